@@ -8,7 +8,9 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -37,6 +39,42 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// nip98Handler runs a handler behind full NIP-98 authentication, copying the
+// authenticated pubkey and firebase_uid from the request context into the Gin context
+func nip98Handler(m *auth.NIP98Middleware, handler gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		m.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			c.Request = r
+			if pubkey, ok := r.Context().Value("pubkey").(string); ok {
+				c.Set("pubkey", pubkey)
+			}
+			if firebaseUID, ok := r.Context().Value("firebase_uid").(string); ok {
+				c.Set("firebase_uid", firebaseUID)
+			}
+			handler(c)
+		})).ServeHTTP(c.Writer, c.Request)
+	}
+}
+
+// optionalNip98Handler verifies a NIP-98 signature when the request carries one, so public
+// handlers can tell the owner apart from anonymous callers. Requests without a Nostr
+// Authorization header are passed through unauthenticated.
+func optionalNip98Handler(m *auth.NIP98Middleware, handler gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !strings.HasPrefix(c.GetHeader("Authorization"), "Nostr ") {
+			handler(c)
+			return
+		}
+		m.SignatureValidationMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			c.Request = r
+			if pubkey, ok := r.Context().Value("pubkey").(string); ok {
+				c.Set("pubkey", pubkey)
+			}
+			handler(c)
+		})).ServeHTTP(c.Writer, c.Request)
+	}
+}
+
 func main() {
 	// Load development configuration
 	devConfig := config.LoadDevConfig()
@@ -67,6 +105,10 @@ func main() {
 	if tempDir == "" {
 		tempDir = "/tmp"
 	}
+	// Media tools run in per-job working directories, so temp paths must be absolute
+	if absTempDir, err := filepath.Abs(tempDir); err == nil {
+		tempDir = absTempDir
+	}
 
 	ctx := context.Background()
 
@@ -182,9 +224,16 @@ func main() {
 
 	// Initialize remaining services
 	pathConfig := utils.GetStoragePathConfig()
-	nostrTrackService := services.NewNostrTrackService(firestoreClient, storageService, pathConfig)
-	audioProcessor := utils.NewAudioProcessor(tempDir)
-	processingService := services.NewProcessingService(storageService, nostrTrackService, audioProcessor, tempDir)
+	processingConfig := config.NewProcessingConfig()
+	nostrTrackService := services.NewNostrTrackService(firestoreClient, storageService, pathConfig, processingConfig.UploadPolicy)
+	nostrArtistService := services.NewNostrArtistService(firestoreClient)
+	nostrAlbumService := services.NewNostrAlbumService(firestoreClient, nostrTrackService)
+	executor := utils.NewExecutor(tempDir, processingConfig.ExecLimits)
+	audioProcessor := utils.NewAudioProcessor(tempDir, executor)
+	fingerprintService := services.NewFingerprintService(firestoreClient)
+	processingService := services.NewProcessingService(storageService, nostrTrackService, audioProcessor, tempDir, processingConfig, fingerprintService, nostrArtistService, nostrAlbumService)
+	imageProcessor := utils.NewImageProcessor(tempDir, executor)
+	artworkService := services.NewArtworkService(firestoreClient, storageService, nostrTrackService, nostrAlbumService, imageProcessor, tempDir)
 
 	// Initialize middleware
 	var firebaseMiddleware *auth.FirebaseMiddleware
@@ -207,11 +256,21 @@ func main() {
 	// Initialize handlers
 	authHandlers := handlers.NewAuthHandlers(userService)
 	tracksHandler := handlers.NewTracksHandler(nostrTrackService, processingService, audioProcessor)
+	artistsHandler := handlers.NewArtistsHandler(nostrArtistService, nostrAlbumService, processingConfig)
+	albumsHandler := handlers.NewAlbumsHandler(nostrAlbumService)
+	artworkHandler := handlers.NewArtworkHandler(artworkService)
+
+	// STREAM_MODE selects how /v1/stream delivers audio: "proxy" (default) or "redirect"
+	streamURLExpiry := time.Duration(getEnvAsInt("STREAM_SIGNED_URL_TTL_SECONDS", 300)) * time.Second
+	streamHandler := handlers.NewStreamHandler(nostrTrackService, storageService, os.Getenv("STREAM_MODE"), streamURLExpiry)
 
 	// Initialize legacy handler if PostgreSQL is available
 	var legacyHandler *handlers.LegacyHandler
+	var legacyMigrationHandler *handlers.LegacyMigrationHandler
 	if postgresService != nil {
 		legacyHandler = handlers.NewLegacyHandler(postgresService)
+		legacyMigrationService := services.NewLegacyMigrationService(firestoreClient, postgresService, storageService, userService)
+		legacyMigrationHandler = handlers.NewLegacyMigrationHandler(legacyMigrationService)
 	}
 
 	// Set up Gin router
@@ -344,7 +403,7 @@ func main() {
 
 	// API Endpoints
 	v1 := router.Group("/v1")
-	
+
 	// Auth endpoints
 	authGroup := v1.Group("/auth")
 	{
@@ -398,26 +457,55 @@ func main() {
 	tracksGroup := v1.Group("/tracks")
 	{
 		// Public endpoints
-		tracksGroup.GET("/:trackId", tracksHandler.GetTrack)
+		tracksGroup.GET("/:trackId", optionalNip98Handler(nip98Middleware, tracksHandler.GetTrack))
 
 		// NIP-98 authenticated endpoints
-		tracksGroup.POST("/nostr", gin.WrapH(nip98Middleware.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			c, _ := gin.CreateTestContext(w)
-			c.Request = r
-			tracksHandler.CreateTrackNostr(c)
-		}))))
+		tracksGroup.POST("/nostr", nip98Handler(nip98Middleware, tracksHandler.CreateTrackNostr))
+		tracksGroup.GET("/my", nip98Handler(nip98Middleware, tracksHandler.GetMyTracks))
+		tracksGroup.DELETE("/:trackId", nip98Handler(nip98Middleware, tracksHandler.DeleteTrack))
+		tracksGroup.GET("/:trackId/download", nip98Handler(nip98Middleware, streamHandler.GetDownloadURL))
+		tracksGroup.GET("/:trackId/upload", nip98Handler(nip98Middleware, tracksHandler.GetUploadStatus))
+		tracksGroup.POST("/:trackId/upload", nip98Handler(nip98Middleware, tracksHandler.RenewUpload))
+	}
 
-		tracksGroup.GET("/my", gin.WrapH(nip98Middleware.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			c, _ := gin.CreateTestContext(w)
-			c.Request = r
-			tracksHandler.GetMyTracks(c)
-		}))))
+	// Audio streaming (public versions for everyone, all versions for the NIP-98 signed owner)
+	v1.GET("/stream/:trackId/:versionId", optionalNip98Handler(nip98Middleware, streamHandler.StreamTrack))
+	v1.GET("/stream/:trackId/:versionId/*file", optionalNip98Handler(nip98Middleware, streamHandler.StreamTrack))
 
-		tracksGroup.DELETE("/:trackId", gin.WrapH(nip98Middleware.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			c, _ := gin.CreateTestContext(w)
-			c.Request = r
-			tracksHandler.DeleteTrack(c)
-		}))))
+	// Artist endpoints
+	artistsGroup := v1.Group("/artists")
+	{
+		// Public endpoints
+		artistsGroup.GET("/:pubkey", artistsHandler.GetArtist)
+		artistsGroup.GET("/:pubkey/albums", artistsHandler.GetArtistAlbums)
+		artistsGroup.GET("/:pubkey/tracks", tracksHandler.GetArtistTracks)
+
+		// NIP-98 authenticated endpoints
+		artistsGroup.POST("", nip98Handler(nip98Middleware, artistsHandler.CreateArtist))
+		artistsGroup.PUT("/:pubkey", nip98Handler(nip98Middleware, artistsHandler.UpdateArtist))
+		artistsGroup.DELETE("/:pubkey", nip98Handler(nip98Middleware, artistsHandler.DeleteArtist))
+	}
+
+	// Album endpoints
+	albumsGroup := v1.Group("/albums")
+	{
+		// Public endpoints
+		albumsGroup.GET("/:albumId", albumsHandler.GetAlbum)
+
+		// NIP-98 authenticated endpoints
+		albumsGroup.GET("/my", nip98Handler(nip98Middleware, albumsHandler.GetMyAlbums))
+		albumsGroup.POST("", nip98Handler(nip98Middleware, albumsHandler.CreateAlbum))
+		albumsGroup.PUT("/:albumId", nip98Handler(nip98Middleware, albumsHandler.UpdateAlbum))
+		albumsGroup.PUT("/:albumId/tracks", nip98Handler(nip98Middleware, albumsHandler.SetAlbumTracks))
+		albumsGroup.DELETE("/:albumId", nip98Handler(nip98Middleware, albumsHandler.DeleteAlbum))
+	}
+
+	// Artwork endpoints (NIP-98 authenticated)
+	artworkGroup := v1.Group("/artwork")
+	{
+		artworkGroup.POST("", nip98Handler(nip98Middleware, artworkHandler.CreateArtworkUpload))
+		artworkGroup.GET("/:artworkId", nip98Handler(nip98Middleware, artworkHandler.GetArtwork))
+		artworkGroup.POST("/:artworkId/process", nip98Handler(nip98Middleware, artworkHandler.ProcessArtwork))
 	}
 
 	// Legacy endpoints (if PostgreSQL is available)
@@ -433,6 +521,11 @@ func main() {
 		log.Println("⚠️  Legacy endpoints not registered - FlexibleAuthMiddleware requires Firebase Auth")
 	}
 
+	// Legacy catalog migration (NIP-98 authenticated, tracks are attributed to the signing pubkey)
+	if legacyMigrationHandler != nil {
+		v1.POST("/legacy/migrate", nip98Handler(nip98Middleware, legacyMigrationHandler.MigrateTracks))
+	}
+
 	// Start server
 	log.Printf("Starting server on port %s", port)
 	if devConfig.IsDevelopment {
@@ -450,6 +543,14 @@ func main() {
 		log.Printf("🏭 Running in PRODUCTION mode")
 	}
 
+	// UPLOAD_REAPER_INTERVAL_SECONDS sets how often tracks whose upload expired are marked
+	// upload_expired; 0 disables the reaper
+	reaperCtx, stopReaper := context.WithCancel(context.Background())
+	defer stopReaper()
+	if reaperInterval := getEnvAsInt("UPLOAD_REAPER_INTERVAL_SECONDS", 900); reaperInterval > 0 {
+		go nostrTrackService.RunUploadReaper(reaperCtx, time.Duration(reaperInterval)*time.Second)
+	}
+
 	go func() {
 		if err := router.Run(":" + port); err != nil {
 			log.Fatalf("Server failed to start: %v", err)
@@ -462,6 +563,7 @@ func main() {
 	<-quit
 
 	log.Println("Shutting down server...")
+	stopReaper()
 
 	// Graceful shutdown with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/wavlake/monorepo/internal/services"
+)
+
+// backfill-object-keys records the storage object keys of nostr_tracks created before they
+// were stored on the track and its compression versions, and the has_public_versions and
+// formats fields of tracks created before they were stored.
+//
+// Usage:
+//
+//	backfill-object-keys [-dry-run] [-json]
+//
+// Requires GOOGLE_CLOUD_PROJECT and GCS_BUCKET_NAME.
+func main() {
+	dryRun := flag.Bool("dry-run", false, "Report what would be backfilled without writing anything")
+	jsonOutput := flag.Bool("json", false, "Print the backfill report as JSON")
+	timeout := flag.Duration("timeout", 30*time.Minute, "Maximum time for the whole backfill")
+	flag.Parse()
+
+	projectID := os.Getenv("GOOGLE_CLOUD_PROJECT")
+	if projectID == "" {
+		log.Fatal("GOOGLE_CLOUD_PROJECT environment variable not set")
+	}
+
+	bucketName := os.Getenv("GCS_BUCKET_NAME")
+	if bucketName == "" {
+		log.Fatal("GCS_BUCKET_NAME environment variable not set")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	firestoreClient, err := firestore.NewClient(ctx, projectID)
+	if err != nil {
+		log.Fatalf("Failed to initialize Firestore: %v", err)
+	}
+	defer firestoreClient.Close()
+
+	storageService, err := services.NewStorageService(ctx, bucketName)
+	if err != nil {
+		log.Fatalf("Failed to initialize GCS storage service: %v", err)
+	}
+	defer storageService.Close()
+
+	backfillService := services.NewObjectKeyBackfillService(firestoreClient, storageService)
+
+	report, err := backfillService.BackfillObjectKeys(ctx, *dryRun)
+	if err != nil {
+		log.Fatalf("Backfill failed: %v", err)
+	}
+
+	if *jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			log.Fatalf("Failed to encode report: %v", err)
+		}
+	} else {
+		for _, result := range report.Results {
+			if result.Status == services.BackfillStatusSkipped {
+				continue
+			}
+			fmt.Printf("%-13s %s original=%q versions=%d version_fields=%t %s\n", result.Status, result.TrackID, result.OriginalObjectKey, result.VersionKeys, result.VersionFields, result.Message)
+		}
+		fmt.Printf("\nupdated: %d, skipped: %d, failed: %d (dry run: %t)\n", report.Updated, report.Skipped, report.Failed, report.DryRun)
+	}
+
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"crypto/md5"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -9,17 +10,26 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/wavlake/monorepo/internal/utils"
 )
 
+// defaultSigningKey is only suitable for local development
+const defaultSigningKey = "wavlake-dev-file-server-key"
+
 type FileServer struct {
 	storagePath string
+	signingKey  []byte
+	sessionsMu  sync.Mutex              // Guards sessions
+	sessions    map[string]*sessionLock // Locks of resumable upload sessions in use
 }
 
-func NewFileServer(storagePath string) *FileServer {
-	return &FileServer{storagePath: storagePath}
+func NewFileServer(storagePath string, signingKey []byte) *FileServer {
+	return &FileServer{storagePath: storagePath, signingKey: signingKey, sessions: make(map[string]*sessionLock)}
 }
 
 // Token validation - simple mock implementation
@@ -115,6 +125,74 @@ func (fs *FileServer) handleDownload(c *gin.Context) {
 	c.File(fullPath)
 }
 
+// handleSigned serves GET/HEAD, accepts PUT and starts resumable uploads on POST for URLs
+// signed by the API, mirroring GCS signed URLs including the response-content-disposition
+// override
+func (fs *FileServer) handleSigned(c *gin.Context) {
+	filePath := strings.TrimPrefix(c.Param("filepath"), "/")
+	if err := utils.VerifyFileServerSignature(fs.signingKey, c.Request.Method, filePath, c.Request.URL.Query(), time.Now()); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	fullPath := filepath.Join(fs.storagePath, filepath.Clean("/"+filePath))
+
+	if c.Request.Method == http.MethodPost {
+		fs.startResumableUpload(c, filePath)
+		return
+	}
+
+	if c.Request.Method == http.MethodPut {
+		var maxLength int64
+		if limit := c.Query(utils.SignedURLMaxLengthParam); limit != "" {
+			maxLength, _ = strconv.ParseInt(limit, 10, 64)
+			if c.Request.ContentLength > maxLength {
+				c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Upload exceeds the signed size limit"})
+				return
+			}
+			// Chunked uploads have no Content-Length, so cap the body as well
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxLength)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create directory"})
+			return
+		}
+		dst, err := os.Create(fullPath)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create file"})
+			return
+		}
+		defer dst.Close()
+
+		size, err := io.Copy(dst, c.Request.Body)
+		if err != nil {
+			dst.Close()
+			os.Remove(fullPath)
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Upload exceeds the signed size limit"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to write file"})
+			return
+		}
+		log.Printf("File uploaded via signed URL: %s (%d bytes)", fullPath, size)
+		c.Status(http.StatusOK)
+		return
+	}
+
+	if _, err := os.Stat(fullPath); os.IsNotExist(err) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "File not found"})
+		return
+	}
+
+	if disposition := c.Query(utils.SignedURLDispositionParam); disposition != "" {
+		c.Header("Content-Disposition", disposition)
+	}
+	c.File(fullPath)
+}
+
 func (fs *FileServer) handleList(c *gin.Context) {
 	// List files in storage directory
 	prefix := c.Query("prefix")
@@ -190,7 +268,12 @@ func main() {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
-	fs := NewFileServer(storagePath)
+	signingKey := os.Getenv("FILE_SERVER_SIGNING_KEY")
+	if signingKey == "" {
+		signingKey = defaultSigningKey
+	}
+
+	fs := NewFileServer(storagePath, []byte(signingKey))
 	router := gin.New()
 	router.Use(gin.Logger())
 	router.Use(gin.Recovery())
@@ -202,6 +285,15 @@ func main() {
 	// Download endpoint
 	router.GET("/file/*filepath", fs.handleDownload)
 
+	// Signed URL endpoints (equivalent of GCS signed URLs)
+	router.GET("/signed/*filepath", fs.handleSigned)
+	router.HEAD("/signed/*filepath", fs.handleSigned)
+	router.PUT("/signed/*filepath", fs.handleSigned)
+	router.POST("/signed/*filepath", fs.handleSigned)
+
+	// Resumable upload sessions started through signed POST URLs
+	router.PUT("/resumable/:sessionId", fs.handleResumable)
+
 	// Admin endpoints
 	router.GET("/status", fs.handleStatus)
 	router.GET("/list", fs.handleList)
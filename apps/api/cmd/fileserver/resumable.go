@@ -0,0 +1,291 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wavlake/monorepo/internal/utils"
+)
+
+// resumableSessionTTL matches how long GCS keeps resumable upload sessions
+const resumableSessionTTL = 7 * 24 * time.Hour
+
+// resumableDir holds session state and partial uploads, under the storage path
+const resumableDir = ".resumable"
+
+var sessionIDPattern = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+// uploadSession is the persisted state of a resumable upload, so uploads survive restarts
+type uploadSession struct {
+	Path      string    `json:"path"`                 // Object path the upload is stored at when complete
+	MaxLength int64     `json:"max_length,omitempty"` // Signed upload size limit, 0 for none
+	Total     int64     `json:"total"`                // Total size, -1 until the client sends it
+	Offset    int64     `json:"offset"`               // Bytes committed
+	Complete  bool      `json:"complete,omitempty"`   // Kept after completion so status checks succeed
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// startResumableUpload opens a session for a signed POST with "x-goog-resumable: start" and
+// answers with its URL in Location, as GCS does
+func (fs *FileServer) startResumableUpload(c *gin.Context, filePath string) {
+	if c.GetHeader(utils.ResumableHeader) != utils.ResumableStart {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "POST requires " + utils.ResumableHeader + ": " + utils.ResumableStart})
+		return
+	}
+
+	session := &uploadSession{
+		Path:      filePath,
+		Total:     -1,
+		ExpiresAt: time.Now().Add(resumableSessionTTL),
+	}
+	if limit := c.Query(utils.SignedURLMaxLengthParam); limit != "" {
+		session.MaxLength, _ = strconv.ParseInt(limit, 10, 64)
+	}
+
+	idBytes := make([]byte, 16)
+	if _, err := rand.Read(idBytes); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session"})
+		return
+	}
+	id := hex.EncodeToString(idBytes)
+
+	if err := os.MkdirAll(filepath.Join(fs.storagePath, resumableDir), 0755); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create directory"})
+		return
+	}
+	if err := os.WriteFile(fs.partPath(id), nil, 0644); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session"})
+		return
+	}
+	if err := fs.saveSession(id, session); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session"})
+		return
+	}
+
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	c.Header("Location", fmt.Sprintf("%s://%s/resumable/%s", scheme, c.Request.Host, id))
+	log.Printf("Started resumable upload %s for %s", id, filePath)
+	c.Status(http.StatusCreated)
+}
+
+// handleResumable accepts chunks of a resumable upload. "Content-Range: bytes START-END/TOTAL"
+// appends a chunk (TOTAL may be "*" until the last one), "bytes */TOTAL" reports the committed
+// offset, and no Content-Range uploads the whole object at once. Incomplete uploads are
+// answered with 308 and a Range header of the committed bytes.
+func (fs *FileServer) handleResumable(c *gin.Context) {
+	id := c.Param("sessionId")
+	if !sessionIDPattern.MatchString(id) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Upload session not found"})
+		return
+	}
+
+	unlock := fs.lockSession(id)
+	defer unlock()
+
+	session, err := fs.loadSession(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Upload session not found"})
+		return
+	}
+	if time.Now().After(session.ExpiresAt) {
+		fs.removeSession(id)
+		c.JSON(http.StatusNotFound, gin.H{"error": "Upload session not found"})
+		return
+	}
+	if session.Complete {
+		c.JSON(http.StatusOK, gin.H{"name": session.Path, "size": session.Total, "bucket": "mock-bucket"})
+		return
+	}
+
+	chunk := utils.ContentRange{Start: 0, End: c.Request.ContentLength - 1, Total: c.Request.ContentLength}
+	if header := c.GetHeader("Content-Range"); header != "" {
+		if chunk, err = utils.ParseContentRange(header); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	} else if c.Request.ContentLength < 0 {
+		c.JSON(http.StatusLengthRequired, gin.H{"error": "Content-Length or Content-Range is required"})
+		return
+	}
+
+	if chunk.Total >= 0 {
+		if session.Total >= 0 && session.Total != chunk.Total {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Total size does not match earlier chunks"})
+			return
+		}
+		session.Total = chunk.Total
+	}
+	if session.MaxLength > 0 && session.Total > session.MaxLength {
+		fs.removeSession(id)
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Upload exceeds the signed size limit"})
+		return
+	}
+
+	if chunk.Start >= 0 && chunk.Start <= session.Offset {
+		written, err := fs.appendChunk(id, session, chunk, c.Request.Body)
+		session.Offset += written
+		if session.MaxLength > 0 && session.Offset > session.MaxLength {
+			fs.removeSession(id)
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Upload exceeds the signed size limit"})
+			return
+		}
+		if err != nil {
+			// Keep what was committed so the client can resume after an interrupted chunk
+			log.Printf("Resumable upload %s interrupted at %d bytes: %v", id, session.Offset, err)
+		}
+	}
+
+	if session.Total >= 0 && session.Offset >= session.Total {
+		fs.completeSession(c, id, session)
+		return
+	}
+
+	if err := fs.saveSession(id, session); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save session"})
+		return
+	}
+	if committed := utils.FormatCommittedRange(session.Offset); committed != "" {
+		c.Header("Range", committed)
+	}
+	c.Status(utils.StatusResumeIncomplete)
+}
+
+// sessionLock serializes the requests of one upload session
+type sessionLock struct {
+	mu   sync.Mutex
+	refs int // Requests holding or waiting for mu
+}
+
+// lockSession locks one upload session and returns its unlock function. sessionsMu only
+// guards the lock table, so chunks of different sessions are written concurrently.
+func (fs *FileServer) lockSession(id string) (unlock func()) {
+	fs.sessionsMu.Lock()
+	lock := fs.sessions[id]
+	if lock == nil {
+		lock = &sessionLock{}
+		fs.sessions[id] = lock
+	}
+	lock.refs++
+	fs.sessionsMu.Unlock()
+
+	lock.mu.Lock()
+	return func() {
+		lock.mu.Unlock()
+
+		fs.sessionsMu.Lock()
+		lock.refs--
+		if lock.refs == 0 {
+			delete(fs.sessions, id)
+		}
+		fs.sessionsMu.Unlock()
+	}
+}
+
+// appendChunk writes the part of a chunk past the committed offset and returns how many
+// bytes were written. Bytes the session already has are skipped, so clients can resend.
+func (fs *FileServer) appendChunk(id string, session *uploadSession, chunk utils.ContentRange, body io.Reader) (int64, error) {
+	if _, err := io.CopyN(io.Discard, body, session.Offset-chunk.Start); err != nil {
+		return 0, err
+	}
+
+	want := chunk.End + 1 - session.Offset
+	if session.MaxLength > 0 {
+		// Read one byte past the limit so oversized uploads are detected
+		want = min(want, session.MaxLength-session.Offset+1)
+	}
+	if want <= 0 {
+		return 0, nil
+	}
+
+	part, err := os.OpenFile(fs.partPath(id), os.O_WRONLY, 0644)
+	if err != nil {
+		return 0, err
+	}
+	defer part.Close()
+	if _, err := part.Seek(session.Offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	written, err := io.CopyN(part, body, want)
+	if errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+	return written, err
+}
+
+// completeSession moves a finished upload into place and answers as GCS does
+func (fs *FileServer) completeSession(c *gin.Context, id string, session *uploadSession) {
+	fullPath := filepath.Join(fs.storagePath, filepath.Clean("/"+session.Path))
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create directory"})
+		return
+	}
+	if err := os.Truncate(fs.partPath(id), session.Total); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to write file"})
+		return
+	}
+	if err := os.Rename(fs.partPath(id), fullPath); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to write file"})
+		return
+	}
+	session.Complete = true
+	if err := fs.saveSession(id, session); err != nil {
+		log.Printf("Failed to record completion of resumable upload %s: %v", id, err)
+	}
+
+	log.Printf("File uploaded via resumable session %s: %s (%d bytes)", id, fullPath, session.Total)
+	c.JSON(http.StatusOK, gin.H{
+		"name":   session.Path,
+		"size":   session.Total,
+		"bucket": "mock-bucket",
+	})
+}
+
+func (fs *FileServer) sessionPath(id string) string {
+	return filepath.Join(fs.storagePath, resumableDir, id+".json")
+}
+
+func (fs *FileServer) partPath(id string) string {
+	return filepath.Join(fs.storagePath, resumableDir, id+".part")
+}
+
+func (fs *FileServer) loadSession(id string) (*uploadSession, error) {
+	data, err := os.ReadFile(fs.sessionPath(id))
+	if err != nil {
+		return nil, err
+	}
+	var session uploadSession
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (fs *FileServer) saveSession(id string, session *uploadSession) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(fs.sessionPath(id), data, 0644)
+}
+
+func (fs *FileServer) removeSession(id string) {
+	os.Remove(fs.sessionPath(id))
+	os.Remove(fs.partPath(id))
+}
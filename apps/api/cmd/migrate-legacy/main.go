@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	_ "github.com/lib/pq" // PostgreSQL driver
+	"github.com/wavlake/monorepo/internal/services"
+)
+
+// migrate-legacy copies a user's tracks from the legacy PostgreSQL catalog into
+// nostr_tracks, attributed to one of the user's linked pubkeys.
+//
+// Usage:
+//
+//	migrate-legacy -firebase-uid <uid> -pubkey <hex pubkey> [-dry-run] [-json]
+//
+// Requires GOOGLE_CLOUD_PROJECT, GCS_BUCKET_NAME and PROD_POSTGRES_CONNECTION_STRING_RO.
+func main() {
+	firebaseUID := flag.String("firebase-uid", "", "Firebase UID of the legacy catalog owner")
+	pubkey := flag.String("pubkey", "", "Linked Nostr pubkey the migrated tracks are attributed to")
+	dryRun := flag.Bool("dry-run", false, "Report what would be migrated without writing anything")
+	jsonOutput := flag.Bool("json", false, "Print the migration report as JSON")
+	timeout := flag.Duration("timeout", 30*time.Minute, "Maximum time for the whole migration")
+	flag.Parse()
+
+	if *firebaseUID == "" || *pubkey == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	projectID := os.Getenv("GOOGLE_CLOUD_PROJECT")
+	if projectID == "" {
+		log.Fatal("GOOGLE_CLOUD_PROJECT environment variable not set")
+	}
+
+	bucketName := os.Getenv("GCS_BUCKET_NAME")
+	if bucketName == "" {
+		log.Fatal("GCS_BUCKET_NAME environment variable not set")
+	}
+
+	pgConnStr := os.Getenv("PROD_POSTGRES_CONNECTION_STRING_RO")
+	if pgConnStr == "" {
+		log.Fatal("PROD_POSTGRES_CONNECTION_STRING_RO environment variable not set")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	firestoreClient, err := firestore.NewClient(ctx, projectID)
+	if err != nil {
+		log.Fatalf("Failed to initialize Firestore: %v", err)
+	}
+	defer firestoreClient.Close()
+
+	storageService, err := services.NewStorageService(ctx, bucketName)
+	if err != nil {
+		log.Fatalf("Failed to initialize GCS storage service: %v", err)
+	}
+	defer storageService.Close()
+
+	db, err := sql.Open("postgres", pgConnStr)
+	if err != nil {
+		log.Fatalf("Failed to open PostgreSQL connection: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.PingContext(ctx); err != nil {
+		log.Fatalf("PostgreSQL connection test failed: %v", err)
+	}
+
+	migrationService := services.NewLegacyMigrationService(
+		firestoreClient,
+		services.NewPostgresService(db),
+		storageService,
+		services.NewUserService(firestoreClient, nil), // Only the Firestore-backed pubkey lookup is needed
+	)
+
+	report, err := migrationService.MigrateUserTracks(ctx, *firebaseUID, *pubkey, *dryRun)
+	if err != nil {
+		log.Fatalf("Migration failed: %v", err)
+	}
+
+	if *jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			log.Fatalf("Failed to encode report: %v", err)
+		}
+	} else {
+		for _, result := range report.Results {
+			fmt.Printf("%-14s %s %q %s %s\n", result.Status, result.LegacyTrackID, result.Title, result.TrackID, result.Message)
+		}
+		fmt.Printf("\nmigrated: %d, skipped: %d, failed: %d (dry run: %t)\n", report.Migrated, report.Skipped, report.Failed, report.DryRun)
+	}
+
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/wavlake/monorepo/internal/services"
+	"github.com/wavlake/monorepo/internal/utils"
+)
+
+// purge-deleted-tracks hard deletes nostr_tracks that were soft deleted longer than the
+// retention period ago, together with every file stored for them. It is meant to run on a
+// schedule; tracks that fail are retried by the next run.
+//
+// Usage:
+//
+//	purge-deleted-tracks [-retention 720h] [-dry-run] [-json]
+//
+// Requires GOOGLE_CLOUD_PROJECT and GCS_BUCKET_NAME.
+func main() {
+	retention := flag.Duration("retention", services.DefaultPurgeRetention, "How long soft-deleted tracks are kept before they are purged")
+	dryRun := flag.Bool("dry-run", false, "Report what would be purged without deleting anything")
+	jsonOutput := flag.Bool("json", false, "Print the purge report as JSON")
+	timeout := flag.Duration("timeout", 30*time.Minute, "Maximum time for the whole purge")
+	flag.Parse()
+
+	if *retention <= 0 {
+		log.Fatal("-retention must be positive")
+	}
+
+	projectID := os.Getenv("GOOGLE_CLOUD_PROJECT")
+	if projectID == "" {
+		log.Fatal("GOOGLE_CLOUD_PROJECT environment variable not set")
+	}
+
+	bucketName := os.Getenv("GCS_BUCKET_NAME")
+	if bucketName == "" {
+		log.Fatal("GCS_BUCKET_NAME environment variable not set")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	firestoreClient, err := firestore.NewClient(ctx, projectID)
+	if err != nil {
+		log.Fatalf("Failed to initialize Firestore: %v", err)
+	}
+	defer firestoreClient.Close()
+
+	storageService, err := services.NewStorageService(ctx, bucketName)
+	if err != nil {
+		log.Fatalf("Failed to initialize GCS storage service: %v", err)
+	}
+	defer storageService.Close()
+
+	trackService := services.NewNostrTrackService(firestoreClient, storageService, utils.GetStoragePathConfig(), nil)
+
+	report, err := trackService.PurgeDeletedTracks(ctx, *retention, *dryRun)
+	if err != nil {
+		log.Fatalf("Purge failed: %v", err)
+	}
+
+	if *jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			log.Fatalf("Failed to encode report: %v", err)
+		}
+	} else {
+		for _, result := range report.Results {
+			fmt.Printf("%-11s %s deleted=%s %s %s\n", result.Status, result.TrackID, result.DeletedAt.Format(time.RFC3339), result.Message, strings.Join(result.FailedFiles, ", "))
+		}
+		fmt.Printf("\npurged: %d, failed: %d (deleted before %s, dry run: %t)\n", report.Purged, report.Failed, report.Cutoff.Format(time.RFC3339), report.DryRun)
+	}
+
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+}
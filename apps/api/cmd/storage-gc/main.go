@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/wavlake/monorepo/internal/services"
+)
+
+// storage-gc deletes track files under tracks/original and tracks/compressed that no
+// nostr_tracks record references, and track records whose original was never uploaded, once
+// they are older than the grace period.
+//
+// Usage:
+//
+//	storage-gc [-grace-period 192h] [-dry-run] [-json]
+//
+// Requires GOOGLE_CLOUD_PROJECT and GCS_BUCKET_NAME.
+func main() {
+	gracePeriod := flag.Duration("grace-period", services.DefaultGCGracePeriod, "Minimum age of orphans and abandoned tracks before they are deleted")
+	dryRun := flag.Bool("dry-run", false, "Report orphans and abandoned tracks without deleting anything")
+	jsonOutput := flag.Bool("json", false, "Print the GC report as JSON")
+	timeout := flag.Duration("timeout", 60*time.Minute, "Maximum time for the whole run")
+	flag.Parse()
+
+	if *gracePeriod < time.Hour {
+		log.Fatal("-grace-period must be at least 1h")
+	}
+
+	projectID := os.Getenv("GOOGLE_CLOUD_PROJECT")
+	if projectID == "" {
+		log.Fatal("GOOGLE_CLOUD_PROJECT environment variable not set")
+	}
+
+	bucketName := os.Getenv("GCS_BUCKET_NAME")
+	if bucketName == "" {
+		log.Fatal("GCS_BUCKET_NAME environment variable not set")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	firestoreClient, err := firestore.NewClient(ctx, projectID)
+	if err != nil {
+		log.Fatalf("Failed to initialize Firestore: %v", err)
+	}
+	defer firestoreClient.Close()
+
+	storageService, err := services.NewStorageService(ctx, bucketName)
+	if err != nil {
+		log.Fatalf("Failed to initialize GCS storage service: %v", err)
+	}
+	defer storageService.Close()
+
+	gcService := services.NewStorageGCService(firestoreClient, storageService)
+
+	report, err := gcService.CollectGarbage(ctx, *gracePeriod, *dryRun)
+	if err != nil {
+		log.Fatalf("Storage GC failed: %v", err)
+	}
+
+	if *jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			log.Fatalf("Failed to encode report: %v", err)
+		}
+	} else {
+		for _, orphan := range report.Orphans {
+			fmt.Printf("%-15s object %s created=%s size=%d %s\n", orphan.Status, orphan.ObjectName, orphan.CreatedAt.Format(time.RFC3339), orphan.Size, orphan.Message)
+		}
+		for _, track := range report.AbandonedTracks {
+			fmt.Printf("%-15s track  %s created=%s pubkey=%s %s\n", track.Status, track.TrackID, track.CreatedAt.Format(time.RFC3339), track.Pubkey, track.Message)
+		}
+		fmt.Printf("\ntracks: %d, objects: %d, orphans: %d, abandoned tracks: %d, deleted: %d, failed: %d (older than %s, dry run: %t)\n",
+			report.TracksScanned, report.ObjectsScanned, len(report.Orphans), len(report.AbandonedTracks), report.Deleted, report.Failed, report.Cutoff.Format(time.RFC3339), report.DryRun)
+	}
+
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+}
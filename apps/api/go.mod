@@ -18,6 +18,7 @@ require (
 	github.com/onsi/gomega v1.38.0
 	github.com/stretchr/testify v1.10.0
 	google.golang.org/api v0.239.0
+	google.golang.org/grpc v1.73.0
 )
 
 require (
@@ -108,7 +109,6 @@ require (
 	google.golang.org/genproto v0.0.0-20250603155806-513f23925822 // indirect
 	google.golang.org/genproto/googleapis/api v0.0.0-20250603155806-513f23925822 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20250715232539-7130f93afb79 // indirect
-	google.golang.org/grpc v1.73.0 // indirect
 	google.golang.org/protobuf v1.36.6 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
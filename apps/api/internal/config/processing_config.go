@@ -0,0 +1,192 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wavlake/monorepo/internal/models"
+	"github.com/wavlake/monorepo/internal/utils"
+)
+
+// DefaultFingerprintMatchThreshold is the similarity at which two fingerprints are treated as
+// the same recording. Unrelated audio scores around 0.5.
+const DefaultFingerprintMatchThreshold = 0.85
+
+// DefaultMinFreeDiskBytes is the free space kept in reserve beyond what a processing job needs
+const DefaultMinFreeDiskBytes = 512 << 20
+
+// DefaultTrackURLTemplate is the public track page linked from output tags
+const DefaultTrackURLTemplate = "https://wavlake.com/track/%s"
+
+// Duplicate upload policies
+const (
+	// DuplicatePolicyFlag records the matching track on the new upload and keeps processing
+	DuplicatePolicyFlag = "flag"
+	// DuplicatePolicyReject fails processing of uploads matching another pubkey's track
+	DuplicatePolicyReject = "reject"
+)
+
+// Built-in compression presets
+const (
+	PresetStreamLow  = "stream-low"
+	PresetStreamHigh = "stream-high"
+	PresetLossless   = "lossless"
+)
+
+// DefaultCompressionLadder is the presets generated on upload for artists without their own ladder
+var DefaultCompressionLadder = []string{PresetStreamLow, PresetStreamHigh}
+
+// DefaultCompressionPresets returns the built-in presets. COMPRESSION_PRESETS can redefine
+// them or add more.
+func DefaultCompressionPresets() map[string]models.CompressionOption {
+	return map[string]models.CompressionOption{
+		PresetStreamLow: {
+			Format:     "mp3",
+			Bitrate:    128,
+			Quality:    "medium",
+			SampleRate: 44100,
+		},
+		PresetStreamHigh: {
+			Format:     "mp3",
+			Bitrate:    320,
+			Quality:    "high",
+			SampleRate: 44100,
+		},
+		PresetLossless: {
+			Format: "flac",
+		},
+	}
+}
+
+// ProcessingConfig holds audio processing pipeline configuration
+type ProcessingConfig struct {
+	// DuplicatePolicy decides what happens when an upload's SHA-256 matches a track owned by
+	// another pubkey: DuplicatePolicyFlag or DuplicatePolicyReject
+	DuplicatePolicy string
+
+	// FingerprintEnabled turns on Chromaprint fingerprinting of originals (requires fpcalc)
+	FingerprintEnabled bool
+
+	// FingerprintMatchThreshold is the minimum similarity (0-1) that queues a match for moderation
+	FingerprintMatchThreshold float64
+
+	// TrackURLTemplate builds the public track URL embedded in output tags; %s is the track ID
+	TrackURLTemplate string
+
+	// Presets are the named compression options artists and callers can request
+	Presets map[string]models.CompressionOption
+
+	// DefaultLadder is the preset names generated on upload when the artist has no ladder
+	DefaultLadder []string
+
+	// UploadPolicy limits the originals accepted for processing
+	UploadPolicy *UploadPolicy
+
+	// ExecLimits bounds each ffmpeg, ffprobe and fpcalc run on uploaded files
+	ExecLimits utils.ExecLimits
+
+	// MinFreeDiskBytes is the free space that must remain in the temp directory after a job's
+	// estimated needs; jobs that would dip below it are refused before downloading
+	MinFreeDiskBytes int64
+}
+
+// NewProcessingConfig creates a new processing configuration from environment
+func NewProcessingConfig() *ProcessingConfig {
+	policy := strings.ToLower(strings.TrimSpace(os.Getenv("DUPLICATE_UPLOAD_POLICY")))
+	if policy != DuplicatePolicyReject {
+		policy = DuplicatePolicyFlag
+	}
+
+	threshold, err := strconv.ParseFloat(os.Getenv("FINGERPRINT_MATCH_THRESHOLD"), 64)
+	if err != nil || threshold <= 0 || threshold > 1 {
+		threshold = DefaultFingerprintMatchThreshold
+	}
+
+	trackURLTemplate := os.Getenv("TRACK_URL_TEMPLATE")
+	if !strings.Contains(trackURLTemplate, "%s") {
+		trackURLTemplate = DefaultTrackURLTemplate
+	}
+
+	presets := loadCompressionPresets(os.Getenv("COMPRESSION_PRESETS"))
+
+	var ladder []string
+	for _, name := range strings.Split(os.Getenv("DEFAULT_COMPRESSION_LADDER"), ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		if _, ok := presets[name]; !ok {
+			log.Printf("Ignoring unknown preset %q in DEFAULT_COMPRESSION_LADDER", name)
+			continue
+		}
+		ladder = append(ladder, name)
+	}
+	if len(ladder) == 0 {
+		ladder = append(ladder, DefaultCompressionLadder...)
+	}
+
+	return &ProcessingConfig{
+		DuplicatePolicy:           policy,
+		FingerprintEnabled:        os.Getenv("FINGERPRINT_ENABLED") == "true",
+		FingerprintMatchThreshold: threshold,
+		TrackURLTemplate:          trackURLTemplate,
+		Presets:                   presets,
+		DefaultLadder:             ladder,
+		UploadPolicy:              NewUploadPolicy(),
+		ExecLimits:                newExecLimits(),
+		MinFreeDiskBytes:          int64(envPositiveInt("PROCESSING_MIN_FREE_DISK_BYTES", DefaultMinFreeDiskBytes)),
+	}
+}
+
+// newExecLimits reads the media tool sandbox limits from environment
+func newExecLimits() utils.ExecLimits {
+	limits := utils.DefaultExecLimits()
+	limits.CPUSeconds = uint64(envPositiveInt("SANDBOX_CPU_SECONDS", int(limits.CPUSeconds)))
+	limits.MemoryBytes = uint64(envPositiveInt("SANDBOX_MEMORY_BYTES", int(limits.MemoryBytes)))
+	limits.FileSizeBytes = uint64(envPositiveInt("SANDBOX_FILE_SIZE_BYTES", int(limits.FileSizeBytes)))
+	limits.Timeout = time.Duration(envPositiveInt("SANDBOX_TIMEOUT_SECONDS", int(limits.Timeout/time.Second))) * time.Second
+	limits.MaxStderrBytes = envPositiveInt("SANDBOX_MAX_STDERR_BYTES", limits.MaxStderrBytes)
+	return limits
+}
+
+// ResolvePresets returns the compression options for preset names, tagged with their name
+func (c *ProcessingConfig) ResolvePresets(names []string) ([]models.CompressionOption, error) {
+	options := make([]models.CompressionOption, 0, len(names))
+	for _, name := range names {
+		option, ok := c.Presets[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown compression preset: %s", name)
+		}
+		option.Preset = name
+		options = append(options, option)
+	}
+	return options, nil
+}
+
+// loadCompressionPresets merges a JSON object of preset name to CompressionOption over the
+// built-in presets. Invalid presets are logged and skipped.
+func loadCompressionPresets(raw string) map[string]models.CompressionOption {
+	presets := DefaultCompressionPresets()
+	if strings.TrimSpace(raw) == "" {
+		return presets
+	}
+
+	var overrides map[string]models.CompressionOption
+	if err := json.Unmarshal([]byte(raw), &overrides); err != nil {
+		log.Printf("Ignoring invalid COMPRESSION_PRESETS: %v", err)
+		return presets
+	}
+
+	for name, option := range overrides {
+		if err := utils.ValidateCompressionOption(option); err != nil {
+			log.Printf("Ignoring compression preset %q: %v", name, err)
+			continue
+		}
+		presets[name] = option
+	}
+	return presets
+}
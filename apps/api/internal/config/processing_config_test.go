@@ -0,0 +1,98 @@
+package config_test
+
+import (
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/wavlake/monorepo/internal/config"
+)
+
+var _ = Describe("ProcessingConfig", func() {
+	const envKey = "DUPLICATE_UPLOAD_POLICY"
+	envKeys := []string{envKey, "FINGERPRINT_ENABLED", "FINGERPRINT_MATCH_THRESHOLD", "COMPRESSION_PRESETS", "DEFAULT_COMPRESSION_LADDER"}
+	originalEnvValues := make(map[string]string)
+
+	BeforeEach(func() {
+		for _, key := range envKeys {
+			originalEnvValues[key] = os.Getenv(key)
+		}
+	})
+
+	AfterEach(func() {
+		for _, key := range envKeys {
+			if originalEnvValues[key] != "" {
+				os.Setenv(key, originalEnvValues[key])
+			} else {
+				os.Unsetenv(key)
+			}
+		}
+	})
+
+	Describe("NewProcessingConfig", func() {
+		It("should flag duplicates by default", func() {
+			os.Unsetenv(envKey)
+			Expect(config.NewProcessingConfig().DuplicatePolicy).To(Equal(config.DuplicatePolicyFlag))
+		})
+
+		It("should reject duplicates when configured", func() {
+			os.Setenv(envKey, " Reject ")
+			Expect(config.NewProcessingConfig().DuplicatePolicy).To(Equal(config.DuplicatePolicyReject))
+		})
+
+		It("should fall back to flagging for unknown policies", func() {
+			os.Setenv(envKey, "ignore")
+			Expect(config.NewProcessingConfig().DuplicatePolicy).To(Equal(config.DuplicatePolicyFlag))
+		})
+
+		It("should leave fingerprinting off unless enabled", func() {
+			os.Unsetenv("FINGERPRINT_ENABLED")
+			os.Unsetenv("FINGERPRINT_MATCH_THRESHOLD")
+			cfg := config.NewProcessingConfig()
+			Expect(cfg.FingerprintEnabled).To(BeFalse())
+			Expect(cfg.FingerprintMatchThreshold).To(Equal(config.DefaultFingerprintMatchThreshold))
+
+			os.Setenv("FINGERPRINT_ENABLED", "true")
+			os.Setenv("FINGERPRINT_MATCH_THRESHOLD", "0.9")
+			cfg = config.NewProcessingConfig()
+			Expect(cfg.FingerprintEnabled).To(BeTrue())
+			Expect(cfg.FingerprintMatchThreshold).To(Equal(0.9))
+		})
+
+		It("should provide the built-in presets and ladder by default", func() {
+			os.Unsetenv("COMPRESSION_PRESETS")
+			os.Unsetenv("DEFAULT_COMPRESSION_LADDER")
+			cfg := config.NewProcessingConfig()
+			Expect(cfg.Presets).To(HaveKey(config.PresetStreamLow))
+			Expect(cfg.Presets).To(HaveKey(config.PresetStreamHigh))
+			Expect(cfg.Presets[config.PresetLossless].Format).To(Equal("flac"))
+			Expect(cfg.DefaultLadder).To(Equal(config.DefaultCompressionLadder))
+		})
+
+		It("should merge valid presets from the environment", func() {
+			os.Setenv("COMPRESSION_PRESETS", `{"stream-opus":{"format":"opus","bitrate":96},"broken":{"format":"mp3","bitrate":1000}}`)
+			os.Setenv("DEFAULT_COMPRESSION_LADDER", "stream-opus, lossless, missing")
+			cfg := config.NewProcessingConfig()
+			Expect(cfg.Presets["stream-opus"].Format).To(Equal("opus"))
+			Expect(cfg.Presets).NotTo(HaveKey("broken"))
+			Expect(cfg.Presets).To(HaveKey(config.PresetStreamLow))
+			Expect(cfg.DefaultLadder).To(Equal([]string{"stream-opus", config.PresetLossless}))
+		})
+	})
+
+	Describe("ResolvePresets", func() {
+		It("should return the options tagged with their preset name", func() {
+			options, err := config.NewProcessingConfig().ResolvePresets([]string{config.PresetLossless})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(options).To(HaveLen(1))
+			Expect(options[0].Format).To(Equal("flac"))
+			Expect(options[0].Preset).To(Equal(config.PresetLossless))
+		})
+
+		It("should reject unknown presets", func() {
+			_, err := config.NewProcessingConfig().ResolvePresets([]string{"studio"})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
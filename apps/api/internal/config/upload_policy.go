@@ -0,0 +1,127 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/wavlake/monorepo/internal/utils"
+)
+
+// Upload policy defaults
+const (
+	DefaultUploadMaxBytes           = 500 * 1024 * 1024 // 500 MiB
+	DefaultUploadMinDurationSeconds = 1
+	DefaultUploadMaxDurationSeconds = 4 * 60 * 60 // 4 hours
+	DefaultUploadMinSampleRate      = 22050
+)
+
+// DefaultUploadCodecs are the ffprobe audio codec names accepted for originals
+var DefaultUploadCodecs = []string{
+	"mp3", "aac", "alac", "flac", "vorbis", "opus",
+	"pcm_s16le", "pcm_s24le", "pcm_s32le", "pcm_f32le",
+	"pcm_s16be", "pcm_s24be", "pcm_s32be", "pcm_f32be",
+}
+
+// DefaultUploadContainers are the ffprobe container names accepted for originals. ffprobe
+// reports MP4 and M4A as "mov,mp4,m4a,3gp,3g2,mj2"; any one name matching is enough.
+var DefaultUploadContainers = []string{"mp3", "wav", "flac", "ogg", "mp4", "m4a", "aiff"}
+
+// UploadPolicy limits what can be uploaded as a track original
+type UploadPolicy struct {
+	MaxBytes           int64    // Largest accepted original
+	MinDurationSeconds int      // Shortest accepted duration
+	MaxDurationSeconds int      // Longest accepted duration
+	MinSampleRate      int      // Lowest accepted sample rate in Hz
+	AllowedCodecs      []string // ffprobe audio codec names
+	AllowedContainers  []string // ffprobe container names
+}
+
+// NewUploadPolicy creates the upload policy from environment
+func NewUploadPolicy() *UploadPolicy {
+	return &UploadPolicy{
+		MaxBytes:           int64(envPositiveInt("UPLOAD_MAX_BYTES", DefaultUploadMaxBytes)),
+		MinDurationSeconds: envPositiveInt("UPLOAD_MIN_DURATION_SECONDS", DefaultUploadMinDurationSeconds),
+		MaxDurationSeconds: envPositiveInt("UPLOAD_MAX_DURATION_SECONDS", DefaultUploadMaxDurationSeconds),
+		MinSampleRate:      envPositiveInt("UPLOAD_MIN_SAMPLE_RATE", DefaultUploadMinSampleRate),
+		AllowedCodecs:      envList("UPLOAD_ALLOWED_CODECS", DefaultUploadCodecs),
+		AllowedContainers:  envList("UPLOAD_ALLOWED_CONTAINERS", DefaultUploadContainers),
+	}
+}
+
+// Check returns every way a probed original breaks the policy, or nil when it is accepted.
+// size is the uploaded file's size in bytes.
+func (p *UploadPolicy) Check(probe *utils.ProbeResult, size int64) []string {
+	var reasons []string
+	if size > p.MaxBytes {
+		reasons = append(reasons, fmt.Sprintf("file is %d bytes, larger than the %d byte limit", size, p.MaxBytes))
+	}
+
+	if !p.containerAllowed(probe.Format.Name) {
+		reasons = append(reasons, fmt.Sprintf("container %q is not accepted", probe.Format.Name))
+	}
+
+	stream := probe.AudioStream()
+	if stream == nil {
+		return append(reasons, "file does not contain an audio stream")
+	}
+	if reason := utils.DetectDRM(probe); reason != "" {
+		reasons = append(reasons, reason)
+	} else if !slices.Contains(p.AllowedCodecs, stream.CodecName) {
+		reasons = append(reasons, fmt.Sprintf("codec %q is not accepted", stream.CodecName))
+	}
+
+	if stream.SampleRate < p.MinSampleRate {
+		reasons = append(reasons, fmt.Sprintf("sample rate %d Hz is below the %d Hz minimum", stream.SampleRate, p.MinSampleRate))
+	}
+
+	durationMs := probe.Format.DurationMs
+	if durationMs == 0 {
+		durationMs = stream.DurationMs
+	}
+	switch {
+	case durationMs == 0:
+		reasons = append(reasons, "duration could not be read; the file may be corrupted")
+	case durationMs < int64(p.MinDurationSeconds)*1000:
+		reasons = append(reasons, fmt.Sprintf("duration %.1fs is shorter than the %ds minimum", float64(durationMs)/1000, p.MinDurationSeconds))
+	case durationMs > int64(p.MaxDurationSeconds)*1000:
+		reasons = append(reasons, fmt.Sprintf("duration %.1fs is longer than the %ds maximum", float64(durationMs)/1000, p.MaxDurationSeconds))
+	}
+
+	return reasons
+}
+
+// containerAllowed reports whether any of ffprobe's comma-separated container names is allowed
+func (p *UploadPolicy) containerAllowed(formatName string) bool {
+	for _, name := range strings.Split(formatName, ",") {
+		if slices.Contains(p.AllowedContainers, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// envPositiveInt reads a positive integer from the environment, falling back to def
+func envPositiveInt(key string, def int) int {
+	value, err := strconv.Atoi(strings.TrimSpace(os.Getenv(key)))
+	if err != nil || value <= 0 {
+		return def
+	}
+	return value
+}
+
+// envList reads a comma-separated list from the environment, falling back to def
+func envList(key string, def []string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.ToLower(strings.TrimSpace(value)); value != "" {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		return slices.Clone(def)
+	}
+	return values
+}
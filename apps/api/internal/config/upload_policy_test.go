@@ -0,0 +1,101 @@
+package config_test
+
+import (
+	"fmt"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/wavlake/monorepo/internal/config"
+	"github.com/wavlake/monorepo/internal/utils"
+)
+
+var _ = Describe("UploadPolicy", func() {
+	envKeys := []string{"UPLOAD_MAX_BYTES", "UPLOAD_MIN_SAMPLE_RATE", "UPLOAD_ALLOWED_CODECS"}
+	originalEnvValues := make(map[string]string)
+
+	BeforeEach(func() {
+		for _, key := range envKeys {
+			originalEnvValues[key] = os.Getenv(key)
+		}
+	})
+
+	AfterEach(func() {
+		for _, key := range envKeys {
+			if originalEnvValues[key] != "" {
+				os.Setenv(key, originalEnvValues[key])
+			} else {
+				os.Unsetenv(key)
+			}
+		}
+	})
+
+	probe := func(format, codec, codecTag string, sampleRate int, duration string) *utils.ProbeResult {
+		result, err := utils.ParseProbeOutput([]byte(fmt.Sprintf(`{
+			"streams": [{"index": 0, "codec_type": "audio", "codec_name": %q, "codec_tag_string": %q, "sample_rate": "%d"}],
+			"format": {"format_name": %q, "duration": %q}
+		}`, codec, codecTag, sampleRate, format, duration)))
+		Expect(err).NotTo(HaveOccurred())
+		return result
+	}
+
+	Describe("NewUploadPolicy", func() {
+		It("should use defaults when unset or invalid", func() {
+			os.Unsetenv("UPLOAD_MAX_BYTES")
+			os.Setenv("UPLOAD_MIN_SAMPLE_RATE", "-1")
+			os.Unsetenv("UPLOAD_ALLOWED_CODECS")
+
+			policy := config.NewUploadPolicy()
+			Expect(policy.MaxBytes).To(Equal(int64(config.DefaultUploadMaxBytes)))
+			Expect(policy.MinSampleRate).To(Equal(config.DefaultUploadMinSampleRate))
+			Expect(policy.AllowedCodecs).To(Equal(config.DefaultUploadCodecs))
+		})
+
+		It("should read limits from environment", func() {
+			os.Setenv("UPLOAD_MAX_BYTES", "1048576")
+			os.Setenv("UPLOAD_ALLOWED_CODECS", " FLAC, mp3 ,")
+
+			policy := config.NewUploadPolicy()
+			Expect(policy.MaxBytes).To(Equal(int64(1048576)))
+			Expect(policy.AllowedCodecs).To(Equal([]string{"flac", "mp3"}))
+		})
+	})
+
+	Describe("Check", func() {
+		var policy *config.UploadPolicy
+
+		BeforeEach(func() {
+			policy = &config.UploadPolicy{
+				MaxBytes:           1000,
+				MinDurationSeconds: 1,
+				MaxDurationSeconds: 600,
+				MinSampleRate:      22050,
+				AllowedCodecs:      []string{"mp3", "aac"},
+				AllowedContainers:  []string{"mp3", "mp4"},
+			}
+		})
+
+		It("should accept a file within the policy", func() {
+			Expect(policy.Check(probe("mov,mp4,m4a,3gp,3g2,mj2", "aac", "mp4a", 44100, "180.0"), 500)).To(BeEmpty())
+		})
+
+		It("should report every violation", func() {
+			reasons := policy.Check(probe("wav", "pcm_s16le", "[1][0][0][0]", 8000, "0.5"), 2000)
+			Expect(reasons).To(HaveLen(5))
+			Expect(reasons[0]).To(ContainSubstring("larger than the 1000 byte limit"))
+			Expect(reasons[1]).To(ContainSubstring(`container "wav"`))
+			Expect(reasons[2]).To(ContainSubstring(`codec "pcm_s16le"`))
+			Expect(reasons[3]).To(ContainSubstring("sample rate 8000 Hz"))
+			Expect(reasons[4]).To(ContainSubstring("shorter than the 1s minimum"))
+		})
+
+		It("should reject DRM protected audio", func() {
+			Expect(policy.Check(probe("mov,mp4,m4a,3gp,3g2,mj2", "", "drms", 44100, "180.0"), 500)).To(ConsistOf("file is DRM protected"))
+		})
+
+		It("should reject files without a readable duration", func() {
+			Expect(policy.Check(probe("mp3", "mp3", "", 44100, "N/A"), 500)).To(ConsistOf(ContainSubstring("duration could not be read")))
+		})
+	})
+})
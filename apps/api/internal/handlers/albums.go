@@ -0,0 +1,338 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wavlake/monorepo/internal/models"
+	"github.com/wavlake/monorepo/internal/services"
+)
+
+type AlbumsHandler struct {
+	albumService services.NostrAlbumServiceInterface
+}
+
+func NewAlbumsHandler(albumService services.NostrAlbumServiceInterface) *AlbumsHandler {
+	return &AlbumsHandler{
+		albumService: albumService,
+	}
+}
+
+type CreateAlbumRequest struct {
+	Title       string   `json:"title" binding:"required"`
+	Description string   `json:"description"`
+	ArtworkURL  string   `json:"artwork_url"`
+	Genre       string   `json:"genre"`
+	IsSingle    bool     `json:"is_single"`
+	TrackIDs    []string `json:"track_ids"`
+}
+
+// UpdateAlbumRequest only changes the fields that are present in the request.
+// Setting is_draft to false publishes the album.
+type UpdateAlbumRequest struct {
+	Title       *string `json:"title"`
+	Description *string `json:"description"`
+	ArtworkURL  *string `json:"artwork_url"`
+	Genre       *string `json:"genre"`
+	IsSingle    *bool   `json:"is_single"`
+	IsDraft     *bool   `json:"is_draft"`
+}
+
+type SetAlbumTracksRequest struct {
+	TrackIDs []string `json:"track_ids" binding:"required"`
+}
+
+type AlbumResponse struct {
+	Success bool               `json:"success"`
+	Data    *models.NostrAlbum `json:"data,omitempty"`
+	Error   string             `json:"error,omitempty"`
+	Message string             `json:"message,omitempty"`
+}
+
+type AlbumsResponse struct {
+	Success bool                 `json:"success"`
+	Data    []*models.NostrAlbum `json:"data,omitempty"`
+	Error   string               `json:"error,omitempty"`
+}
+
+type PublicAlbumResponse struct {
+	Success bool         `json:"success"`
+	Data    *PublicAlbum `json:"data,omitempty"`
+	Error   string       `json:"error,omitempty"`
+}
+
+type PublicAlbumsResponse struct {
+	Success bool           `json:"success"`
+	Data    []*PublicAlbum `json:"data,omitempty"`
+	Error   string         `json:"error,omitempty"`
+}
+
+// CreateAlbum creates a draft album owned by the authenticated pubkey
+func (h *AlbumsHandler) CreateAlbum(c *gin.Context) {
+	var req CreateAlbumRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, AlbumResponse{
+			Success: false,
+			Error:   "title field is required",
+		})
+		return
+	}
+
+	pubkey, ok := authenticatedPubkey(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, AlbumResponse{
+			Success: false,
+			Error:   "authentication required",
+		})
+		return
+	}
+
+	album := &models.NostrAlbum{
+		Pubkey:      pubkey,
+		FirebaseUID: c.GetString("firebase_uid"),
+		Title:       req.Title,
+		Description: req.Description,
+		ArtworkURL:  req.ArtworkURL,
+		Genre:       req.Genre,
+		IsSingle:    req.IsSingle,
+		TrackIDs:    req.TrackIDs,
+	}
+
+	if err := h.albumService.CreateAlbum(c.Request.Context(), album); err != nil {
+		if errors.Is(err, services.ErrInvalidAlbumTrack) {
+			c.JSON(http.StatusBadRequest, AlbumResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+			return
+		}
+		log.Printf("Failed to create album for pubkey %s: %v", pubkey, err)
+		c.JSON(http.StatusInternalServerError, AlbumResponse{
+			Success: false,
+			Error:   "failed to create album",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, AlbumResponse{
+		Success: true,
+		Data:    album,
+	})
+}
+
+// GetAlbum returns a published album by ID
+func (h *AlbumsHandler) GetAlbum(c *gin.Context) {
+	albumID := c.Param("albumId")
+	if albumID == "" {
+		c.JSON(http.StatusBadRequest, PublicAlbumResponse{
+			Success: false,
+			Error:   "album ID is required",
+		})
+		return
+	}
+
+	album, err := h.albumService.GetAlbum(c.Request.Context(), albumID)
+	if err != nil || album.Deleted || album.IsDraft {
+		c.JSON(http.StatusNotFound, PublicAlbumResponse{
+			Success: false,
+			Error:   "album not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, PublicAlbumResponse{
+		Success: true,
+		Data:    newPublicAlbum(album),
+	})
+}
+
+// GetMyAlbums returns all albums for the authenticated pubkey, drafts included
+func (h *AlbumsHandler) GetMyAlbums(c *gin.Context) {
+	pubkey, ok := authenticatedPubkey(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, AlbumsResponse{
+			Success: false,
+			Error:   "authentication required",
+		})
+		return
+	}
+
+	albums, err := h.albumService.GetAlbumsByPubkey(c.Request.Context(), pubkey)
+	if err != nil {
+		log.Printf("Failed to get albums for pubkey %s: %v", pubkey, err)
+		c.JSON(http.StatusInternalServerError, AlbumsResponse{
+			Success: false,
+			Error:   "failed to retrieve albums",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, AlbumsResponse{
+		Success: true,
+		Data:    albums,
+	})
+}
+
+// UpdateAlbum updates album metadata and draft state
+func (h *AlbumsHandler) UpdateAlbum(c *gin.Context) {
+	var req UpdateAlbumRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, AlbumResponse{
+			Success: false,
+			Error:   "invalid request body",
+		})
+		return
+	}
+
+	album, ok := h.getOwnedAlbum(c)
+	if !ok {
+		return
+	}
+
+	updates := map[string]interface{}{}
+	if req.Title != nil {
+		if *req.Title == "" {
+			c.JSON(http.StatusBadRequest, AlbumResponse{
+				Success: false,
+				Error:   "title cannot be empty",
+			})
+			return
+		}
+		updates["title"] = *req.Title
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if req.ArtworkURL != nil {
+		updates["artwork_url"] = *req.ArtworkURL
+	}
+	if req.Genre != nil {
+		updates["genre"] = *req.Genre
+	}
+	if req.IsSingle != nil {
+		updates["is_single"] = *req.IsSingle
+	}
+	if req.IsDraft != nil {
+		updates["is_draft"] = *req.IsDraft
+		// Keep the original publish date when an album is unpublished and republished
+		if !*req.IsDraft && album.PublishedAt.IsZero() {
+			updates["published_at"] = time.Now()
+		}
+	}
+
+	ctx := c.Request.Context()
+
+	if len(updates) > 0 {
+		if err := h.albumService.UpdateAlbum(ctx, album.ID, updates); err != nil {
+			log.Printf("Failed to update album %s: %v", album.ID, err)
+			c.JSON(http.StatusInternalServerError, AlbumResponse{
+				Success: false,
+				Error:   "failed to update album",
+			})
+			return
+		}
+	}
+
+	h.respondWithAlbum(c, album.ID)
+}
+
+// SetAlbumTracks replaces the ordered track list of an album
+func (h *AlbumsHandler) SetAlbumTracks(c *gin.Context) {
+	var req SetAlbumTracksRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, AlbumResponse{
+			Success: false,
+			Error:   "track_ids field is required",
+		})
+		return
+	}
+
+	album, ok := h.getOwnedAlbum(c)
+	if !ok {
+		return
+	}
+
+	if err := h.albumService.SetAlbumTracks(c.Request.Context(), album.ID, req.TrackIDs); err != nil {
+		if errors.Is(err, services.ErrInvalidAlbumTrack) {
+			c.JSON(http.StatusBadRequest, AlbumResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+			return
+		}
+		log.Printf("Failed to set tracks for album %s: %v", album.ID, err)
+		c.JSON(http.StatusInternalServerError, AlbumResponse{
+			Success: false,
+			Error:   "failed to update album tracks",
+		})
+		return
+	}
+
+	h.respondWithAlbum(c, album.ID)
+}
+
+// DeleteAlbum soft deletes an album
+func (h *AlbumsHandler) DeleteAlbum(c *gin.Context) {
+	album, ok := h.getOwnedAlbum(c)
+	if !ok {
+		return
+	}
+
+	if err := h.albumService.DeleteAlbum(c.Request.Context(), album.ID); err != nil {
+		log.Printf("Failed to delete album %s: %v", album.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete album"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "album deleted successfully"})
+}
+
+// getOwnedAlbum loads the album from the path and verifies the caller owns it.
+// It writes the error response and returns false when the album cannot be used.
+func (h *AlbumsHandler) getOwnedAlbum(c *gin.Context) (*models.NostrAlbum, bool) {
+	albumID := c.Param("albumId")
+	if albumID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "album ID is required"})
+		return nil, false
+	}
+
+	pubkey, ok := authenticatedPubkey(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		return nil, false
+	}
+
+	album, err := h.albumService.GetAlbum(c.Request.Context(), albumID)
+	if err != nil || album.Deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "album not found"})
+		return nil, false
+	}
+
+	if album.Pubkey != pubkey {
+		c.JSON(http.StatusForbidden, gin.H{"error": "you can only modify your own albums"})
+		return nil, false
+	}
+
+	return album, true
+}
+
+// respondWithAlbum reloads an album after a write and returns it to the owner
+func (h *AlbumsHandler) respondWithAlbum(c *gin.Context, albumID string) {
+	album, err := h.albumService.GetAlbum(c.Request.Context(), albumID)
+	if err != nil {
+		log.Printf("Failed to reload album %s: %v", albumID, err)
+		c.JSON(http.StatusInternalServerError, AlbumResponse{
+			Success: false,
+			Error:   "failed to retrieve album",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, AlbumResponse{
+		Success: true,
+		Data:    album,
+	})
+}
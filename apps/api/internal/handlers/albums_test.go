@@ -0,0 +1,251 @@
+package handlers_test
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/wavlake/monorepo/internal/handlers"
+	"github.com/wavlake/monorepo/internal/models"
+	"github.com/wavlake/monorepo/internal/services"
+	"github.com/wavlake/monorepo/tests/mocks"
+	"github.com/wavlake/monorepo/tests/testutil"
+)
+
+var _ = Describe("AlbumsHandler", func() {
+	var (
+		ctrl             *gomock.Controller
+		mockAlbumService *mocks.MockNostrAlbumServiceInterface
+		albumsHandler    *handlers.AlbumsHandler
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockAlbumService = mocks.NewMockNostrAlbumServiceInterface(ctrl)
+		albumsHandler = handlers.NewAlbumsHandler(mockAlbumService)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("CreateAlbum", func() {
+		It("should create an album owned by the authenticated pubkey", func() {
+			c, w := testutil.SetupGinTestContext("POST", "/v1/albums", map[string]interface{}{
+				"title":     "Test Album",
+				"track_ids": []string{testutil.TestTrackID},
+			})
+			testutil.SetAuthContext(c, testutil.TestFirebaseUID, testutil.TestPubkey)
+
+			mockAlbumService.EXPECT().
+				CreateAlbum(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ interface{}, album *models.NostrAlbum) error {
+					Expect(album.Pubkey).To(Equal(testutil.TestPubkey))
+					Expect(album.TrackIDs).To(Equal([]string{testutil.TestTrackID}))
+					album.ID = testutil.TestAlbumID
+					album.IsDraft = true
+					return nil
+				})
+
+			albumsHandler.CreateAlbum(c)
+
+			response := testutil.AssertJSONResponse(w, http.StatusOK)
+			data := response["data"].(map[string]interface{})
+			Expect(data["id"]).To(Equal(testutil.TestAlbumID))
+			Expect(data["is_draft"]).To(BeTrue())
+		})
+
+		It("should reject tracks the caller cannot use", func() {
+			c, w := testutil.SetupGinTestContext("POST", "/v1/albums", map[string]interface{}{
+				"title":     "Test Album",
+				"track_ids": []string{"someone-elses-track"},
+			})
+			testutil.SetAuthContext(c, testutil.TestFirebaseUID, testutil.TestPubkey)
+
+			mockAlbumService.EXPECT().
+				CreateAlbum(gomock.Any(), gomock.Any()).
+				Return(fmt.Errorf("%w: track someone-elses-track is not owned by this pubkey", services.ErrInvalidAlbumTrack))
+
+			albumsHandler.CreateAlbum(c)
+
+			response := testutil.AssertJSONResponse(w, http.StatusBadRequest)
+			Expect(response["error"]).To(ContainSubstring("not owned by this pubkey"))
+		})
+	})
+
+	Describe("GetAlbum", func() {
+		It("should return published albums", func() {
+			c, w := testutil.SetupGinTestContext("GET", "/v1/albums/"+testutil.TestAlbumID, nil)
+			c.Params = []gin.Param{{Key: "albumId", Value: testutil.TestAlbumID}}
+
+			mockAlbumService.EXPECT().GetAlbum(gomock.Any(), testutil.TestAlbumID).Return(testutil.ValidNostrAlbum(), nil)
+
+			albumsHandler.GetAlbum(c)
+
+			response := testutil.AssertJSONResponse(w, http.StatusOK)
+			data := response["data"].(map[string]interface{})
+			Expect(data["track_ids"]).To(HaveLen(2))
+			Expect(data).NotTo(HaveKey("firebase_uid"))
+		})
+
+		It("should hide drafts", func() {
+			c, w := testutil.SetupGinTestContext("GET", "/v1/albums/"+testutil.TestAlbumID, nil)
+			c.Params = []gin.Param{{Key: "albumId", Value: testutil.TestAlbumID}}
+
+			album := testutil.ValidNostrAlbum()
+			album.IsDraft = true
+			mockAlbumService.EXPECT().GetAlbum(gomock.Any(), testutil.TestAlbumID).Return(album, nil)
+
+			albumsHandler.GetAlbum(c)
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Describe("GetMyAlbums", func() {
+		It("should include drafts for the owner", func() {
+			c, w := testutil.SetupGinTestContext("GET", "/v1/albums/my", nil)
+			testutil.SetAuthContext(c, testutil.TestFirebaseUID, testutil.TestPubkey)
+
+			draft := testutil.ValidNostrAlbum()
+			draft.IsDraft = true
+			mockAlbumService.EXPECT().
+				GetAlbumsByPubkey(gomock.Any(), testutil.TestPubkey).
+				Return([]*models.NostrAlbum{testutil.ValidNostrAlbum(), draft}, nil)
+
+			albumsHandler.GetMyAlbums(c)
+
+			response := testutil.AssertJSONResponse(w, http.StatusOK)
+			Expect(response["data"]).To(HaveLen(2))
+		})
+	})
+
+	Describe("UpdateAlbum", func() {
+		var draft *models.NostrAlbum
+
+		BeforeEach(func() {
+			draft = testutil.ValidNostrAlbum()
+			draft.IsDraft = true
+			draft.PublishedAt = time.Time{}
+		})
+
+		It("should set published_at the first time an album is published", func() {
+			c, w := testutil.SetupGinTestContext("PUT", "/v1/albums/"+testutil.TestAlbumID, map[string]interface{}{
+				"is_draft": false,
+			})
+			c.Params = []gin.Param{{Key: "albumId", Value: testutil.TestAlbumID}}
+			testutil.SetAuthContext(c, testutil.TestFirebaseUID, testutil.TestPubkey)
+
+			gomock.InOrder(
+				mockAlbumService.EXPECT().GetAlbum(gomock.Any(), testutil.TestAlbumID).Return(draft, nil),
+				mockAlbumService.EXPECT().
+					UpdateAlbum(gomock.Any(), testutil.TestAlbumID, gomock.Any()).
+					DoAndReturn(func(_ interface{}, _ string, updates map[string]interface{}) error {
+						Expect(updates["is_draft"]).To(BeFalse())
+						Expect(updates).To(HaveKey("published_at"))
+						return nil
+					}),
+				mockAlbumService.EXPECT().GetAlbum(gomock.Any(), testutil.TestAlbumID).Return(testutil.ValidNostrAlbum(), nil),
+			)
+
+			albumsHandler.UpdateAlbum(c)
+
+			response := testutil.AssertJSONResponse(w, http.StatusOK)
+			data := response["data"].(map[string]interface{})
+			Expect(data["is_draft"]).To(BeFalse())
+		})
+
+		It("should keep the original publish date when republishing", func() {
+			c, w := testutil.SetupGinTestContext("PUT", "/v1/albums/"+testutil.TestAlbumID, map[string]interface{}{
+				"is_draft": false,
+			})
+			c.Params = []gin.Param{{Key: "albumId", Value: testutil.TestAlbumID}}
+			testutil.SetAuthContext(c, testutil.TestFirebaseUID, testutil.TestPubkey)
+
+			draft.PublishedAt = testutil.FixedTime()
+
+			gomock.InOrder(
+				mockAlbumService.EXPECT().GetAlbum(gomock.Any(), testutil.TestAlbumID).Return(draft, nil),
+				mockAlbumService.EXPECT().
+					UpdateAlbum(gomock.Any(), testutil.TestAlbumID, map[string]interface{}{"is_draft": false}).
+					Return(nil),
+				mockAlbumService.EXPECT().GetAlbum(gomock.Any(), testutil.TestAlbumID).Return(testutil.ValidNostrAlbum(), nil),
+			)
+
+			albumsHandler.UpdateAlbum(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+		})
+
+		It("should forbid modifying another pubkey's album", func() {
+			c, w := testutil.SetupGinTestContext("PUT", "/v1/albums/"+testutil.TestAlbumID, map[string]interface{}{
+				"title": "Renamed",
+			})
+			c.Params = []gin.Param{{Key: "albumId", Value: testutil.TestAlbumID}}
+			testutil.SetAuthContext(c, testutil.TestFirebaseUID, testutil.TestPubkey2)
+
+			mockAlbumService.EXPECT().GetAlbum(gomock.Any(), testutil.TestAlbumID).Return(testutil.ValidNostrAlbum(), nil)
+
+			albumsHandler.UpdateAlbum(c)
+
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+		})
+	})
+
+	Describe("SetAlbumTracks", func() {
+		It("should replace the track order", func() {
+			newOrder := []string{"test-track-456", testutil.TestTrackID}
+			c, w := testutil.SetupGinTestContext("PUT", "/v1/albums/"+testutil.TestAlbumID+"/tracks", map[string]interface{}{
+				"track_ids": newOrder,
+			})
+			c.Params = []gin.Param{{Key: "albumId", Value: testutil.TestAlbumID}}
+			testutil.SetAuthContext(c, testutil.TestFirebaseUID, testutil.TestPubkey)
+
+			reordered := testutil.ValidNostrAlbum()
+			reordered.TrackIDs = newOrder
+
+			gomock.InOrder(
+				mockAlbumService.EXPECT().GetAlbum(gomock.Any(), testutil.TestAlbumID).Return(testutil.ValidNostrAlbum(), nil),
+				mockAlbumService.EXPECT().SetAlbumTracks(gomock.Any(), testutil.TestAlbumID, newOrder).Return(nil),
+				mockAlbumService.EXPECT().GetAlbum(gomock.Any(), testutil.TestAlbumID).Return(reordered, nil),
+			)
+
+			albumsHandler.SetAlbumTracks(c)
+
+			response := testutil.AssertJSONResponse(w, http.StatusOK)
+			data := response["data"].(map[string]interface{})
+			Expect(data["track_ids"]).To(Equal([]interface{}{"test-track-456", testutil.TestTrackID}))
+		})
+
+		It("should require track_ids", func() {
+			c, w := testutil.SetupGinTestContext("PUT", "/v1/albums/"+testutil.TestAlbumID+"/tracks", map[string]interface{}{})
+			c.Params = []gin.Param{{Key: "albumId", Value: testutil.TestAlbumID}}
+			testutil.SetAuthContext(c, testutil.TestFirebaseUID, testutil.TestPubkey)
+
+			albumsHandler.SetAlbumTracks(c)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Describe("DeleteAlbum", func() {
+		It("should soft delete the album", func() {
+			c, w := testutil.SetupGinTestContext("DELETE", "/v1/albums/"+testutil.TestAlbumID, nil)
+			c.Params = []gin.Param{{Key: "albumId", Value: testutil.TestAlbumID}}
+			testutil.SetAuthContext(c, testutil.TestFirebaseUID, testutil.TestPubkey)
+
+			mockAlbumService.EXPECT().GetAlbum(gomock.Any(), testutil.TestAlbumID).Return(testutil.ValidNostrAlbum(), nil)
+			mockAlbumService.EXPECT().DeleteAlbum(gomock.Any(), testutil.TestAlbumID).Return(nil)
+
+			albumsHandler.DeleteAlbum(c)
+
+			response := testutil.AssertJSONResponse(w, http.StatusOK)
+			Expect(response["success"]).To(BeTrue())
+		})
+	})
+})
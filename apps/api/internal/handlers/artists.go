@@ -0,0 +1,317 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wavlake/monorepo/internal/config"
+	"github.com/wavlake/monorepo/internal/models"
+	"github.com/wavlake/monorepo/internal/services"
+)
+
+type ArtistsHandler struct {
+	artistService    services.NostrArtistServiceInterface
+	albumService     services.NostrAlbumServiceInterface
+	processingConfig *config.ProcessingConfig
+}
+
+func NewArtistsHandler(artistService services.NostrArtistServiceInterface, albumService services.NostrAlbumServiceInterface, processingConfig *config.ProcessingConfig) *ArtistsHandler {
+	return &ArtistsHandler{
+		artistService:    artistService,
+		albumService:     albumService,
+		processingConfig: processingConfig,
+	}
+}
+
+type CreateArtistRequest struct {
+	Name       string `json:"name" binding:"required"`
+	Bio        string `json:"bio"`
+	ArtworkURL string `json:"artwork_url"`
+	Website    string `json:"website"`
+	Twitter    string `json:"twitter"`
+	Instagram  string `json:"instagram"`
+	Youtube    string `json:"youtube"`
+}
+
+// UpdateArtistRequest only changes the fields that are present in the request
+type UpdateArtistRequest struct {
+	Name          *string   `json:"name"`
+	Bio           *string   `json:"bio"`
+	ArtworkURL    *string   `json:"artwork_url"`
+	Website       *string   `json:"website"`
+	Twitter       *string   `json:"twitter"`
+	Instagram     *string   `json:"instagram"`
+	Youtube       *string   `json:"youtube"`
+	DefaultLadder *[]string `json:"default_ladder"` // Compression presets generated on upload, empty for the platform default
+}
+
+type ArtistResponse struct {
+	Success bool                `json:"success"`
+	Data    *models.NostrArtist `json:"data,omitempty"`
+	Error   string              `json:"error,omitempty"`
+	Message string              `json:"message,omitempty"`
+}
+
+type PublicArtistResponse struct {
+	Success bool          `json:"success"`
+	Data    *PublicArtist `json:"data,omitempty"`
+	Error   string        `json:"error,omitempty"`
+}
+
+// CreateArtist creates the artist profile for the authenticated pubkey
+func (h *ArtistsHandler) CreateArtist(c *gin.Context) {
+	var req CreateArtistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ArtistResponse{
+			Success: false,
+			Error:   "name field is required",
+		})
+		return
+	}
+
+	pubkey, ok := authenticatedPubkey(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, ArtistResponse{
+			Success: false,
+			Error:   "authentication required",
+		})
+		return
+	}
+
+	artist := &models.NostrArtist{
+		Pubkey:      pubkey,
+		FirebaseUID: c.GetString("firebase_uid"),
+		Name:        req.Name,
+		Bio:         req.Bio,
+		ArtworkURL:  req.ArtworkURL,
+		Website:     req.Website,
+		Twitter:     req.Twitter,
+		Instagram:   req.Instagram,
+		Youtube:     req.Youtube,
+	}
+
+	if err := h.artistService.CreateArtist(c.Request.Context(), artist); err != nil {
+		if errors.Is(err, services.ErrArtistAlreadyExists) {
+			c.JSON(http.StatusConflict, ArtistResponse{
+				Success: false,
+				Error:   "artist profile already exists for this pubkey",
+			})
+			return
+		}
+		log.Printf("Failed to create artist for pubkey %s: %v", pubkey, err)
+		c.JSON(http.StatusInternalServerError, ArtistResponse{
+			Success: false,
+			Error:   "failed to create artist",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ArtistResponse{
+		Success: true,
+		Data:    artist,
+	})
+}
+
+// GetArtist returns the public artist profile for a pubkey
+func (h *ArtistsHandler) GetArtist(c *gin.Context) {
+	pubkey := c.Param("pubkey")
+	if pubkey == "" {
+		c.JSON(http.StatusBadRequest, PublicArtistResponse{
+			Success: false,
+			Error:   "pubkey is required",
+		})
+		return
+	}
+
+	artist, err := h.artistService.GetArtist(c.Request.Context(), pubkey)
+	if err != nil || artist.Deleted {
+		c.JSON(http.StatusNotFound, PublicArtistResponse{
+			Success: false,
+			Error:   "artist not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, PublicArtistResponse{
+		Success: true,
+		Data:    newPublicArtist(artist),
+	})
+}
+
+// UpdateArtist updates the artist profile owned by the authenticated pubkey
+func (h *ArtistsHandler) UpdateArtist(c *gin.Context) {
+	pubkey := c.Param("pubkey")
+
+	authPubkey, ok := authenticatedPubkey(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, ArtistResponse{
+			Success: false,
+			Error:   "authentication required",
+		})
+		return
+	}
+
+	if pubkey != authPubkey {
+		c.JSON(http.StatusForbidden, ArtistResponse{
+			Success: false,
+			Error:   "you can only update your own artist profile",
+		})
+		return
+	}
+
+	var req UpdateArtistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ArtistResponse{
+			Success: false,
+			Error:   "invalid request body",
+		})
+		return
+	}
+
+	updates := map[string]interface{}{}
+	if req.Name != nil {
+		if *req.Name == "" {
+			c.JSON(http.StatusBadRequest, ArtistResponse{
+				Success: false,
+				Error:   "name cannot be empty",
+			})
+			return
+		}
+		updates["name"] = *req.Name
+	}
+	if req.Bio != nil {
+		updates["bio"] = *req.Bio
+	}
+	if req.ArtworkURL != nil {
+		updates["artwork_url"] = *req.ArtworkURL
+	}
+	if req.Website != nil {
+		updates["website"] = *req.Website
+	}
+	if req.Twitter != nil {
+		updates["twitter"] = *req.Twitter
+	}
+	if req.Instagram != nil {
+		updates["instagram"] = *req.Instagram
+	}
+	if req.Youtube != nil {
+		updates["youtube"] = *req.Youtube
+	}
+	if req.DefaultLadder != nil {
+		for _, name := range *req.DefaultLadder {
+			if _, ok := h.processingConfig.Presets[name]; !ok {
+				c.JSON(http.StatusBadRequest, ArtistResponse{
+					Success: false,
+					Error:   "unknown compression preset: " + name,
+				})
+				return
+			}
+		}
+		updates["default_ladder"] = *req.DefaultLadder
+	}
+
+	ctx := c.Request.Context()
+
+	artist, err := h.artistService.GetArtist(ctx, pubkey)
+	if err != nil || artist.Deleted {
+		c.JSON(http.StatusNotFound, ArtistResponse{
+			Success: false,
+			Error:   "artist not found",
+		})
+		return
+	}
+
+	if len(updates) > 0 {
+		if err := h.artistService.UpdateArtist(ctx, pubkey, updates); err != nil {
+			log.Printf("Failed to update artist %s: %v", pubkey, err)
+			c.JSON(http.StatusInternalServerError, ArtistResponse{
+				Success: false,
+				Error:   "failed to update artist",
+			})
+			return
+		}
+	}
+
+	updated, err := h.artistService.GetArtist(ctx, pubkey)
+	if err != nil {
+		log.Printf("Failed to reload artist %s: %v", pubkey, err)
+		c.JSON(http.StatusInternalServerError, ArtistResponse{
+			Success: false,
+			Error:   "failed to retrieve artist",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ArtistResponse{
+		Success: true,
+		Data:    updated,
+	})
+}
+
+// DeleteArtist soft deletes the artist profile owned by the authenticated pubkey
+func (h *ArtistsHandler) DeleteArtist(c *gin.Context) {
+	pubkey := c.Param("pubkey")
+
+	authPubkey, ok := authenticatedPubkey(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		return
+	}
+
+	if pubkey != authPubkey {
+		c.JSON(http.StatusForbidden, gin.H{"error": "you can only delete your own artist profile"})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	artist, err := h.artistService.GetArtist(ctx, pubkey)
+	if err != nil || artist.Deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "artist not found"})
+		return
+	}
+
+	if err := h.artistService.DeleteArtist(ctx, pubkey); err != nil {
+		log.Printf("Failed to delete artist %s: %v", pubkey, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete artist"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "artist deleted successfully"})
+}
+
+// GetArtistAlbums returns the published albums of an artist
+func (h *ArtistsHandler) GetArtistAlbums(c *gin.Context) {
+	pubkey := c.Param("pubkey")
+	if pubkey == "" {
+		c.JSON(http.StatusBadRequest, PublicAlbumsResponse{
+			Success: false,
+			Error:   "pubkey is required",
+		})
+		return
+	}
+
+	albums, err := h.albumService.GetAlbumsByPubkey(c.Request.Context(), pubkey)
+	if err != nil {
+		log.Printf("Failed to get albums for pubkey %s: %v", pubkey, err)
+		c.JSON(http.StatusInternalServerError, PublicAlbumsResponse{
+			Success: false,
+			Error:   "failed to retrieve albums",
+		})
+		return
+	}
+
+	published := []*PublicAlbum{}
+	for _, album := range albums {
+		if !album.IsDraft {
+			published = append(published, newPublicAlbum(album))
+		}
+	}
+
+	c.JSON(http.StatusOK, PublicAlbumsResponse{
+		Success: true,
+		Data:    published,
+	})
+}
@@ -0,0 +1,263 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/wavlake/monorepo/internal/config"
+	"github.com/wavlake/monorepo/internal/handlers"
+	"github.com/wavlake/monorepo/internal/models"
+	"github.com/wavlake/monorepo/internal/services"
+	"github.com/wavlake/monorepo/tests/mocks"
+	"github.com/wavlake/monorepo/tests/testutil"
+)
+
+var _ = Describe("ArtistsHandler", func() {
+	var (
+		ctrl              *gomock.Controller
+		mockArtistService *mocks.MockNostrArtistServiceInterface
+		mockAlbumService  *mocks.MockNostrAlbumServiceInterface
+		artistsHandler    *handlers.ArtistsHandler
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockArtistService = mocks.NewMockNostrArtistServiceInterface(ctrl)
+		mockAlbumService = mocks.NewMockNostrAlbumServiceInterface(ctrl)
+		artistsHandler = handlers.NewArtistsHandler(mockArtistService, mockAlbumService, config.NewProcessingConfig())
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("CreateArtist", func() {
+		It("should create a profile for the authenticated pubkey", func() {
+			c, w := testutil.SetupGinTestContext("POST", "/v1/artists", map[string]interface{}{
+				"name": "Test Artist",
+				"bio":  "Test artist bio",
+			})
+			testutil.SetAuthContext(c, testutil.TestFirebaseUID, testutil.TestPubkey)
+
+			mockArtistService.EXPECT().
+				CreateArtist(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ interface{}, artist *models.NostrArtist) error {
+					Expect(artist.Pubkey).To(Equal(testutil.TestPubkey))
+					Expect(artist.FirebaseUID).To(Equal(testutil.TestFirebaseUID))
+					Expect(artist.Name).To(Equal("Test Artist"))
+					return nil
+				})
+
+			artistsHandler.CreateArtist(c)
+
+			response := testutil.AssertJSONResponse(w, http.StatusOK)
+			Expect(response["success"]).To(BeTrue())
+			data := response["data"].(map[string]interface{})
+			Expect(data["pubkey"]).To(Equal(testutil.TestPubkey))
+		})
+
+		It("should require a name", func() {
+			c, w := testutil.SetupGinTestContext("POST", "/v1/artists", map[string]interface{}{})
+			testutil.SetAuthContext(c, testutil.TestFirebaseUID, testutil.TestPubkey)
+
+			artistsHandler.CreateArtist(c)
+
+			response := testutil.AssertJSONResponse(w, http.StatusBadRequest)
+			Expect(response["error"]).To(Equal("name field is required"))
+		})
+
+		It("should return conflict when the profile already exists", func() {
+			c, w := testutil.SetupGinTestContext("POST", "/v1/artists", map[string]interface{}{
+				"name": "Test Artist",
+			})
+			testutil.SetAuthContext(c, testutil.TestFirebaseUID, testutil.TestPubkey)
+
+			mockArtistService.EXPECT().
+				CreateArtist(gomock.Any(), gomock.Any()).
+				Return(services.ErrArtistAlreadyExists)
+
+			artistsHandler.CreateArtist(c)
+
+			Expect(w.Code).To(Equal(http.StatusConflict))
+		})
+
+		It("should require authentication", func() {
+			c, w := testutil.SetupGinTestContext("POST", "/v1/artists", map[string]interface{}{
+				"name": "Test Artist",
+			})
+
+			artistsHandler.CreateArtist(c)
+
+			response := testutil.AssertJSONResponse(w, http.StatusUnauthorized)
+			Expect(response["error"]).To(Equal("authentication required"))
+		})
+	})
+
+	Describe("GetArtist", func() {
+		It("should return the artist profile", func() {
+			c, w := testutil.SetupGinTestContext("GET", "/v1/artists/"+testutil.TestPubkey, nil)
+			c.Params = []gin.Param{{Key: "pubkey", Value: testutil.TestPubkey}}
+
+			mockArtistService.EXPECT().
+				GetArtist(gomock.Any(), testutil.TestPubkey).
+				Return(testutil.ValidNostrArtist(), nil)
+
+			artistsHandler.GetArtist(c)
+
+			response := testutil.AssertJSONResponse(w, http.StatusOK)
+			data := response["data"].(map[string]interface{})
+			Expect(data["name"]).To(Equal("Test Artist"))
+			Expect(data).NotTo(HaveKey("firebase_uid"))
+		})
+
+		It("should hide deleted profiles", func() {
+			c, w := testutil.SetupGinTestContext("GET", "/v1/artists/"+testutil.TestPubkey, nil)
+			c.Params = []gin.Param{{Key: "pubkey", Value: testutil.TestPubkey}}
+
+			artist := testutil.ValidNostrArtist()
+			artist.Deleted = true
+			mockArtistService.EXPECT().
+				GetArtist(gomock.Any(), testutil.TestPubkey).
+				Return(artist, nil)
+
+			artistsHandler.GetArtist(c)
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Describe("UpdateArtist", func() {
+		It("should only update fields present in the request", func() {
+			c, w := testutil.SetupGinTestContext("PUT", "/v1/artists/"+testutil.TestPubkey, map[string]interface{}{
+				"bio": "Updated bio",
+			})
+			c.Params = []gin.Param{{Key: "pubkey", Value: testutil.TestPubkey}}
+			testutil.SetAuthContext(c, testutil.TestFirebaseUID, testutil.TestPubkey)
+
+			updated := testutil.ValidNostrArtist()
+			updated.Bio = "Updated bio"
+
+			gomock.InOrder(
+				mockArtistService.EXPECT().GetArtist(gomock.Any(), testutil.TestPubkey).Return(testutil.ValidNostrArtist(), nil),
+				mockArtistService.EXPECT().
+					UpdateArtist(gomock.Any(), testutil.TestPubkey, map[string]interface{}{"bio": "Updated bio"}).
+					Return(nil),
+				mockArtistService.EXPECT().GetArtist(gomock.Any(), testutil.TestPubkey).Return(updated, nil),
+			)
+
+			artistsHandler.UpdateArtist(c)
+
+			response := testutil.AssertJSONResponse(w, http.StatusOK)
+			data := response["data"].(map[string]interface{})
+			Expect(data["bio"]).To(Equal("Updated bio"))
+		})
+
+		It("should store a default ladder of known presets", func() {
+			c, w := testutil.SetupGinTestContext("PUT", "/v1/artists/"+testutil.TestPubkey, map[string]interface{}{
+				"default_ladder": []string{config.PresetStreamHigh, config.PresetLossless},
+			})
+			c.Params = []gin.Param{{Key: "pubkey", Value: testutil.TestPubkey}}
+			testutil.SetAuthContext(c, testutil.TestFirebaseUID, testutil.TestPubkey)
+
+			updated := testutil.ValidNostrArtist()
+			updated.DefaultLadder = []string{config.PresetStreamHigh, config.PresetLossless}
+
+			gomock.InOrder(
+				mockArtistService.EXPECT().GetArtist(gomock.Any(), testutil.TestPubkey).Return(testutil.ValidNostrArtist(), nil),
+				mockArtistService.EXPECT().
+					UpdateArtist(gomock.Any(), testutil.TestPubkey, map[string]interface{}{
+						"default_ladder": []string{config.PresetStreamHigh, config.PresetLossless},
+					}).
+					Return(nil),
+				mockArtistService.EXPECT().GetArtist(gomock.Any(), testutil.TestPubkey).Return(updated, nil),
+			)
+
+			artistsHandler.UpdateArtist(c)
+
+			response := testutil.AssertJSONResponse(w, http.StatusOK)
+			data := response["data"].(map[string]interface{})
+			Expect(data["default_ladder"]).To(Equal([]interface{}{config.PresetStreamHigh, config.PresetLossless}))
+		})
+
+		It("should reject unknown presets in the default ladder", func() {
+			c, w := testutil.SetupGinTestContext("PUT", "/v1/artists/"+testutil.TestPubkey, map[string]interface{}{
+				"default_ladder": []string{"studio-master"},
+			})
+			c.Params = []gin.Param{{Key: "pubkey", Value: testutil.TestPubkey}}
+			testutil.SetAuthContext(c, testutil.TestFirebaseUID, testutil.TestPubkey)
+
+			artistsHandler.UpdateArtist(c)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should forbid updating another pubkey's profile", func() {
+			c, w := testutil.SetupGinTestContext("PUT", "/v1/artists/"+testutil.TestPubkey2, map[string]interface{}{
+				"bio": "Updated bio",
+			})
+			c.Params = []gin.Param{{Key: "pubkey", Value: testutil.TestPubkey2}}
+			testutil.SetAuthContext(c, testutil.TestFirebaseUID, testutil.TestPubkey)
+
+			artistsHandler.UpdateArtist(c)
+
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+		})
+	})
+
+	Describe("DeleteArtist", func() {
+		It("should soft delete the caller's profile", func() {
+			c, w := testutil.SetupGinTestContext("DELETE", "/v1/artists/"+testutil.TestPubkey, nil)
+			c.Params = []gin.Param{{Key: "pubkey", Value: testutil.TestPubkey}}
+			testutil.SetAuthContext(c, testutil.TestFirebaseUID, testutil.TestPubkey)
+
+			mockArtistService.EXPECT().GetArtist(gomock.Any(), testutil.TestPubkey).Return(testutil.ValidNostrArtist(), nil)
+			mockArtistService.EXPECT().DeleteArtist(gomock.Any(), testutil.TestPubkey).Return(nil)
+
+			artistsHandler.DeleteArtist(c)
+
+			response := testutil.AssertJSONResponse(w, http.StatusOK)
+			Expect(response["success"]).To(BeTrue())
+		})
+
+		It("should return server error when deletion fails", func() {
+			c, w := testutil.SetupGinTestContext("DELETE", "/v1/artists/"+testutil.TestPubkey, nil)
+			c.Params = []gin.Param{{Key: "pubkey", Value: testutil.TestPubkey}}
+			testutil.SetAuthContext(c, testutil.TestFirebaseUID, testutil.TestPubkey)
+
+			mockArtistService.EXPECT().GetArtist(gomock.Any(), testutil.TestPubkey).Return(testutil.ValidNostrArtist(), nil)
+			mockArtistService.EXPECT().DeleteArtist(gomock.Any(), testutil.TestPubkey).Return(errors.New("firestore unavailable"))
+
+			artistsHandler.DeleteArtist(c)
+
+			Expect(w.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+
+	Describe("GetArtistAlbums", func() {
+		It("should only return published albums", func() {
+			c, w := testutil.SetupGinTestContext("GET", "/v1/artists/"+testutil.TestPubkey+"/albums", nil)
+			c.Params = []gin.Param{{Key: "pubkey", Value: testutil.TestPubkey}}
+
+			draft := testutil.ValidNostrAlbum()
+			draft.ID = "album-draft"
+			draft.IsDraft = true
+
+			mockAlbumService.EXPECT().
+				GetAlbumsByPubkey(gomock.Any(), testutil.TestPubkey).
+				Return([]*models.NostrAlbum{testutil.ValidNostrAlbum(), draft}, nil)
+
+			artistsHandler.GetArtistAlbums(c)
+
+			response := testutil.AssertJSONResponse(w, http.StatusOK)
+			data := response["data"].([]interface{})
+			Expect(data).To(HaveLen(1))
+			Expect(data[0].(map[string]interface{})["id"]).To(Equal(testutil.TestAlbumID))
+			Expect(data[0]).NotTo(HaveKey("firebase_uid"))
+		})
+	})
+})
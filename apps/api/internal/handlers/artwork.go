@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wavlake/monorepo/internal/models"
+	"github.com/wavlake/monorepo/internal/services"
+)
+
+type ArtworkHandler struct {
+	artworkService services.ArtworkServiceInterface
+}
+
+func NewArtworkHandler(artworkService services.ArtworkServiceInterface) *ArtworkHandler {
+	return &ArtworkHandler{
+		artworkService: artworkService,
+	}
+}
+
+type CreateArtworkUploadRequest struct {
+	TargetType string `json:"target_type" binding:"required"` // "track" or "album"
+	TargetID   string `json:"target_id" binding:"required"`
+	Extension  string `json:"extension" binding:"required"`
+}
+
+type ArtworkResponse struct {
+	Success bool                 `json:"success"`
+	Data    *models.NostrArtwork `json:"data,omitempty"`
+	Error   string               `json:"error,omitempty"`
+	Message string               `json:"message,omitempty"`
+}
+
+// CreateArtworkUpload creates an artwork record and returns a presigned URL to upload the image to
+func (h *ArtworkHandler) CreateArtworkUpload(c *gin.Context) {
+	var req CreateArtworkUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ArtworkResponse{
+			Success: false,
+			Error:   "target_type, target_id and extension fields are required",
+		})
+		return
+	}
+
+	pubkey, ok := authenticatedPubkey(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, ArtworkResponse{
+			Success: false,
+			Error:   "authentication required",
+		})
+		return
+	}
+
+	artwork, err := h.artworkService.CreateArtworkUpload(
+		c.Request.Context(),
+		pubkey,
+		c.GetString("firebase_uid"),
+		req.TargetType,
+		req.TargetID,
+		req.Extension,
+	)
+	if err != nil {
+		if errors.Is(err, services.ErrUnsupportedImageFormat) || errors.Is(err, services.ErrInvalidArtworkTarget) {
+			c.JSON(http.StatusBadRequest, ArtworkResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+			return
+		}
+		log.Printf("Failed to create artwork upload for pubkey %s: %v", pubkey, err)
+		c.JSON(http.StatusInternalServerError, ArtworkResponse{
+			Success: false,
+			Error:   "failed to create artwork upload",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ArtworkResponse{
+		Success: true,
+		Data:    artwork,
+	})
+}
+
+// GetArtwork returns the processing status and variants of an artwork upload
+func (h *ArtworkHandler) GetArtwork(c *gin.Context) {
+	artwork, ok := h.getOwnedArtwork(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, ArtworkResponse{
+		Success: true,
+		Data:    artwork,
+	})
+}
+
+// ProcessArtwork starts processing an uploaded artwork image
+func (h *ArtworkHandler) ProcessArtwork(c *gin.Context) {
+	artwork, ok := h.getOwnedArtwork(c)
+	if !ok {
+		return
+	}
+
+	h.artworkService.ProcessArtworkAsync(c.Request.Context(), artwork.ID)
+
+	c.JSON(http.StatusAccepted, ArtworkResponse{
+		Success: true,
+		Data:    artwork,
+		Message: "artwork processing started",
+	})
+}
+
+// getOwnedArtwork loads the artwork from the path and verifies the caller uploaded it.
+// It writes the error response and returns false when the artwork cannot be used.
+func (h *ArtworkHandler) getOwnedArtwork(c *gin.Context) (*models.NostrArtwork, bool) {
+	artworkID := c.Param("artworkId")
+	if artworkID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "artwork ID is required"})
+		return nil, false
+	}
+
+	pubkey, ok := authenticatedPubkey(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		return nil, false
+	}
+
+	artwork, err := h.artworkService.GetArtwork(c.Request.Context(), artworkID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "artwork not found"})
+		return nil, false
+	}
+
+	if artwork.Pubkey != pubkey {
+		c.JSON(http.StatusForbidden, gin.H{"error": "you can only access your own artwork"})
+		return nil, false
+	}
+
+	return artwork, true
+}
@@ -0,0 +1,163 @@
+package handlers_test
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/wavlake/monorepo/internal/handlers"
+	"github.com/wavlake/monorepo/internal/models"
+	"github.com/wavlake/monorepo/internal/services"
+	"github.com/wavlake/monorepo/tests/mocks"
+	"github.com/wavlake/monorepo/tests/testutil"
+)
+
+var _ = Describe("ArtworkHandler", func() {
+	var (
+		ctrl               *gomock.Controller
+		mockArtworkService *mocks.MockArtworkServiceInterface
+		artworkHandler     *handlers.ArtworkHandler
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockArtworkService = mocks.NewMockArtworkServiceInterface(ctrl)
+		artworkHandler = handlers.NewArtworkHandler(mockArtworkService)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("CreateArtworkUpload", func() {
+		It("should return a presigned upload URL for the caller's track", func() {
+			c, w := testutil.SetupGinTestContext("POST", "/v1/artwork", map[string]interface{}{
+				"target_type": "track",
+				"target_id":   testutil.TestTrackID,
+				"extension":   "png",
+			})
+			testutil.SetAuthContext(c, testutil.TestFirebaseUID, testutil.TestPubkey)
+
+			mockArtworkService.EXPECT().
+				CreateArtworkUpload(gomock.Any(), testutil.TestPubkey, testutil.TestFirebaseUID, "track", testutil.TestTrackID, "png").
+				Return(&models.NostrArtwork{
+					ID:           testutil.TestArtworkID,
+					PresignedURL: "https://storage.googleapis.com/upload-url",
+					IsProcessing: true,
+				}, nil)
+
+			artworkHandler.CreateArtworkUpload(c)
+
+			response := testutil.AssertJSONResponse(w, http.StatusOK)
+			data := response["data"].(map[string]interface{})
+			Expect(data["id"]).To(Equal(testutil.TestArtworkID))
+			Expect(data["presigned_url"]).To(Equal("https://storage.googleapis.com/upload-url"))
+		})
+
+		It("should reject unsupported image formats", func() {
+			c, w := testutil.SetupGinTestContext("POST", "/v1/artwork", map[string]interface{}{
+				"target_type": "track",
+				"target_id":   testutil.TestTrackID,
+				"extension":   "gif",
+			})
+			testutil.SetAuthContext(c, testutil.TestFirebaseUID, testutil.TestPubkey)
+
+			mockArtworkService.EXPECT().
+				CreateArtworkUpload(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "gif").
+				Return(nil, fmt.Errorf("%w: gif", services.ErrUnsupportedImageFormat))
+
+			artworkHandler.CreateArtworkUpload(c)
+
+			response := testutil.AssertJSONResponse(w, http.StatusBadRequest)
+			Expect(response["error"]).To(ContainSubstring("unsupported image format"))
+		})
+
+		It("should reject targets the caller does not own", func() {
+			c, w := testutil.SetupGinTestContext("POST", "/v1/artwork", map[string]interface{}{
+				"target_type": "album",
+				"target_id":   testutil.TestAlbumID,
+				"extension":   "jpg",
+			})
+			testutil.SetAuthContext(c, testutil.TestFirebaseUID, testutil.TestPubkey2)
+
+			mockArtworkService.EXPECT().
+				CreateArtworkUpload(gomock.Any(), testutil.TestPubkey2, gomock.Any(), "album", testutil.TestAlbumID, "jpg").
+				Return(nil, fmt.Errorf("%w: album %s is not owned by this pubkey", services.ErrInvalidArtworkTarget, testutil.TestAlbumID))
+
+			artworkHandler.CreateArtworkUpload(c)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should require all fields", func() {
+			c, w := testutil.SetupGinTestContext("POST", "/v1/artwork", map[string]interface{}{
+				"extension": "jpg",
+			})
+			testutil.SetAuthContext(c, testutil.TestFirebaseUID, testutil.TestPubkey)
+
+			artworkHandler.CreateArtworkUpload(c)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Describe("GetArtwork", func() {
+		It("should return the variants to the uploader", func() {
+			c, w := testutil.SetupGinTestContext("GET", "/v1/artwork/"+testutil.TestArtworkID, nil)
+			c.Params = []gin.Param{{Key: "artworkId", Value: testutil.TestArtworkID}}
+			testutil.SetAuthContext(c, testutil.TestFirebaseUID, testutil.TestPubkey)
+
+			mockArtworkService.EXPECT().GetArtwork(gomock.Any(), testutil.TestArtworkID).Return(testutil.ValidNostrArtwork(), nil)
+
+			artworkHandler.GetArtwork(c)
+
+			response := testutil.AssertJSONResponse(w, http.StatusOK)
+			data := response["data"].(map[string]interface{})
+			Expect(data["variants"]).To(HaveLen(2))
+		})
+
+		It("should forbid other pubkeys", func() {
+			c, w := testutil.SetupGinTestContext("GET", "/v1/artwork/"+testutil.TestArtworkID, nil)
+			c.Params = []gin.Param{{Key: "artworkId", Value: testutil.TestArtworkID}}
+			testutil.SetAuthContext(c, testutil.TestFirebaseUID, testutil.TestPubkey2)
+
+			mockArtworkService.EXPECT().GetArtwork(gomock.Any(), testutil.TestArtworkID).Return(testutil.ValidNostrArtwork(), nil)
+
+			artworkHandler.GetArtwork(c)
+
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+		})
+	})
+
+	Describe("ProcessArtwork", func() {
+		It("should start processing in the background", func() {
+			c, w := testutil.SetupGinTestContext("POST", "/v1/artwork/"+testutil.TestArtworkID+"/process", nil)
+			c.Params = []gin.Param{{Key: "artworkId", Value: testutil.TestArtworkID}}
+			testutil.SetAuthContext(c, testutil.TestFirebaseUID, testutil.TestPubkey)
+
+			mockArtworkService.EXPECT().GetArtwork(gomock.Any(), testutil.TestArtworkID).Return(testutil.ValidNostrArtwork(), nil)
+			mockArtworkService.EXPECT().ProcessArtworkAsync(gomock.Any(), testutil.TestArtworkID)
+
+			artworkHandler.ProcessArtwork(c)
+
+			response := testutil.AssertJSONResponse(w, http.StatusAccepted)
+			Expect(response["message"]).To(Equal("artwork processing started"))
+		})
+
+		It("should return not found for unknown artwork", func() {
+			c, w := testutil.SetupGinTestContext("POST", "/v1/artwork/missing/process", nil)
+			c.Params = []gin.Param{{Key: "artworkId", Value: "missing"}}
+			testutil.SetAuthContext(c, testutil.TestFirebaseUID, testutil.TestPubkey)
+
+			mockArtworkService.EXPECT().GetArtwork(gomock.Any(), "missing").Return(nil, fmt.Errorf("failed to get artwork: not found"))
+
+			artworkHandler.ProcessArtwork(c)
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+})
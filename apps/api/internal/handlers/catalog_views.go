@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/wavlake/monorepo/internal/models"
+)
+
+// PublicArtist is the view of an artist profile served to anyone. It omits the creator's
+// Firebase UID and owner settings such as the default compression ladder; the owner's
+// endpoints return the full models.NostrArtist.
+type PublicArtist struct {
+	Pubkey     string    `json:"pubkey"`
+	Name       string    `json:"name"`
+	Bio        string    `json:"bio,omitempty"`
+	ArtworkURL string    `json:"artwork_url,omitempty"`
+	Website    string    `json:"website,omitempty"`
+	Twitter    string    `json:"twitter,omitempty"`
+	Instagram  string    `json:"instagram,omitempty"`
+	Youtube    string    `json:"youtube,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// newPublicArtist builds the public view of an artist profile
+func newPublicArtist(artist *models.NostrArtist) *PublicArtist {
+	return &PublicArtist{
+		Pubkey:     artist.Pubkey,
+		Name:       artist.Name,
+		Bio:        artist.Bio,
+		ArtworkURL: artist.ArtworkURL,
+		Website:    artist.Website,
+		Twitter:    artist.Twitter,
+		Instagram:  artist.Instagram,
+		Youtube:    artist.Youtube,
+		CreatedAt:  artist.CreatedAt,
+		UpdatedAt:  artist.UpdatedAt,
+	}
+}
+
+// PublicAlbum is the view of a published album served to anyone. It omits the creator's
+// Firebase UID and the draft and deleted flags, which are always false for public albums.
+type PublicAlbum struct {
+	ID              string                  `json:"id"`
+	Pubkey          string                  `json:"pubkey"`
+	Title           string                  `json:"title"`
+	Description     string                  `json:"description,omitempty"`
+	ArtworkURL      string                  `json:"artwork_url,omitempty"`
+	ArtworkVariants []models.ArtworkVariant `json:"artwork_variants,omitempty"`
+	Genre           string                  `json:"genre,omitempty"`
+	IsSingle        bool                    `json:"is_single"`
+	TrackIDs        []string                `json:"track_ids"`
+	PublishedAt     time.Time               `json:"published_at,omitempty"`
+	CreatedAt       time.Time               `json:"created_at"`
+	UpdatedAt       time.Time               `json:"updated_at"`
+}
+
+// newPublicAlbum builds the public view of an album
+func newPublicAlbum(album *models.NostrAlbum) *PublicAlbum {
+	return &PublicAlbum{
+		ID:              album.ID,
+		Pubkey:          album.Pubkey,
+		Title:           album.Title,
+		Description:     album.Description,
+		ArtworkURL:      album.ArtworkURL,
+		ArtworkVariants: album.ArtworkVariants,
+		Genre:           album.Genre,
+		IsSingle:        album.IsSingle,
+		TrackIDs:        album.TrackIDs,
+		PublishedAt:     album.PublishedAt,
+		CreatedAt:       album.CreatedAt,
+		UpdatedAt:       album.UpdatedAt,
+	}
+}
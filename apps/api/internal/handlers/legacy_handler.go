@@ -134,67 +134,123 @@ func (h *LegacyHandler) GetUserMetadata(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// LegacyTracksResponse is one page of GET /v1/legacy/tracks, in the envelope of the other list endpoints
+type LegacyTracksResponse struct {
+	Success    bool                 `json:"success"`
+	Data       []models.LegacyTrack `json:"data,omitempty"`
+	NextCursor string               `json:"next_cursor,omitempty"`
+	Error      string               `json:"error,omitempty"`
+}
+
+// LegacyArtistsResponse is one page of GET /v1/legacy/artists
+type LegacyArtistsResponse struct {
+	Success    bool                  `json:"success"`
+	Data       []models.LegacyArtist `json:"data,omitempty"`
+	NextCursor string                `json:"next_cursor,omitempty"`
+	Error      string                `json:"error,omitempty"`
+}
+
+// LegacyAlbumsResponse is one page of GET /v1/legacy/albums
+type LegacyAlbumsResponse struct {
+	Success    bool                 `json:"success"`
+	Data       []models.LegacyAlbum `json:"data,omitempty"`
+	NextCursor string               `json:"next_cursor,omitempty"`
+	Error      string               `json:"error,omitempty"`
+}
+
 // GetUserTracks handles GET /v1/legacy/tracks
+// Supports limit, cursor, sort and order query parameters, plus the
+// is_processing, deleted, has_public_versions and format filters
 func (h *LegacyHandler) GetUserTracks(c *gin.Context) {
 	firebaseUID := c.GetString("firebase_uid")
 	if firebaseUID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		c.JSON(http.StatusUnauthorized, LegacyTracksResponse{Success: false, Error: "authentication required"})
 		return
 	}
 
-	tracks, err := h.postgresService.GetUserTracks(c.Request.Context(), firebaseUID)
-	if err != nil && isDatabaseError(err) {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "database error"})
+	opts, err := parseListOptions(c, legacyTrackSortFields)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, LegacyTracksResponse{Success: false, Error: err.Error()})
 		return
 	}
 
-	if tracks == nil {
-		tracks = []models.LegacyTrack{}
+	filter, err := parseTrackFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, LegacyTracksResponse{Success: false, Error: err.Error()})
+		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"tracks": tracks})
+	page, err := h.postgresService.ListUserTracks(c.Request.Context(), firebaseUID, opts, filter)
+	if err != nil && isDatabaseError(err) {
+		log.Printf("PostgreSQL error getting tracks for %s: %v", firebaseUID, err)
+		c.JSON(http.StatusInternalServerError, LegacyTracksResponse{Success: false, Error: "database error"})
+		return
+	}
+
+	response := LegacyTracksResponse{Success: true}
+	if page != nil {
+		response.Data = page.Tracks
+		response.NextCursor = page.NextCursor
+	}
+	c.JSON(http.StatusOK, response)
 }
 
 // GetUserArtists handles GET /v1/legacy/artists
+// Supports limit, cursor, sort and order query parameters
 func (h *LegacyHandler) GetUserArtists(c *gin.Context) {
 	firebaseUID := c.GetString("firebase_uid")
 	if firebaseUID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		c.JSON(http.StatusUnauthorized, LegacyArtistsResponse{Success: false, Error: "authentication required"})
 		return
 	}
 
-	artists, err := h.postgresService.GetUserArtists(c.Request.Context(), firebaseUID)
+	opts, err := parseListOptions(c, legacyArtistSortFields)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, LegacyArtistsResponse{Success: false, Error: err.Error()})
+		return
+	}
+
+	page, err := h.postgresService.ListUserArtists(c.Request.Context(), firebaseUID, opts)
 	if err != nil && isDatabaseError(err) {
 		log.Printf("PostgreSQL error getting artists for %s: %v", firebaseUID, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "database error"})
+		c.JSON(http.StatusInternalServerError, LegacyArtistsResponse{Success: false, Error: "database error"})
 		return
 	}
 
-	if artists == nil {
-		artists = []models.LegacyArtist{}
+	response := LegacyArtistsResponse{Success: true}
+	if page != nil {
+		response.Data = page.Artists
+		response.NextCursor = page.NextCursor
 	}
-
-	c.JSON(http.StatusOK, gin.H{"artists": artists})
+	c.JSON(http.StatusOK, response)
 }
 
 // GetUserAlbums handles GET /v1/legacy/albums
+// Supports limit, cursor, sort and order query parameters
 func (h *LegacyHandler) GetUserAlbums(c *gin.Context) {
 	firebaseUID := c.GetString("firebase_uid")
 	if firebaseUID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		c.JSON(http.StatusUnauthorized, LegacyAlbumsResponse{Success: false, Error: "authentication required"})
 		return
 	}
 
-	albums, err := h.postgresService.GetUserAlbums(c.Request.Context(), firebaseUID)
+	opts, err := parseListOptions(c, legacyAlbumSortFields)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, LegacyAlbumsResponse{Success: false, Error: err.Error()})
+		return
+	}
+
+	page, err := h.postgresService.ListUserAlbums(c.Request.Context(), firebaseUID, opts)
 	if err != nil && isDatabaseError(err) {
 		log.Printf("PostgreSQL error getting albums for %s: %v", firebaseUID, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "database error"})
+		c.JSON(http.StatusInternalServerError, LegacyAlbumsResponse{Success: false, Error: "database error"})
 		return
 	}
 
-	if albums == nil {
-		albums = []models.LegacyAlbum{}
+	response := LegacyAlbumsResponse{Success: true}
+	if page != nil {
+		response.Data = page.Albums
+		response.NextCursor = page.NextCursor
 	}
-
-	c.JSON(http.StatusOK, gin.H{"albums": albums})
-}
\ No newline at end of file
+	c.JSON(http.StatusOK, response)
+}
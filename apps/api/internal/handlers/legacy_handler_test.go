@@ -330,8 +330,8 @@ var _ = Describe("LegacyHandler", func() {
 
 			It("should return user tracks when they exist", func() {
 				mockPostgresService.EXPECT().
-					GetUserTracks(gomock.Any(), testFirebaseUID).
-					Return(expectedTracks, nil)
+					ListUserTracks(gomock.Any(), testFirebaseUID, gomock.Any(), gomock.Any()).
+					Return(&models.LegacyTrackPage{Tracks: expectedTracks}, nil)
 
 				req := httptest.NewRequest(http.MethodGet, "/legacy/tracks", nil)
 				w := httptest.NewRecorder()
@@ -343,8 +343,8 @@ var _ = Describe("LegacyHandler", func() {
 				err := testutil.ParseJSONResponse(w.Body, &response)
 				Expect(err).ToNot(HaveOccurred())
 
-				Expect(response).To(HaveKey("tracks"))
-				tracks := response["tracks"].([]interface{})
+				Expect(response).To(HaveKey("data"))
+				tracks := response["data"].([]interface{})
 				Expect(tracks).To(HaveLen(2))
 
 				// Verify track data structure
@@ -355,8 +355,8 @@ var _ = Describe("LegacyHandler", func() {
 
 			It("should return empty array when user has no tracks", func() {
 				mockPostgresService.EXPECT().
-					GetUserTracks(gomock.Any(), testFirebaseUID).
-					Return([]models.LegacyTrack{}, nil)
+					ListUserTracks(gomock.Any(), testFirebaseUID, gomock.Any(), gomock.Any()).
+					Return(&models.LegacyTrackPage{Tracks: []models.LegacyTrack{}}, nil)
 
 				req := httptest.NewRequest(http.MethodGet, "/legacy/tracks", nil)
 				w := httptest.NewRecorder()
@@ -368,14 +368,13 @@ var _ = Describe("LegacyHandler", func() {
 				err := testutil.ParseJSONResponse(w.Body, &response)
 				Expect(err).ToNot(HaveOccurred())
 
-				Expect(response).To(HaveKey("tracks"))
-				tracks := response["tracks"].([]interface{})
-				Expect(tracks).To(BeEmpty())
+				Expect(response["success"]).To(BeTrue())
+				Expect(response).NotTo(HaveKey("data"))
 			})
 
 			It("should return empty array when user not found (non-database error)", func() {
 				mockPostgresService.EXPECT().
-					GetUserTracks(gomock.Any(), testFirebaseUID).
+					ListUserTracks(gomock.Any(), testFirebaseUID, gomock.Any(), gomock.Any()).
 					Return(nil, sql.ErrNoRows)
 
 				req := httptest.NewRequest(http.MethodGet, "/legacy/tracks", nil)
@@ -388,14 +387,13 @@ var _ = Describe("LegacyHandler", func() {
 				err := testutil.ParseJSONResponse(w.Body, &response)
 				Expect(err).ToNot(HaveOccurred())
 
-				Expect(response).To(HaveKey("tracks"))
-				tracks := response["tracks"].([]interface{})
-				Expect(tracks).To(BeEmpty())
+				Expect(response["success"]).To(BeTrue())
+				Expect(response).NotTo(HaveKey("data"))
 			})
 
 			It("should return database error when query fails with database error", func() {
 				mockPostgresService.EXPECT().
-					GetUserTracks(gomock.Any(), testFirebaseUID).
+					ListUserTracks(gomock.Any(), testFirebaseUID, gomock.Any(), gomock.Any()).
 					Return(nil, errors.New("relation tracks does not exist"))
 
 				req := httptest.NewRequest(http.MethodGet, "/legacy/tracks", nil)
@@ -414,7 +412,7 @@ var _ = Describe("LegacyHandler", func() {
 
 			It("should handle connection timeout errors", func() {
 				mockPostgresService.EXPECT().
-					GetUserTracks(gomock.Any(), testFirebaseUID).
+					ListUserTracks(gomock.Any(), testFirebaseUID, gomock.Any(), gomock.Any()).
 					Return(nil, errors.New("connection timeout"))
 
 				req := httptest.NewRequest(http.MethodGet, "/legacy/tracks", nil)
@@ -432,7 +430,7 @@ var _ = Describe("LegacyHandler", func() {
 
 			It("should handle network errors gracefully", func() {
 				mockPostgresService.EXPECT().
-					GetUserTracks(gomock.Any(), testFirebaseUID).
+					ListUserTracks(gomock.Any(), testFirebaseUID, gomock.Any(), gomock.Any()).
 					Return(nil, errors.New("network unreachable"))
 
 				req := httptest.NewRequest(http.MethodGet, "/legacy/tracks", nil)
@@ -442,9 +440,37 @@ var _ = Describe("LegacyHandler", func() {
 				Expect(w.Code).To(Equal(http.StatusInternalServerError))
 			})
 
+			It("should return the next cursor when more tracks exist", func() {
+				mockPostgresService.EXPECT().
+					ListUserTracks(gomock.Any(), testFirebaseUID, models.ListOptions{Limit: 2, SortBy: "title"}, gomock.Any()).
+					Return(&models.LegacyTrackPage{Tracks: expectedTracks, NextCursor: "next-page"}, nil)
+
+				req := httptest.NewRequest(http.MethodGet, "/legacy/tracks?limit=2&sort=title", nil)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var response map[string]interface{}
+				err := testutil.ParseJSONResponse(w.Body, &response)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(response["success"]).To(BeTrue())
+				Expect(response["data"]).To(HaveLen(2))
+				Expect(response["next_cursor"]).To(Equal("next-page"))
+			})
+
+			It("should reject an invalid sort order", func() {
+				req := httptest.NewRequest(http.MethodGet, "/legacy/tracks?order=sideways", nil)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+
 			It("should handle permission denied errors", func() {
 				mockPostgresService.EXPECT().
-					GetUserTracks(gomock.Any(), testFirebaseUID).
+					ListUserTracks(gomock.Any(), testFirebaseUID, gomock.Any(), gomock.Any()).
 					Return(nil, errors.New("permission denied on table tracks"))
 
 				req := httptest.NewRequest(http.MethodGet, "/legacy/tracks", nil)
@@ -508,8 +534,8 @@ var _ = Describe("LegacyHandler", func() {
 
 			It("should return user artists when they exist", func() {
 				mockPostgresService.EXPECT().
-					GetUserArtists(gomock.Any(), testFirebaseUID).
-					Return(expectedArtists, nil)
+					ListUserArtists(gomock.Any(), testFirebaseUID, gomock.Any()).
+					Return(&models.LegacyArtistPage{Artists: expectedArtists}, nil)
 
 				req := httptest.NewRequest(http.MethodGet, "/legacy/artists", nil)
 				w := httptest.NewRecorder()
@@ -521,8 +547,8 @@ var _ = Describe("LegacyHandler", func() {
 				err := testutil.ParseJSONResponse(w.Body, &response)
 				Expect(err).ToNot(HaveOccurred())
 
-				Expect(response).To(HaveKey("artists"))
-				artists := response["artists"].([]interface{})
+				Expect(response).To(HaveKey("data"))
+				artists := response["data"].([]interface{})
 				Expect(artists).To(HaveLen(2))
 
 				// Verify artist data structure
@@ -533,8 +559,8 @@ var _ = Describe("LegacyHandler", func() {
 
 			It("should return empty array when user has no artists", func() {
 				mockPostgresService.EXPECT().
-					GetUserArtists(gomock.Any(), testFirebaseUID).
-					Return([]models.LegacyArtist{}, nil)
+					ListUserArtists(gomock.Any(), testFirebaseUID, gomock.Any()).
+					Return(&models.LegacyArtistPage{Artists: []models.LegacyArtist{}}, nil)
 
 				req := httptest.NewRequest(http.MethodGet, "/legacy/artists", nil)
 				w := httptest.NewRecorder()
@@ -546,14 +572,13 @@ var _ = Describe("LegacyHandler", func() {
 				err := testutil.ParseJSONResponse(w.Body, &response)
 				Expect(err).ToNot(HaveOccurred())
 
-				Expect(response).To(HaveKey("artists"))
-				artists := response["artists"].([]interface{})
-				Expect(artists).To(BeEmpty())
+				Expect(response["success"]).To(BeTrue())
+				Expect(response).NotTo(HaveKey("data"))
 			})
 
 			It("should return empty array when user not found (non-database error)", func() {
 				mockPostgresService.EXPECT().
-					GetUserArtists(gomock.Any(), testFirebaseUID).
+					ListUserArtists(gomock.Any(), testFirebaseUID, gomock.Any()).
 					Return(nil, sql.ErrNoRows)
 
 				req := httptest.NewRequest(http.MethodGet, "/legacy/artists", nil)
@@ -566,14 +591,13 @@ var _ = Describe("LegacyHandler", func() {
 				err := testutil.ParseJSONResponse(w.Body, &response)
 				Expect(err).ToNot(HaveOccurred())
 
-				Expect(response).To(HaveKey("artists"))
-				artists := response["artists"].([]interface{})
-				Expect(artists).To(BeEmpty())
+				Expect(response["success"]).To(BeTrue())
+				Expect(response).NotTo(HaveKey("data"))
 			})
 
 			It("should return database error when query fails with database error", func() {
 				mockPostgresService.EXPECT().
-					GetUserArtists(gomock.Any(), testFirebaseUID).
+					ListUserArtists(gomock.Any(), testFirebaseUID, gomock.Any()).
 					Return(nil, errors.New("relation artists does not exist"))
 
 				req := httptest.NewRequest(http.MethodGet, "/legacy/artists", nil)
@@ -592,7 +616,7 @@ var _ = Describe("LegacyHandler", func() {
 
 			It("should handle connection timeout errors", func() {
 				mockPostgresService.EXPECT().
-					GetUserArtists(gomock.Any(), testFirebaseUID).
+					ListUserArtists(gomock.Any(), testFirebaseUID, gomock.Any()).
 					Return(nil, errors.New("connection timeout"))
 
 				req := httptest.NewRequest(http.MethodGet, "/legacy/artists", nil)
@@ -610,7 +634,7 @@ var _ = Describe("LegacyHandler", func() {
 
 			It("should handle network errors gracefully", func() {
 				mockPostgresService.EXPECT().
-					GetUserArtists(gomock.Any(), testFirebaseUID).
+					ListUserArtists(gomock.Any(), testFirebaseUID, gomock.Any()).
 					Return(nil, errors.New("network unreachable"))
 
 				req := httptest.NewRequest(http.MethodGet, "/legacy/artists", nil)
@@ -622,7 +646,7 @@ var _ = Describe("LegacyHandler", func() {
 
 			It("should handle permission denied errors", func() {
 				mockPostgresService.EXPECT().
-					GetUserArtists(gomock.Any(), testFirebaseUID).
+					ListUserArtists(gomock.Any(), testFirebaseUID, gomock.Any()).
 					Return(nil, errors.New("permission denied on table artists"))
 
 				req := httptest.NewRequest(http.MethodGet, "/legacy/artists", nil)
@@ -686,8 +710,8 @@ var _ = Describe("LegacyHandler", func() {
 
 			It("should return user albums when they exist", func() {
 				mockPostgresService.EXPECT().
-					GetUserAlbums(gomock.Any(), testFirebaseUID).
-					Return(expectedAlbums, nil)
+					ListUserAlbums(gomock.Any(), testFirebaseUID, gomock.Any()).
+					Return(&models.LegacyAlbumPage{Albums: expectedAlbums}, nil)
 
 				req := httptest.NewRequest(http.MethodGet, "/legacy/albums", nil)
 				w := httptest.NewRecorder()
@@ -699,8 +723,8 @@ var _ = Describe("LegacyHandler", func() {
 				err := testutil.ParseJSONResponse(w.Body, &response)
 				Expect(err).ToNot(HaveOccurred())
 
-				Expect(response).To(HaveKey("albums"))
-				albums := response["albums"].([]interface{})
+				Expect(response).To(HaveKey("data"))
+				albums := response["data"].([]interface{})
 				Expect(albums).To(HaveLen(2))
 
 				// Verify album data structure
@@ -711,8 +735,8 @@ var _ = Describe("LegacyHandler", func() {
 
 			It("should return empty array when user has no albums", func() {
 				mockPostgresService.EXPECT().
-					GetUserAlbums(gomock.Any(), testFirebaseUID).
-					Return([]models.LegacyAlbum{}, nil)
+					ListUserAlbums(gomock.Any(), testFirebaseUID, gomock.Any()).
+					Return(&models.LegacyAlbumPage{Albums: []models.LegacyAlbum{}}, nil)
 
 				req := httptest.NewRequest(http.MethodGet, "/legacy/albums", nil)
 				w := httptest.NewRecorder()
@@ -724,14 +748,13 @@ var _ = Describe("LegacyHandler", func() {
 				err := testutil.ParseJSONResponse(w.Body, &response)
 				Expect(err).ToNot(HaveOccurred())
 
-				Expect(response).To(HaveKey("albums"))
-				albums := response["albums"].([]interface{})
-				Expect(albums).To(BeEmpty())
+				Expect(response["success"]).To(BeTrue())
+				Expect(response).NotTo(HaveKey("data"))
 			})
 
 			It("should return empty array when user not found (non-database error)", func() {
 				mockPostgresService.EXPECT().
-					GetUserAlbums(gomock.Any(), testFirebaseUID).
+					ListUserAlbums(gomock.Any(), testFirebaseUID, gomock.Any()).
 					Return(nil, sql.ErrNoRows)
 
 				req := httptest.NewRequest(http.MethodGet, "/legacy/albums", nil)
@@ -744,14 +767,13 @@ var _ = Describe("LegacyHandler", func() {
 				err := testutil.ParseJSONResponse(w.Body, &response)
 				Expect(err).ToNot(HaveOccurred())
 
-				Expect(response).To(HaveKey("albums"))
-				albums := response["albums"].([]interface{})
-				Expect(albums).To(BeEmpty())
+				Expect(response["success"]).To(BeTrue())
+				Expect(response).NotTo(HaveKey("data"))
 			})
 
 			It("should return database error when query fails with database error", func() {
 				mockPostgresService.EXPECT().
-					GetUserAlbums(gomock.Any(), testFirebaseUID).
+					ListUserAlbums(gomock.Any(), testFirebaseUID, gomock.Any()).
 					Return(nil, errors.New("relation albums does not exist"))
 
 				req := httptest.NewRequest(http.MethodGet, "/legacy/albums", nil)
@@ -770,7 +792,7 @@ var _ = Describe("LegacyHandler", func() {
 
 			It("should handle connection timeout errors", func() {
 				mockPostgresService.EXPECT().
-					GetUserAlbums(gomock.Any(), testFirebaseUID).
+					ListUserAlbums(gomock.Any(), testFirebaseUID, gomock.Any()).
 					Return(nil, errors.New("connection timeout"))
 
 				req := httptest.NewRequest(http.MethodGet, "/legacy/albums", nil)
@@ -788,7 +810,7 @@ var _ = Describe("LegacyHandler", func() {
 
 			It("should handle network errors gracefully", func() {
 				mockPostgresService.EXPECT().
-					GetUserAlbums(gomock.Any(), testFirebaseUID).
+					ListUserAlbums(gomock.Any(), testFirebaseUID, gomock.Any()).
 					Return(nil, errors.New("network unreachable"))
 
 				req := httptest.NewRequest(http.MethodGet, "/legacy/albums", nil)
@@ -800,7 +822,7 @@ var _ = Describe("LegacyHandler", func() {
 
 			It("should handle permission denied errors", func() {
 				mockPostgresService.EXPECT().
-					GetUserAlbums(gomock.Any(), testFirebaseUID).
+					ListUserAlbums(gomock.Any(), testFirebaseUID, gomock.Any()).
 					Return(nil, errors.New("permission denied on table albums"))
 
 				req := httptest.NewRequest(http.MethodGet, "/legacy/albums", nil)
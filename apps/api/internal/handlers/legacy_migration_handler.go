@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wavlake/monorepo/internal/models"
+	"github.com/wavlake/monorepo/internal/services"
+)
+
+type LegacyMigrationHandler struct {
+	migrationService services.LegacyMigrationServiceInterface
+}
+
+// NewLegacyMigrationHandler creates a new legacy migration handler
+func NewLegacyMigrationHandler(migrationService services.LegacyMigrationServiceInterface) *LegacyMigrationHandler {
+	return &LegacyMigrationHandler{
+		migrationService: migrationService,
+	}
+}
+
+type LegacyMigrationResponse struct {
+	Success bool                          `json:"success"`
+	Data    *models.LegacyMigrationReport `json:"data,omitempty"`
+	Error   string                        `json:"error,omitempty"`
+}
+
+// MigrateTracks handles POST /v1/legacy/migrate
+// Copies the caller's legacy tracks into new tracks owned by the authenticated pubkey.
+// Pass ?dry_run=true to preview the migration without writing anything.
+func (h *LegacyMigrationHandler) MigrateTracks(c *gin.Context) {
+	pubkey, ok := authenticatedPubkey(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, LegacyMigrationResponse{
+			Success: false,
+			Error:   "authentication required",
+		})
+		return
+	}
+
+	firebaseUID := c.GetString("firebase_uid")
+	if firebaseUID == "" {
+		c.JSON(http.StatusUnauthorized, LegacyMigrationResponse{
+			Success: false,
+			Error:   "Failed to find an associated Firebase UID",
+		})
+		return
+	}
+
+	dryRun := false
+	if value := c.Query("dry_run"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, LegacyMigrationResponse{
+				Success: false,
+				Error:   "dry_run must be a boolean",
+			})
+			return
+		}
+		dryRun = parsed
+	}
+
+	report, err := h.migrationService.MigrateUserTracks(c.Request.Context(), firebaseUID, pubkey, dryRun)
+	if err != nil {
+		if errors.Is(err, services.ErrPubkeyNotLinked) {
+			c.JSON(http.StatusForbidden, LegacyMigrationResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+			return
+		}
+		log.Printf("Legacy migration failed for %s: %v", firebaseUID, err)
+		c.JSON(http.StatusInternalServerError, LegacyMigrationResponse{
+			Success: false,
+			Error:   "failed to migrate legacy tracks",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, LegacyMigrationResponse{
+		Success: true,
+		Data:    report,
+	})
+}
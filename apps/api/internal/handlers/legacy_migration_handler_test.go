@@ -0,0 +1,111 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/wavlake/monorepo/internal/handlers"
+	"github.com/wavlake/monorepo/internal/models"
+	"github.com/wavlake/monorepo/internal/services"
+	"github.com/wavlake/monorepo/tests/mocks"
+	"github.com/wavlake/monorepo/tests/testutil"
+)
+
+var _ = Describe("LegacyMigrationHandler", func() {
+	var (
+		ctrl                 *gomock.Controller
+		mockMigrationService *mocks.MockLegacyMigrationServiceInterface
+		migrationHandler     *handlers.LegacyMigrationHandler
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockMigrationService = mocks.NewMockLegacyMigrationServiceInterface(ctrl)
+		migrationHandler = handlers.NewLegacyMigrationHandler(mockMigrationService)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("MigrateTracks", func() {
+		It("should return per-track results", func() {
+			c, w := testutil.SetupGinTestContext("POST", "/v1/legacy/migrate", nil)
+			testutil.SetAuthContext(c, testutil.TestFirebaseUID, testutil.TestPubkey)
+
+			mockMigrationService.EXPECT().
+				MigrateUserTracks(gomock.Any(), testutil.TestFirebaseUID, testutil.TestPubkey, false).
+				Return(&models.LegacyMigrationReport{
+					FirebaseUID: testutil.TestFirebaseUID,
+					Pubkey:      testutil.TestPubkey,
+					Migrated:    1,
+					Skipped:     1,
+					Results: []models.LegacyMigrationResult{
+						{LegacyTrackID: "legacy-1", Status: services.MigrationStatusMigrated, TrackID: testutil.TestTrackID},
+						{LegacyTrackID: "legacy-2", Status: services.MigrationStatusSkipped, Message: "already migrated"},
+					},
+				}, nil)
+
+			migrationHandler.MigrateTracks(c)
+
+			response := testutil.AssertJSONResponse(w, http.StatusOK)
+			data := response["data"].(map[string]interface{})
+			Expect(data["migrated"]).To(BeNumerically("==", 1))
+			Expect(data["results"]).To(HaveLen(2))
+		})
+
+		It("should pass dry_run through to the service", func() {
+			c, w := testutil.SetupGinTestContext("POST", "/v1/legacy/migrate?dry_run=true", nil)
+			testutil.SetAuthContext(c, testutil.TestFirebaseUID, testutil.TestPubkey)
+
+			mockMigrationService.EXPECT().
+				MigrateUserTracks(gomock.Any(), testutil.TestFirebaseUID, testutil.TestPubkey, true).
+				Return(&models.LegacyMigrationReport{DryRun: true}, nil)
+
+			migrationHandler.MigrateTracks(c)
+
+			response := testutil.AssertJSONResponse(w, http.StatusOK)
+			data := response["data"].(map[string]interface{})
+			Expect(data["dry_run"]).To(BeTrue())
+		})
+
+		It("should reject an invalid dry_run value", func() {
+			c, w := testutil.SetupGinTestContext("POST", "/v1/legacy/migrate?dry_run=maybe", nil)
+			testutil.SetAuthContext(c, testutil.TestFirebaseUID, testutil.TestPubkey)
+
+			migrationHandler.MigrateTracks(c)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should forbid migrating to an unlinked pubkey", func() {
+			c, w := testutil.SetupGinTestContext("POST", "/v1/legacy/migrate", nil)
+			testutil.SetAuthContext(c, testutil.TestFirebaseUID, testutil.TestPubkey2)
+
+			mockMigrationService.EXPECT().
+				MigrateUserTracks(gomock.Any(), testutil.TestFirebaseUID, testutil.TestPubkey2, false).
+				Return(nil, services.ErrPubkeyNotLinked)
+
+			migrationHandler.MigrateTracks(c)
+
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+		})
+
+		It("should return server error when the legacy database fails", func() {
+			c, w := testutil.SetupGinTestContext("POST", "/v1/legacy/migrate", nil)
+			testutil.SetAuthContext(c, testutil.TestFirebaseUID, testutil.TestPubkey)
+
+			mockMigrationService.EXPECT().
+				MigrateUserTracks(gomock.Any(), gomock.Any(), gomock.Any(), false).
+				Return(nil, errors.New("failed to get legacy tracks: connection refused"))
+
+			migrationHandler.MigrateTracks(c)
+
+			Expect(w.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+})
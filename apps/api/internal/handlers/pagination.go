@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wavlake/monorepo/internal/models"
+	"github.com/wavlake/monorepo/internal/utils"
+)
+
+// Sort fields accepted by the list endpoints
+var (
+	trackSortFields        = []string{"created_at", "updated_at"}
+	legacyTrackSortFields  = []string{"created_at", "updated_at", "title"}
+	legacyArtistSortFields = []string{"created_at", "updated_at", "name"}
+	legacyAlbumSortFields  = []string{"created_at", "updated_at", "title"}
+)
+
+// parseListOptions reads the limit, cursor, sort and order query parameters
+func parseListOptions(c *gin.Context, sortFields []string) (models.ListOptions, error) {
+	opts := models.ListOptions{
+		Cursor:    c.Query("cursor"),
+		SortBy:    c.Query("sort"),
+		SortOrder: c.Query("order"),
+	}
+
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > utils.MaxPageLimit {
+			return opts, fmt.Errorf("limit must be between 1 and %d", utils.MaxPageLimit)
+		}
+		opts.Limit = limit
+	}
+
+	if opts.SortBy != "" {
+		allowed := false
+		for _, field := range sortFields {
+			if opts.SortBy == field {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return opts, fmt.Errorf("sort must be one of %v", sortFields)
+		}
+	}
+
+	if opts.SortOrder != "" && opts.SortOrder != "asc" && opts.SortOrder != "desc" {
+		return opts, fmt.Errorf("order must be asc or desc")
+	}
+
+	if opts.Cursor != "" {
+		sortBy, sortOrder := utils.NormalizeSort(opts.SortBy, opts.SortOrder)
+		if _, err := utils.DecodeCursor(opts.Cursor, sortBy, sortOrder); err != nil {
+			if errors.Is(err, utils.ErrCursorMismatch) {
+				return opts, fmt.Errorf("cursor does not match sort and order")
+			}
+			return opts, fmt.Errorf("invalid cursor")
+		}
+	}
+
+	return opts, nil
+}
+
+// parseTrackFilter reads the optional track filter query parameters
+func parseTrackFilter(c *gin.Context) (models.TrackFilter, error) {
+	filter := models.TrackFilter{
+		Format: c.Query("format"),
+	}
+
+	flags := map[string]**bool{
+		"is_processing":       &filter.IsProcessing,
+		"deleted":             &filter.Deleted,
+		"has_public_versions": &filter.HasPublicVersions,
+	}
+	for name, target := range flags {
+		value := c.Query(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, fmt.Errorf("%s must be a boolean", name)
+		}
+		*target = &parsed
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,365 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wavlake/monorepo/internal/models"
+	"github.com/wavlake/monorepo/internal/services"
+	"github.com/wavlake/monorepo/internal/utils"
+)
+
+// Stream delivery modes
+const (
+	// StreamModeProxy streams the object through the API with Range support
+	StreamModeProxy = "proxy"
+	// StreamModeRedirect redirects the listener to a short-lived signed storage URL
+	StreamModeRedirect = "redirect"
+)
+
+type StreamHandler struct {
+	nostrTrackService services.NostrTrackServiceInterface
+	storageService    services.StorageServiceInterface
+	mode              string
+	signedURLExpiry   time.Duration
+}
+
+// NewStreamHandler creates a new stream handler. mode is StreamModeProxy or StreamModeRedirect;
+// signedURLExpiry is the lifetime of redirect URLs.
+func NewStreamHandler(nostrTrackService services.NostrTrackServiceInterface, storageService services.StorageServiceInterface, mode string, signedURLExpiry time.Duration) *StreamHandler {
+	if mode != StreamModeRedirect {
+		mode = StreamModeProxy
+	}
+	return &StreamHandler{
+		nostrTrackService: nostrTrackService,
+		storageService:    storageService,
+		mode:              mode,
+		signedURLExpiry:   signedURLExpiry,
+	}
+}
+
+// StreamTrack handles GET /v1/stream/:trackId/:versionId and, for HLS packages,
+// GET /v1/stream/:trackId/:versionId/*file
+// The owner (NIP-98 signed by the track's pubkey) can stream any version; everyone else can
+// only stream public versions of published tracks. HLS versions redirect to their master
+// playlist, and the renditions and segments it references are served from the same path.
+// Plays are not counted here; range and segment requests make request counts a poor measure.
+func (h *StreamHandler) StreamTrack(c *gin.Context) {
+	trackID := c.Param("trackId")
+	versionID := c.Param("versionId")
+	if trackID == "" || versionID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "track ID and version ID are required"})
+		return
+	}
+
+	track, err := h.nostrTrackService.GetTrack(c.Request.Context(), trackID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "track not found"})
+		return
+	}
+
+	version := findCompressionVersion(track, versionID)
+	if version == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "version not found"})
+		return
+	}
+
+	pubkey, _ := authenticatedPubkey(c)
+	isOwner := pubkey != "" && pubkey == track.Pubkey
+	if !isOwner && (!isPubliclyVisible(track) || !version.IsPublic) {
+		// Hide the existence of private versions and unpublished tracks
+		c.JSON(http.StatusNotFound, gin.H{"error": "version not found"})
+		return
+	}
+
+	file := strings.TrimPrefix(c.Param("file"), "/")
+	if version.Format == utils.HLSFormat {
+		if file == "" {
+			// Playlists reference renditions and segments relative to the master playlist's URL
+			c.Redirect(http.StatusFound, streamURL(trackID, version))
+			return
+		}
+		h.streamHLSFile(c, track, version, file)
+		return
+	}
+	if file != "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "file not found"})
+		return
+	}
+
+	objectName := services.VersionObjectKey(h.storageService, version)
+	if objectName == "" {
+		log.Printf("Cannot resolve storage object for track %s version %s: %s", trackID, versionID, version.URL)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to locate audio"})
+		return
+	}
+
+	h.serveObject(c, track, version, objectName, getStreamContentType(version.Format), h.mode == StreamModeRedirect)
+}
+
+// streamHLSFile serves a playlist or segment of an HLS package. Playlists are always proxied,
+// since the relative paths inside them must resolve against the stream URL rather than storage.
+func (h *StreamHandler) streamHLSFile(c *gin.Context, track *models.NostrTrack, version *models.CompressionVersion, file string) {
+	prefix := services.VersionObjectPrefix(h.storageService, version)
+	if prefix == "" {
+		log.Printf("Cannot resolve HLS package for track %s version %s: %s", track.ID, version.ID, version.URL)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to locate audio"})
+		return
+	}
+
+	// Cleaning against the root keeps ".." from escaping the package
+	name := strings.TrimPrefix(path.Clean("/"+file), "/")
+	if name == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "file not found"})
+		return
+	}
+
+	isPlaylist := path.Ext(name) == ".m3u8"
+	h.serveObject(c, track, version, prefix+name, utils.GetHLSContentType(name), h.mode == StreamModeRedirect && !isPlaylist)
+}
+
+// serveObject streams a stored object with Range support, or redirects to a signed URL for it
+// when redirect is set
+func (h *StreamHandler) serveObject(c *gin.Context, track *models.NostrTrack, version *models.CompressionVersion, objectName, fallbackContentType string, redirect bool) {
+	if redirect {
+		signedURL, err := h.storageService.GenerateSignedURL(c.Request.Context(), objectName, models.SignedURLOptions{
+			Method:     http.MethodGet,
+			Expiration: h.signedURLExpiry,
+		})
+		if err != nil {
+			log.Printf("Failed to sign stream URL for track %s version %s: %v", track.ID, version.ID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate stream URL"})
+			return
+		}
+		c.Header("Cache-Control", "private, no-store")
+		c.Redirect(http.StatusFound, signedURL)
+		return
+	}
+
+	info, err := h.storageService.GetObjectInfo(c.Request.Context(), objectName)
+	if err != nil {
+		log.Printf("Failed to get stream object %s: %v", objectName, err)
+		c.JSON(http.StatusNotFound, gin.H{"error": "audio not found"})
+		return
+	}
+
+	contentType := info.ContentType
+	if contentType == "" {
+		contentType = fallbackContentType
+	}
+	c.Header("Content-Type", contentType)
+	c.Header("Accept-Ranges", "bytes")
+	if info.ETag != "" {
+		c.Header("ETag", quoteETag(info.ETag))
+	}
+	if version.IsPublic && !track.Deleted {
+		c.Header("Cache-Control", "public, max-age=3600")
+	} else {
+		c.Header("Cache-Control", "private, no-store")
+	}
+
+	content := &objectReadSeeker{
+		ctx:            c.Request.Context(),
+		storageService: h.storageService,
+		objectName:     objectName,
+		size:           info.Size,
+	}
+	defer content.Close()
+
+	// ServeContent handles Range, If-Range, If-None-Match and If-Modified-Since
+	http.ServeContent(c.Writer, c.Request, "", info.UpdatedAt, content)
+	c.Writer.WriteHeaderNow() // Flush bodiless responses such as 304 Not Modified
+}
+
+// DownloadURLResponse carries a signed URL for an owner download or preview
+type DownloadURLResponse struct {
+	Success bool             `json:"success"`
+	Data    *DownloadURLData `json:"data,omitempty"`
+	Error   string           `json:"error,omitempty"`
+}
+
+type DownloadURLData struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// GetDownloadURL handles GET /v1/tracks/:trackId/download
+// Returns a signed URL for the owner to download the original upload, or, with ?version=<id>,
+// to fetch any compressed version including private ones. ?inline=true requests an inline
+// Content-Disposition for previewing in the browser instead of an attachment.
+func (h *StreamHandler) GetDownloadURL(c *gin.Context) {
+	pubkey, ok := authenticatedPubkey(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, DownloadURLResponse{Success: false, Error: "authentication required"})
+		return
+	}
+
+	trackID := c.Param("trackId")
+	if trackID == "" {
+		c.JSON(http.StatusBadRequest, DownloadURLResponse{Success: false, Error: "track ID is required"})
+		return
+	}
+
+	track, err := h.nostrTrackService.GetTrack(c.Request.Context(), trackID)
+	if err != nil || track.Deleted || track.Pubkey != pubkey {
+		// Non-owners get the same response as a missing track
+		c.JSON(http.StatusNotFound, DownloadURLResponse{Success: false, Error: "track not found"})
+		return
+	}
+
+	objectName := services.OriginalObjectKey(h.storageService, track)
+	extension := track.Extension
+	if versionID := c.Query("version"); versionID != "" {
+		version := findCompressionVersion(track, versionID)
+		if version == nil {
+			c.JSON(http.StatusNotFound, DownloadURLResponse{Success: false, Error: "version not found"})
+			return
+		}
+		if version.Format == utils.HLSFormat {
+			c.JSON(http.StatusBadRequest, DownloadURLResponse{Success: false, Error: "HLS versions are played from their playlist URL"})
+			return
+		}
+		objectName = services.VersionObjectKey(h.storageService, version)
+		extension = utils.OutputExtension(version.Format)
+	}
+
+	if objectName == "" {
+		c.JSON(http.StatusNotFound, DownloadURLResponse{Success: false, Error: "file not available"})
+		return
+	}
+
+	dispositionType := "attachment"
+	if c.Query("inline") == "true" {
+		dispositionType = "inline"
+	}
+
+	expiresAt := time.Now().Add(h.signedURLExpiry)
+	signedURL, err := h.storageService.GenerateSignedURL(c.Request.Context(), objectName, models.SignedURLOptions{
+		Method:             http.MethodGet,
+		Expiration:         h.signedURLExpiry,
+		ContentDisposition: downloadContentDisposition(dispositionType, track, extension),
+	})
+	if err != nil {
+		log.Printf("Failed to sign download URL for track %s: %v", trackID, err)
+		c.JSON(http.StatusInternalServerError, DownloadURLResponse{Success: false, Error: "failed to generate download URL"})
+		return
+	}
+
+	c.Header("Cache-Control", "private, no-store")
+	c.JSON(http.StatusOK, DownloadURLResponse{
+		Success: true,
+		Data:    &DownloadURLData{URL: signedURL, ExpiresAt: expiresAt},
+	})
+}
+
+// downloadContentDisposition builds a Content-Disposition named after the track title,
+// falling back to the track ID
+func downloadContentDisposition(dispositionType string, track *models.NostrTrack, extension string) string {
+	name := strings.Map(func(r rune) rune {
+		if r < 0x20 || r == '"' || r == '\\' || r == '/' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(track.Title))
+	if name == "" {
+		name = track.ID
+	}
+	if extension != "" {
+		name += "." + strings.TrimPrefix(extension, ".")
+	}
+	return mime.FormatMediaType(dispositionType, map[string]string{"filename": name})
+}
+
+// findCompressionVersion returns the version with the given ID, or nil
+func findCompressionVersion(track *models.NostrTrack, versionID string) *models.CompressionVersion {
+	for i := range track.CompressionVersions {
+		if track.CompressionVersions[i].ID == versionID {
+			return &track.CompressionVersions[i]
+		}
+	}
+	return nil
+}
+
+// getStreamContentType returns the MIME type for a compressed version format
+func getStreamContentType(format string) string {
+	return utils.ContentTypeForFormat(format)
+}
+
+// quoteETag wraps a storage ETag in quotes as required by RFC 7232
+func quoteETag(etag string) string {
+	if strings.HasPrefix(etag, `"`) || strings.HasPrefix(etag, `W/"`) {
+		return etag
+	}
+	return fmt.Sprintf("%q", etag)
+}
+
+// objectReadSeeker adapts ranged storage reads to io.ReadSeeker so http.ServeContent can
+// serve byte ranges without downloading the whole object
+type objectReadSeeker struct {
+	ctx            context.Context
+	storageService services.StorageServiceInterface
+	objectName     string
+	size           int64
+	offset         int64
+	reader         io.ReadCloser
+}
+
+func (r *objectReadSeeker) Read(p []byte) (int, error) {
+	if r.offset >= r.size {
+		return 0, io.EOF
+	}
+
+	if r.reader == nil {
+		reader, err := r.storageService.GetObjectRangeReader(r.ctx, r.objectName, r.offset, -1)
+		if err != nil {
+			return 0, err
+		}
+		r.reader = reader
+	}
+
+	n, err := r.reader.Read(p)
+	r.offset += int64(n)
+	return n, err
+}
+
+func (r *objectReadSeeker) Seek(offset int64, whence int) (int64, error) {
+	var target int64
+	switch whence {
+	case io.SeekStart:
+		target = offset
+	case io.SeekCurrent:
+		target = r.offset + offset
+	case io.SeekEnd:
+		target = r.size + offset
+	default:
+		return 0, errors.New("invalid whence")
+	}
+	if target < 0 {
+		return 0, errors.New("negative position")
+	}
+
+	if target != r.offset {
+		// The next Read opens a new range reader at the target position
+		_ = r.Close() // #nosec G104 -- Closing a reader we are discarding
+		r.offset = target
+	}
+	return target, nil
+}
+
+func (r *objectReadSeeker) Close() error {
+	if r.reader == nil {
+		return nil
+	}
+	err := r.reader.Close()
+	r.reader = nil
+	return err
+}
@@ -0,0 +1,339 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/wavlake/monorepo/internal/handlers"
+	"github.com/wavlake/monorepo/internal/models"
+	"github.com/wavlake/monorepo/internal/utils"
+	"github.com/wavlake/monorepo/tests/mocks"
+	"github.com/wavlake/monorepo/tests/testutil"
+)
+
+var _ = Describe("StreamHandler", func() {
+	const (
+		bucketPrefix = "https://storage.googleapis.com/test-bucket/"
+		objectName   = "compressed/test-track-123/128k.mp3"
+		audioData    = "0123456789abcdef"
+	)
+
+	var (
+		ctrl                  *gomock.Controller
+		mockNostrTrackService *mocks.MockNostrTrackServiceInterface
+		mockStorageService    *mocks.MockStorageServiceInterface
+		streamHandler         *handlers.StreamHandler
+		track                 *models.NostrTrack
+	)
+
+	newStreamContext := func(versionID string) (*gin.Context, *httptest.ResponseRecorder) {
+		c, w := testutil.SetupGinTestContext("GET", "/v1/stream/"+testutil.TestTrackID+"/"+versionID, nil)
+		c.Params = []gin.Param{{Key: "trackId", Value: testutil.TestTrackID}, {Key: "versionId", Value: versionID}}
+		return c, w
+	}
+
+	expectObject := func() {
+		mockStorageService.EXPECT().GetPublicURL("").Return(bucketPrefix).AnyTimes()
+		mockStorageService.EXPECT().
+			GetObjectInfo(gomock.Any(), objectName).
+			Return(&models.FileMetadata{
+				Name:        objectName,
+				Size:        int64(len(audioData)),
+				ContentType: "audio/mpeg",
+				ETag:        "etag-1",
+				UpdatedAt:   time.Now(),
+			}, nil)
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockNostrTrackService = mocks.NewMockNostrTrackServiceInterface(ctrl)
+		mockStorageService = mocks.NewMockStorageServiceInterface(ctrl)
+		streamHandler = handlers.NewStreamHandler(mockNostrTrackService, mockStorageService, handlers.StreamModeProxy, 5*time.Minute)
+
+		privateVersion := testutil.ValidCompressionVersion()
+		privateVersion.ID = "v2-320k"
+		privateVersion.IsPublic = false
+		privateVersion.URL = bucketPrefix + objectName
+		publicVersion := testutil.ValidCompressionVersion()
+		publicVersion.URL = bucketPrefix + objectName
+
+		track = testutil.ValidNostrTrack()
+		track.CompressionVersions = []models.CompressionVersion{publicVersion, privateVersion}
+		mockNostrTrackService.EXPECT().GetTrack(gomock.Any(), testutil.TestTrackID).Return(track, nil).AnyTimes()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("StreamTrack", func() {
+		It("should serve a requested byte range of a public version", func() {
+			c, w := newStreamContext("v1-128k")
+			c.Request.Header.Set("Range", "bytes=4-7")
+
+			expectObject()
+			mockStorageService.EXPECT().
+				GetObjectRangeReader(gomock.Any(), objectName, int64(4), int64(-1)).
+				DoAndReturn(func(_ context.Context, _ string, offset, _ int64) (io.ReadCloser, error) {
+					return io.NopCloser(strings.NewReader(audioData[offset:])), nil
+				})
+
+			streamHandler.StreamTrack(c)
+
+			Expect(w.Code).To(Equal(http.StatusPartialContent))
+			Expect(w.Body.String()).To(Equal("4567"))
+			Expect(w.Header().Get("Content-Range")).To(Equal("bytes 4-7/16"))
+			Expect(w.Header().Get("ETag")).To(Equal(`"etag-1"`))
+		})
+
+		It("should answer 304 when the ETag matches", func() {
+			c, w := newStreamContext("v1-128k")
+			c.Request.Header.Set("If-None-Match", `"etag-1"`)
+
+			expectObject()
+
+			streamHandler.StreamTrack(c)
+
+			Expect(w.Code).To(Equal(http.StatusNotModified))
+		})
+
+		It("should hide private versions from other listeners", func() {
+			c, w := newStreamContext("v2-320k")
+			testutil.SetAuthContext(c, "", testutil.TestPubkey2)
+
+			streamHandler.StreamTrack(c)
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+
+		It("should let the owner stream private versions", func() {
+			c, w := newStreamContext("v2-320k")
+			testutil.SetAuthContext(c, "", testutil.TestPubkey)
+
+			expectObject()
+			mockStorageService.EXPECT().
+				GetObjectRangeReader(gomock.Any(), objectName, int64(0), int64(-1)).
+				Return(io.NopCloser(strings.NewReader(audioData)), nil)
+
+			streamHandler.StreamTrack(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Body.String()).To(Equal(audioData))
+			Expect(w.Header().Get("Cache-Control")).To(Equal("private, no-store"))
+		})
+
+		It("should return not found for unknown versions", func() {
+			c, w := newStreamContext("missing")
+
+			streamHandler.StreamTrack(c)
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+
+		Context("with an HLS version", func() {
+			const hlsPrefix = "tracks/compressed/test-track-123_hls/"
+
+			BeforeEach(func() {
+				hlsVersion := testutil.ValidCompressionVersion()
+				hlsVersion.ID = "hls"
+				hlsVersion.Format = utils.HLSFormat
+				hlsVersion.ObjectKey = hlsPrefix + utils.HLSMasterPlaylist
+				hlsVersion.ObjectPrefix = hlsPrefix
+				track.CompressionVersions = append(track.CompressionVersions, hlsVersion)
+			})
+
+			newFileContext := func(file string) (*gin.Context, *httptest.ResponseRecorder) {
+				c, w := newStreamContext("hls")
+				c.Params = append(c.Params, gin.Param{Key: "file", Value: "/" + file})
+				return c, w
+			}
+
+			It("should redirect to the master playlist", func() {
+				c, w := newStreamContext("hls")
+
+				streamHandler.StreamTrack(c)
+
+				Expect(w.Code).To(Equal(http.StatusFound))
+				Expect(w.Header().Get("Location")).To(Equal("/v1/stream/" + testutil.TestTrackID + "/hls/master.m3u8"))
+			})
+
+			It("should serve files from the package", func() {
+				c, w := newFileContext("128k/index.m3u8")
+				playlist := "#EXTM3U\n"
+
+				mockStorageService.EXPECT().
+					GetObjectInfo(gomock.Any(), hlsPrefix+"128k/index.m3u8").
+					Return(&models.FileMetadata{Size: int64(len(playlist)), UpdatedAt: time.Now()}, nil)
+				mockStorageService.EXPECT().
+					GetObjectRangeReader(gomock.Any(), hlsPrefix+"128k/index.m3u8", int64(0), int64(-1)).
+					Return(io.NopCloser(strings.NewReader(playlist)), nil)
+
+				streamHandler.StreamTrack(c)
+
+				Expect(w.Code).To(Equal(http.StatusOK))
+				Expect(w.Body.String()).To(Equal(playlist))
+				Expect(w.Header().Get("Content-Type")).To(Equal("application/vnd.apple.mpegurl"))
+			})
+
+			It("should keep paths inside the package", func() {
+				c, w := newFileContext("../../original/test-track-123.wav")
+
+				mockStorageService.EXPECT().
+					GetObjectInfo(gomock.Any(), hlsPrefix+"original/test-track-123.wav").
+					Return(nil, errors.New("object not found"))
+
+				streamHandler.StreamTrack(c)
+
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+
+			It("should sign segments but proxy playlists in redirect mode", func() {
+				streamHandler = handlers.NewStreamHandler(mockNostrTrackService, mockStorageService, handlers.StreamModeRedirect, 5*time.Minute)
+				c, w := newFileContext("128k/segment_000.ts")
+
+				mockStorageService.EXPECT().
+					GenerateSignedURL(gomock.Any(), hlsPrefix+"128k/segment_000.ts", gomock.Any()).
+					Return("https://signed.example.com/segment_000.ts", nil)
+
+				streamHandler.StreamTrack(c)
+
+				Expect(w.Code).To(Equal(http.StatusFound))
+
+				c, w = newFileContext("master.m3u8")
+				mockStorageService.EXPECT().
+					GetObjectInfo(gomock.Any(), hlsPrefix+"master.m3u8").
+					Return(nil, errors.New("object not found"))
+
+				streamHandler.StreamTrack(c)
+
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		Context("in redirect mode", func() {
+			BeforeEach(func() {
+				streamHandler = handlers.NewStreamHandler(mockNostrTrackService, mockStorageService, handlers.StreamModeRedirect, 5*time.Minute)
+				mockStorageService.EXPECT().GetPublicURL("").Return(bucketPrefix).AnyTimes()
+			})
+
+			It("should redirect to a signed URL", func() {
+				c, w := newStreamContext("v1-128k")
+
+				mockStorageService.EXPECT().
+					GenerateSignedURL(gomock.Any(), objectName, models.SignedURLOptions{Method: http.MethodGet, Expiration: 5 * time.Minute}).
+					Return("https://signed.example.com/audio.mp3", nil)
+
+				streamHandler.StreamTrack(c)
+
+				Expect(w.Code).To(Equal(http.StatusFound))
+				Expect(w.Header().Get("Location")).To(Equal("https://signed.example.com/audio.mp3"))
+			})
+
+			It("should return server error when signing fails", func() {
+				c, w := newStreamContext("v1-128k")
+
+				mockStorageService.EXPECT().
+					GenerateSignedURL(gomock.Any(), objectName, gomock.Any()).
+					Return("", errors.New("iam unavailable"))
+
+				streamHandler.StreamTrack(c)
+
+				Expect(w.Code).To(Equal(http.StatusInternalServerError))
+			})
+		})
+	})
+
+	Describe("GetDownloadURL", func() {
+		newDownloadContext := func(query string) (*gin.Context, *httptest.ResponseRecorder) {
+			c, w := testutil.SetupGinTestContext("GET", "/v1/tracks/"+testutil.TestTrackID+"/download"+query, nil)
+			c.Params = []gin.Param{{Key: "trackId", Value: testutil.TestTrackID}}
+			return c, w
+		}
+
+		BeforeEach(func() {
+			track.Title = "My Song"
+			mockStorageService.EXPECT().GetPublicURL("").Return(bucketPrefix).AnyTimes()
+		})
+
+		It("should sign the original upload as an attachment for the owner", func() {
+			c, w := newDownloadContext("")
+			testutil.SetAuthContext(c, "", testutil.TestPubkey)
+
+			mockStorageService.EXPECT().
+				GenerateSignedURL(gomock.Any(), "uploads/test-track-123.mp3", models.SignedURLOptions{
+					Method:             http.MethodGet,
+					Expiration:         5 * time.Minute,
+					ContentDisposition: `attachment; filename="My Song.mp3"`,
+				}).
+				Return("https://signed.example.com/original.mp3", nil)
+
+			streamHandler.GetDownloadURL(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var response handlers.DownloadURLResponse
+			testutil.ParseJSONResponse(w.Body, &response)
+			Expect(response.Success).To(BeTrue())
+			Expect(response.Data.URL).To(Equal("https://signed.example.com/original.mp3"))
+			Expect(response.Data.ExpiresAt).To(BeTemporally("~", time.Now().Add(5*time.Minute), time.Minute))
+		})
+
+		It("should sign the recorded object key rather than the URL's", func() {
+			track.OriginalObjectKey = "legacy/raw/my-song.mp3"
+			c, w := newDownloadContext("")
+			testutil.SetAuthContext(c, "", testutil.TestPubkey)
+
+			mockStorageService.EXPECT().
+				GenerateSignedURL(gomock.Any(), "legacy/raw/my-song.mp3", gomock.Any()).
+				Return("https://signed.example.com/original.mp3", nil)
+
+			streamHandler.GetDownloadURL(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+		})
+
+		It("should sign a private version for inline preview", func() {
+			c, w := newDownloadContext("?version=v2-320k&inline=true")
+			testutil.SetAuthContext(c, "", testutil.TestPubkey)
+
+			mockStorageService.EXPECT().
+				GenerateSignedURL(gomock.Any(), objectName, models.SignedURLOptions{
+					Method:             http.MethodGet,
+					Expiration:         5 * time.Minute,
+					ContentDisposition: `inline; filename="My Song.mp3"`,
+				}).
+				Return("https://signed.example.com/preview.mp3", nil)
+
+			streamHandler.GetDownloadURL(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+		})
+
+		It("should return not found for other users", func() {
+			c, w := newDownloadContext("")
+			testutil.SetAuthContext(c, "", testutil.TestPubkey2)
+
+			streamHandler.GetDownloadURL(c)
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+
+		It("should require authentication", func() {
+			c, w := newDownloadContext("")
+
+			streamHandler.GetDownloadURL(c)
+
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+		})
+	})
+})
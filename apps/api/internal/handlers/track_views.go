@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/wavlake/monorepo/internal/models"
+	"github.com/wavlake/monorepo/internal/utils"
+)
+
+// PublicCompressionVersion is a published compressed version of a track
+type PublicCompressionVersion struct {
+	ID         string `json:"id"`
+	URL        string `json:"url"` // Stream path on this API, see streamURL
+	Bitrate    int    `json:"bitrate"`
+	Format     string `json:"format"`
+	Quality    string `json:"quality"`
+	SampleRate int    `json:"sample_rate"`
+	Size       int64  `json:"size"`
+	Renditions []int  `json:"renditions,omitempty"` // HLS rendition bitrates; URL is the master playlist
+	Preset     string `json:"preset,omitempty"`     // Compression preset the version was generated from
+}
+
+// PublicTrack is the view of a track served to anyone. It omits the uploader's
+// Firebase UID, the original upload URL and versions the owner has not published.
+type PublicTrack struct {
+	ID                  string                     `json:"id"`
+	Pubkey              string                     `json:"pubkey"`
+	Title               string                     `json:"title,omitempty"`
+	Lyrics              string                     `json:"lyrics,omitempty"`
+	IsExplicit          bool                       `json:"is_explicit,omitempty"`
+	Duration            int                        `json:"duration,omitempty"`
+	Loudness            *models.LoudnessInfo       `json:"loudness,omitempty"` // Lets players normalize playback
+	CompressionVersions []PublicCompressionVersion `json:"compression_versions"`
+	NostrKind           int                        `json:"nostr_kind,omitempty"`
+	NostrDTag           string                     `json:"nostr_d_tag,omitempty"`
+	ArtworkURL          string                     `json:"artwork_url,omitempty"`
+	ArtworkVariants     []models.ArtworkVariant    `json:"artwork_variants,omitempty"`
+	WaveformURL         string                     `json:"waveform_url,omitempty"`
+	Waveforms           []models.WaveformVariant   `json:"waveforms,omitempty"`
+	CreatedAt           time.Time                  `json:"created_at"`
+	UpdatedAt           time.Time                  `json:"updated_at"`
+}
+
+// streamURL returns the API path a version is played from. Storage URLs are never handed out,
+// so access checks and delivery mode stay with the stream handler. HLS versions point at their
+// master playlist, so the rendition and segment paths inside it resolve under the same path.
+func streamURL(trackID string, version *models.CompressionVersion) string {
+	url := "/v1/stream/" + trackID + "/" + version.ID
+	if version.Format == utils.HLSFormat {
+		url += "/" + utils.HLSMasterPlaylist
+	}
+	return url
+}
+
+// isPubliclyVisible reports whether a track may be shown to other users:
+// not deleted, finished processing and with at least one published version
+func isPubliclyVisible(track *models.NostrTrack) bool {
+	if track == nil || track.Deleted || track.IsProcessing {
+		return false
+	}
+	for _, version := range track.CompressionVersions {
+		if version.IsPublic {
+			return true
+		}
+	}
+	return false
+}
+
+// newPublicTrack builds the public view of a track
+func newPublicTrack(track *models.NostrTrack) *PublicTrack {
+	public := &PublicTrack{
+		ID:                  track.ID,
+		Pubkey:              track.Pubkey,
+		Title:               track.Title,
+		Lyrics:              track.Lyrics,
+		IsExplicit:          track.IsExplicit,
+		Duration:            track.Duration,
+		Loudness:            track.Loudness,
+		CompressionVersions: []PublicCompressionVersion{},
+		NostrKind:           track.NostrKind,
+		NostrDTag:           track.NostrDTag,
+		ArtworkURL:          track.ArtworkURL,
+		ArtworkVariants:     track.ArtworkVariants,
+		WaveformURL:         track.WaveformURL,
+		Waveforms:           track.Waveforms,
+		CreatedAt:           track.CreatedAt,
+		UpdatedAt:           track.UpdatedAt,
+	}
+
+	for _, version := range track.CompressionVersions {
+		if !version.IsPublic {
+			continue
+		}
+		public.CompressionVersions = append(public.CompressionVersions, PublicCompressionVersion{
+			ID:         version.ID,
+			URL:        streamURL(track.ID, &version),
+			Bitrate:    version.Bitrate,
+			Format:     version.Format,
+			Quality:    version.Quality,
+			SampleRate: version.SampleRate,
+			Size:       version.Size,
+			Renditions: version.Renditions,
+			Preset:     version.Options.Preset,
+		})
+	}
+
+	return public
+}
+
+// OwnerTrack is the view of a track served to the pubkey that owns it. It includes
+// processing state, the original upload and every compression version.
+type OwnerTrack struct {
+	ID                    string                      `json:"id"`
+	Pubkey                string                      `json:"pubkey"`
+	Title                 string                      `json:"title,omitempty"`
+	Lyrics                string                      `json:"lyrics,omitempty"`
+	IsExplicit            bool                        `json:"is_explicit,omitempty"`
+	OriginalURL           string                      `json:"original_url"`
+	PresignedURL          string                      `json:"presigned_url,omitempty"`
+	UploadHeaders         map[string]string           `json:"upload_headers,omitempty"`
+	UploadState           string                      `json:"upload_state,omitempty"`
+	UploadSession         *models.UploadSession       `json:"upload_session,omitempty"`
+	UploadExpiresAt       *time.Time                  `json:"upload_expires_at,omitempty"`
+	Extension             string                      `json:"extension"`
+	Size                  int64                       `json:"size,omitempty"`
+	Duration              int                         `json:"duration,omitempty"`
+	IsProcessing          bool                        `json:"is_processing"`
+	CompressionVersions   []models.CompressionVersion `json:"compression_versions,omitempty"`
+	HasPendingCompression bool                        `json:"has_pending_compression"`
+	Deleted               bool                        `json:"deleted"`
+	NostrKind             int                         `json:"nostr_kind,omitempty"`
+	NostrDTag             string                      `json:"nostr_d_tag,omitempty"`
+	ArtworkURL            string                      `json:"artwork_url,omitempty"`
+	ArtworkVariants       []models.ArtworkVariant     `json:"artwork_variants,omitempty"`
+	LegacyTrackID         string                      `json:"legacy_track_id,omitempty"`
+	Loudness              *models.LoudnessInfo        `json:"loudness,omitempty"`
+	FileHash              string                      `json:"file_hash,omitempty"`
+	DuplicateOf           string                      `json:"duplicate_of,omitempty"`
+	Error                 string                      `json:"error,omitempty"`
+	RejectionReasons      []string                    `json:"rejection_reasons,omitempty"`
+	WaveformURL           string                      `json:"waveform_url,omitempty"`
+	Waveforms             []models.WaveformVariant    `json:"waveforms,omitempty"`
+	CreatedAt             time.Time                   `json:"created_at"`
+	UpdatedAt             time.Time                   `json:"updated_at"`
+	CompressedURL         string                      `json:"compressed_url,omitempty"`
+	IsCompressed          bool                        `json:"is_compressed"`
+}
+
+// newOwnerTrack builds the owner view of a track. Version URLs are stream paths like in the
+// public view; private versions stream for the owner with a NIP-98 signed request.
+func newOwnerTrack(track *models.NostrTrack) *OwnerTrack {
+	var versions []models.CompressionVersion
+	for _, version := range track.CompressionVersions {
+		version.URL = streamURL(track.ID, &version)
+		versions = append(versions, version)
+	}
+
+	return &OwnerTrack{
+		ID:                    track.ID,
+		Pubkey:                track.Pubkey,
+		Title:                 track.Title,
+		Lyrics:                track.Lyrics,
+		IsExplicit:            track.IsExplicit,
+		OriginalURL:           track.OriginalURL,
+		PresignedURL:          track.PresignedURL,
+		UploadHeaders:         track.UploadHeaders,
+		UploadState:           track.UploadState,
+		UploadSession:         track.UploadSession,
+		UploadExpiresAt:       track.UploadExpiresAt,
+		Extension:             track.Extension,
+		Size:                  track.Size,
+		Duration:              track.Duration,
+		IsProcessing:          track.IsProcessing,
+		CompressionVersions:   versions,
+		HasPendingCompression: track.HasPendingCompression,
+		Deleted:               track.Deleted,
+		NostrKind:             track.NostrKind,
+		NostrDTag:             track.NostrDTag,
+		ArtworkURL:            track.ArtworkURL,
+		ArtworkVariants:       track.ArtworkVariants,
+		LegacyTrackID:         track.LegacyTrackID,
+		Loudness:              track.Loudness,
+		FileHash:              track.FileHash,
+		DuplicateOf:           track.DuplicateOf,
+		Error:                 track.Error,
+		RejectionReasons:      track.RejectionReasons,
+		WaveformURL:           track.WaveformURL,
+		Waveforms:             track.Waveforms,
+		CreatedAt:             track.CreatedAt,
+		UpdatedAt:             track.UpdatedAt,
+		CompressedURL:         track.CompressedURL,
+		IsCompressed:          track.IsCompressed,
+	}
+}
+
+// newOwnerTracks builds owner views for a list of tracks
+func newOwnerTracks(tracks []*models.NostrTrack) []*OwnerTrack {
+	views := make([]*OwnerTrack, 0, len(tracks))
+	for _, track := range tracks {
+		views = append(views, newOwnerTrack(track))
+	}
+	return views
+}
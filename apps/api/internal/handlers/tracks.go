@@ -1,6 +1,11 @@
 package handlers
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"strings"
@@ -8,6 +13,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/wavlake/monorepo/internal/models"
 	"github.com/wavlake/monorepo/internal/services"
+	"github.com/wavlake/monorepo/internal/utils"
 )
 
 type TracksHandler struct {
@@ -24,15 +30,30 @@ func NewTracksHandler(nostrTrackService services.NostrTrackServiceInterface, pro
 	}
 }
 
+// authenticatedPubkey returns the pubkey set by the NIP-98 middleware, if any
+func authenticatedPubkey(c *gin.Context) (string, bool) {
+	pubkey, exists := c.Get("pubkey")
+	if !exists {
+		return "", false
+	}
+	pubkeyStr, ok := pubkey.(string)
+	if !ok || pubkeyStr == "" {
+		return "", false
+	}
+	return pubkeyStr, true
+}
+
 type CreateNostrTrackRequest struct {
 	Extension string `json:"extension" binding:"required"`
+	Resumable bool   `json:"resumable,omitempty"` // Open a resumable upload session instead of a one-shot URL
+	Size      int64  `json:"size,omitempty"`      // Declared upload size in bytes, checked against the upload limit
 }
 
 type CreateTrackResponse struct {
-	Success bool               `json:"success"`
-	Data    *models.NostrTrack `json:"data,omitempty"`
-	Error   string             `json:"error,omitempty"`
-	Message string             `json:"message,omitempty"`
+	Success bool        `json:"success"`
+	Data    *OwnerTrack `json:"data,omitempty"`
+	Error   string      `json:"error,omitempty"`
+	Message string      `json:"message,omitempty"`
 }
 
 // CreateTrackNostr creates a new track via NIP-98 authentication
@@ -93,12 +114,21 @@ func (h *TracksHandler) CreateTrackNostr(c *gin.Context) {
 	}
 
 	// Create the track
-	track, err := h.nostrTrackService.CreateTrack(
-		c.Request.Context(),
-		pubkeyStr,
-		firebaseUIDStr,
-		strings.TrimPrefix(req.Extension, "."),
-	)
+	extension := strings.TrimPrefix(req.Extension, ".")
+	var track *models.NostrTrack
+	var err error
+	if req.Resumable {
+		track, err = h.nostrTrackService.CreateResumableTrack(c.Request.Context(), pubkeyStr, firebaseUIDStr, extension, req.Size)
+	} else {
+		track, err = h.nostrTrackService.CreateTrack(c.Request.Context(), pubkeyStr, firebaseUIDStr, extension)
+	}
+	if errors.Is(err, services.ErrUploadTooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, CreateTrackResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
 	if err != nil {
 		log.Printf("Failed to create track: %v", err)
 		c.JSON(http.StatusInternalServerError, CreateTrackResponse{
@@ -110,17 +140,20 @@ func (h *TracksHandler) CreateTrackNostr(c *gin.Context) {
 
 	c.JSON(http.StatusOK, CreateTrackResponse{
 		Success: true,
-		Data:    track,
+		Data:    newOwnerTrack(track),
 	})
 }
 
 type GetTracksResponse struct {
-	Success bool                 `json:"success"`
-	Data    []*models.NostrTrack `json:"data,omitempty"`
-	Error   string               `json:"error,omitempty"`
+	Success    bool          `json:"success"`
+	Data       []*OwnerTrack `json:"data,omitempty"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+	Error      string        `json:"error,omitempty"`
 }
 
-// GetMyTracks returns tracks for the authenticated user
+// GetMyTracks returns a page of tracks for the authenticated user.
+// Supports limit, cursor, sort (created_at|updated_at), order (asc|desc) and the
+// is_processing, deleted, has_public_versions and format filters.
 func (h *TracksHandler) GetMyTracks(c *gin.Context) {
 	// Get authenticated user info from NIP-98 middleware context
 	pubkey, exists := c.Get("pubkey")
@@ -141,8 +174,26 @@ func (h *TracksHandler) GetMyTracks(c *gin.Context) {
 		return
 	}
 
+	opts, err := parseListOptions(c, trackSortFields)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, GetTracksResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	filter, err := parseTrackFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, GetTracksResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
 	// Get tracks for this pubkey
-	tracks, err := h.nostrTrackService.GetTracksByPubkey(c.Request.Context(), pubkeyStr)
+	page, err := h.nostrTrackService.ListTracksByPubkey(c.Request.Context(), pubkeyStr, opts, filter)
 	if err != nil {
 		log.Printf("Failed to get tracks for pubkey %s: %v", pubkeyStr, err)
 		c.JSON(http.StatusInternalServerError, GetTracksResponse{
@@ -153,12 +204,102 @@ func (h *TracksHandler) GetMyTracks(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, GetTracksResponse{
-		Success: true,
-		Data:    tracks,
+		Success:    true,
+		Data:       newOwnerTracks(page.Tracks),
+		NextCursor: page.NextCursor,
 	})
 }
 
-// GetTrack returns a specific track by ID
+// publicListMaxAge is how long shared caches may serve public track listings
+const publicListMaxAge = 60
+
+type PublicTracksResponse struct {
+	Success    bool           `json:"success"`
+	Data       []*PublicTrack `json:"data,omitempty"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+	Error      string         `json:"error,omitempty"`
+}
+
+// GetArtistTracks handles GET /v1/artists/:pubkey/tracks
+// Returns the artist's published tracks: not deleted, fully processed and with at least
+// one public version. Only public versions are included. Supports the same limit, cursor,
+// sort and order parameters as GetMyTracks.
+func (h *TracksHandler) GetArtistTracks(c *gin.Context) {
+	pubkey := c.Param("pubkey")
+	if pubkey == "" {
+		c.JSON(http.StatusBadRequest, PublicTracksResponse{
+			Success: false,
+			Error:   "pubkey is required",
+		})
+		return
+	}
+
+	opts, err := parseListOptions(c, trackSortFields)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, PublicTracksResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	notDeleted, notProcessing, hasPublicVersions := false, false, true
+	filter := models.TrackFilter{
+		Deleted:           &notDeleted,
+		IsProcessing:      &notProcessing,
+		HasPublicVersions: &hasPublicVersions,
+	}
+
+	page, err := h.nostrTrackService.ListTracksByPubkey(c.Request.Context(), pubkey, opts, filter)
+	if err != nil {
+		log.Printf("Failed to get public tracks for pubkey %s: %v", pubkey, err)
+		c.JSON(http.StatusInternalServerError, PublicTracksResponse{
+			Success: false,
+			Error:   "failed to retrieve tracks",
+		})
+		return
+	}
+
+	tracks := []*PublicTrack{}
+	for _, track := range page.Tracks {
+		if isPubliclyVisible(track) {
+			tracks = append(tracks, newPublicTrack(track))
+		}
+	}
+
+	writeCacheableJSON(c, PublicTracksResponse{
+		Success:    true,
+		Data:       tracks,
+		NextCursor: page.NextCursor,
+	})
+}
+
+// writeCacheableJSON writes a public 200 response with Cache-Control and a weak ETag,
+// answering 304 Not Modified when the client already has the same body
+func writeCacheableJSON(c *gin.Context, body interface{}) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to encode response"})
+		return
+	}
+
+	sum := sha256.Sum256(data)
+	etag := fmt.Sprintf(`W/"%s"`, hex.EncodeToString(sum[:16]))
+
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", publicListMaxAge))
+	c.Header("ETag", etag)
+
+	if c.GetHeader("If-None-Match") == etag {
+		c.AbortWithStatus(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, "application/json; charset=utf-8", data)
+}
+
+// GetTrack returns a specific track by ID.
+// The owner (authenticated as the track's pubkey) gets the full owner view; everyone else
+// gets the public view, and deleted or unpublished tracks are reported as not found.
 func (h *TracksHandler) GetTrack(c *gin.Context) {
 	trackID := c.Param("trackId")
 	if trackID == "" {
@@ -172,7 +313,99 @@ func (h *TracksHandler) GetTrack(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"success": true, "data": track})
+	if pubkey, ok := authenticatedPubkey(c); ok && pubkey == track.Pubkey {
+		c.JSON(http.StatusOK, gin.H{"success": true, "data": newOwnerTrack(track)})
+		return
+	}
+
+	if !isPubliclyVisible(track) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "track not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": newPublicTrack(track)})
+}
+
+// UploadStatus is the progress of a track's original upload
+type UploadStatus struct {
+	TrackID     string                `json:"track_id"`
+	UploadState string                `json:"upload_state,omitempty"`
+	Session     *models.UploadSession `json:"upload_session,omitempty"`
+}
+
+// GetUploadStatus reports how much of a resumable upload storage has committed, so the
+// owner can resume from that offset
+func (h *TracksHandler) GetUploadStatus(c *gin.Context) {
+	trackID := c.Param("trackId")
+	pubkey, ok := authenticatedPubkey(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		return
+	}
+
+	track, err := h.nostrTrackService.GetTrack(c.Request.Context(), trackID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "track not found"})
+		return
+	}
+	if track.Pubkey != pubkey {
+		c.JSON(http.StatusForbidden, gin.H{"error": "you can only view uploads of your own tracks"})
+		return
+	}
+
+	track, err = h.nostrTrackService.RefreshUploadStatus(c.Request.Context(), trackID)
+	if errors.Is(err, utils.ErrUploadSessionExpired) {
+		c.JSON(http.StatusGone, gin.H{"error": "upload session expired"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to refresh upload status for track %s: %v", trackID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get upload status"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": UploadStatus{
+		TrackID:     track.ID,
+		UploadState: track.UploadState,
+		Session:     track.UploadSession,
+	}})
+}
+
+// RenewUpload issues a fresh upload URL, or resumable session, for one of the owner's tracks
+// whose original never arrived
+func (h *TracksHandler) RenewUpload(c *gin.Context) {
+	trackID := c.Param("trackId")
+	pubkey, ok := authenticatedPubkey(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, CreateTrackResponse{Success: false, Error: "authentication required"})
+		return
+	}
+
+	track, err := h.nostrTrackService.GetTrack(c.Request.Context(), trackID)
+	if err != nil || track.Deleted {
+		c.JSON(http.StatusNotFound, CreateTrackResponse{Success: false, Error: "track not found"})
+		return
+	}
+	if track.Pubkey != pubkey {
+		c.JSON(http.StatusForbidden, CreateTrackResponse{Success: false, Error: "you can only upload to your own tracks"})
+		return
+	}
+
+	track, err = h.nostrTrackService.RenewUpload(c.Request.Context(), trackID)
+	if errors.Is(err, services.ErrUploadComplete) {
+		c.JSON(http.StatusConflict, CreateTrackResponse{Success: false, Error: "track original already uploaded"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to renew upload for track %s: %v", trackID, err)
+		c.JSON(http.StatusInternalServerError, CreateTrackResponse{Success: false, Error: "failed to renew upload"})
+		return
+	}
+
+	c.JSON(http.StatusOK, CreateTrackResponse{
+		Success: true,
+		Data:    newOwnerTrack(track),
+	})
 }
 
 // DeleteTrack soft deletes a track
@@ -1,7 +1,9 @@
 package handlers_test
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"strings"
 
@@ -12,6 +14,8 @@ import (
 
 	"github.com/wavlake/monorepo/internal/handlers"
 	"github.com/wavlake/monorepo/internal/models"
+	"github.com/wavlake/monorepo/internal/services"
+	"github.com/wavlake/monorepo/internal/utils"
 	"github.com/wavlake/monorepo/tests/mocks"
 	"github.com/wavlake/monorepo/tests/testutil"
 )
@@ -98,6 +102,57 @@ var _ = Describe("TracksHandler", func() {
 			})
 		})
 
+		Context("when a resumable upload is requested", func() {
+			It("should open an upload session", func() {
+				c, w := testutil.SetupGinTestContext("POST", "/v1/tracks", map[string]interface{}{
+					"extension": "flac",
+					"resumable": true,
+					"size":      1024,
+				})
+				testutil.SetAuthContext(c, testFirebaseUID, testPubkey)
+
+				expectedTrack := testutil.ValidNostrTrack()
+				expectedTrack.UploadState = models.UploadStatePending
+				expectedTrack.UploadSession = &models.UploadSession{URL: "https://storage.example.com/session", Size: 1024}
+
+				mockAudioProcessor.EXPECT().
+					IsFormatSupported("flac").
+					Return(true)
+
+				mockNostrTrackService.EXPECT().
+					CreateResumableTrack(c.Request.Context(), testPubkey, testFirebaseUID, "flac", int64(1024)).
+					Return(expectedTrack, nil)
+
+				tracksHandler.CreateTrackNostr(c)
+
+				response := testutil.AssertJSONResponse(w, http.StatusOK)
+				data := response["data"].(map[string]interface{})
+				Expect(data["upload_state"]).To(Equal(models.UploadStatePending))
+				Expect(data["upload_session"]).To(HaveKeyWithValue("url", "https://storage.example.com/session"))
+			})
+
+			It("should reject uploads declared larger than the limit", func() {
+				c, w := testutil.SetupGinTestContext("POST", "/v1/tracks", map[string]interface{}{
+					"extension": "wav",
+					"resumable": true,
+					"size":      1 << 40,
+				})
+				testutil.SetAuthContext(c, testFirebaseUID, testPubkey)
+
+				mockAudioProcessor.EXPECT().
+					IsFormatSupported("wav").
+					Return(true)
+
+				mockNostrTrackService.EXPECT().
+					CreateResumableTrack(c.Request.Context(), testPubkey, testFirebaseUID, "wav", int64(1<<40)).
+					Return(nil, fmt.Errorf("%w: too big", services.ErrUploadTooLarge))
+
+				tracksHandler.CreateTrackNostr(c)
+
+				Expect(w.Code).To(Equal(http.StatusRequestEntityTooLarge))
+			})
+		})
+
 		Context("when request body is invalid", func() {
 			It("should return bad request for missing extension", func() {
 				c, w := testutil.SetupGinTestContext("POST", "/v1/tracks", map[string]interface{}{
@@ -305,8 +360,8 @@ var _ = Describe("TracksHandler", func() {
 				expectedTracks := testutil.ValidTracksList()
 				
 				mockNostrTrackService.EXPECT().
-					GetTracksByPubkey(c.Request.Context(), testPubkey).
-					Return(expectedTracks, nil)
+					ListTracksByPubkey(c.Request.Context(), testPubkey, gomock.Any(), gomock.Any()).
+					Return(&models.NostrTrackPage{Tracks: expectedTracks}, nil)
 
 				tracksHandler.GetMyTracks(c)
 
@@ -324,8 +379,8 @@ var _ = Describe("TracksHandler", func() {
 				testutil.SetAuthContext(c, "", testPubkey)
 
 				mockNostrTrackService.EXPECT().
-					GetTracksByPubkey(c.Request.Context(), testPubkey).
-					Return([]*models.NostrTrack{}, nil)
+					ListTracksByPubkey(c.Request.Context(), testPubkey, gomock.Any(), gomock.Any()).
+					Return(&models.NostrTrackPage{Tracks: []*models.NostrTrack{}}, nil)
 
 				tracksHandler.GetMyTracks(c)
 
@@ -347,6 +402,75 @@ var _ = Describe("TracksHandler", func() {
 			})
 		})
 
+		Context("when pagination parameters are provided", func() {
+			It("should pass options and filters to the service and return the next cursor", func() {
+				c, w := testutil.SetupGinTestContext("GET", "/v1/tracks/my?limit=1&sort=updated_at&order=asc&is_processing=false&format=mp3", nil)
+				testutil.SetAuthContext(c, "", testPubkey)
+
+				isProcessing := false
+				mockNostrTrackService.EXPECT().
+					ListTracksByPubkey(c.Request.Context(), testPubkey,
+						models.ListOptions{Limit: 1, SortBy: "updated_at", SortOrder: "asc"},
+						models.TrackFilter{IsProcessing: &isProcessing, Format: "mp3"}).
+					Return(&models.NostrTrackPage{
+						Tracks:     testutil.ValidTracksList()[:1],
+						NextCursor: "next-page",
+					}, nil)
+
+				tracksHandler.GetMyTracks(c)
+
+				response := testutil.AssertJSONResponse(w, http.StatusOK)
+				Expect(response["data"]).To(HaveLen(1))
+				Expect(response["next_cursor"]).To(Equal("next-page"))
+			})
+
+			It("should reject an invalid limit", func() {
+				c, w := testutil.SetupGinTestContext("GET", "/v1/tracks/my?limit=0", nil)
+				testutil.SetAuthContext(c, "", testPubkey)
+
+				tracksHandler.GetMyTracks(c)
+
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+
+			It("should reject an unsupported sort field", func() {
+				c, w := testutil.SetupGinTestContext("GET", "/v1/tracks/my?sort=title", nil)
+				testutil.SetAuthContext(c, "", testPubkey)
+
+				tracksHandler.GetMyTracks(c)
+
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+
+			It("should reject a malformed cursor", func() {
+				c, w := testutil.SetupGinTestContext("GET", "/v1/tracks/my?cursor=not-a-cursor", nil)
+				testutil.SetAuthContext(c, "", testPubkey)
+
+				tracksHandler.GetMyTracks(c)
+
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+
+			It("should reject a cursor issued for another sort", func() {
+				cursor := utils.EncodeCursor("created_at", "desc", "2024-01-02T03:04:05Z", "track-1")
+				c, w := testutil.SetupGinTestContext("GET", "/v1/tracks/my?sort=updated_at&cursor="+cursor, nil)
+				testutil.SetAuthContext(c, "", testPubkey)
+
+				tracksHandler.GetMyTracks(c)
+
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+
+			It("should reject a non-boolean filter", func() {
+				c, w := testutil.SetupGinTestContext("GET", "/v1/tracks/my?deleted=sometimes", nil)
+				testutil.SetAuthContext(c, "", testPubkey)
+
+				tracksHandler.GetMyTracks(c)
+
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
 		Context("when Nostr authentication is missing", func() {
 			It("should return unauthorized error", func() {
 				c, w := testutil.SetupGinTestContext("GET", "/v1/tracks/my", nil)
@@ -383,7 +507,7 @@ var _ = Describe("TracksHandler", func() {
 				expectedError := errors.New("database query failed")
 				
 				mockNostrTrackService.EXPECT().
-					GetTracksByPubkey(c.Request.Context(), testPubkey).
+					ListTracksByPubkey(c.Request.Context(), testPubkey, gomock.Any(), gomock.Any()).
 					Return(nil, expectedError)
 
 				tracksHandler.GetMyTracks(c)
@@ -396,6 +520,83 @@ var _ = Describe("TracksHandler", func() {
 		})
 	})
 
+	Describe("GetArtistTracks", func() {
+		var publishedTrack *models.NostrTrack
+
+		BeforeEach(func() {
+			publishedTrack = testutil.ValidNostrTrack()
+			privateVersion := testutil.ValidCompressionVersion()
+			privateVersion.ID = "v2-320k"
+			privateVersion.IsPublic = false
+			publishedTrack.CompressionVersions = []models.CompressionVersion{testutil.ValidCompressionVersion(), privateVersion}
+		})
+
+		It("should return only public data for published tracks", func() {
+			c, w := testutil.SetupGinTestContext("GET", "/v1/artists/"+testPubkey+"/tracks", nil)
+			c.Params = []gin.Param{{Key: "pubkey", Value: testPubkey}}
+
+			mockNostrTrackService.EXPECT().
+				ListTracksByPubkey(gomock.Any(), testPubkey, gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ string, _ models.ListOptions, filter models.TrackFilter) (*models.NostrTrackPage, error) {
+					Expect(*filter.Deleted).To(BeFalse())
+					Expect(*filter.IsProcessing).To(BeFalse())
+					Expect(*filter.HasPublicVersions).To(BeTrue())
+					return &models.NostrTrackPage{Tracks: []*models.NostrTrack{publishedTrack}, NextCursor: "next-page"}, nil
+				})
+
+			tracksHandler.GetArtistTracks(c)
+
+			response := testutil.AssertJSONResponse(w, http.StatusOK)
+			Expect(w.Header().Get("Cache-Control")).To(ContainSubstring("public"))
+			Expect(w.Header().Get("ETag")).ToNot(BeEmpty())
+			Expect(response["next_cursor"]).To(Equal("next-page"))
+
+			data := response["data"].([]interface{})
+			Expect(data).To(HaveLen(1))
+			track := data[0].(map[string]interface{})
+			Expect(track).ToNot(HaveKey("firebase_uid"))
+			Expect(track).ToNot(HaveKey("original_url"))
+			versions := track["compression_versions"].([]interface{})
+			Expect(versions).To(HaveLen(1))
+			Expect(versions[0].(map[string]interface{})["id"]).To(Equal("v1-128k"))
+			Expect(versions[0].(map[string]interface{})["url"]).To(Equal("/v1/stream/" + publishedTrack.ID + "/v1-128k"))
+		})
+
+		It("should return 304 when the ETag matches", func() {
+			c, w := testutil.SetupGinTestContext("GET", "/v1/artists/"+testPubkey+"/tracks", nil)
+			c.Params = []gin.Param{{Key: "pubkey", Value: testPubkey}}
+
+			mockNostrTrackService.EXPECT().
+				ListTracksByPubkey(gomock.Any(), testPubkey, gomock.Any(), gomock.Any()).
+				Return(&models.NostrTrackPage{Tracks: []*models.NostrTrack{publishedTrack}}, nil).
+				Times(2)
+
+			tracksHandler.GetArtistTracks(c)
+			etag := w.Header().Get("ETag")
+
+			c2, w2 := testutil.SetupGinTestContext("GET", "/v1/artists/"+testPubkey+"/tracks", nil)
+			c2.Params = []gin.Param{{Key: "pubkey", Value: testPubkey}}
+			c2.Request.Header.Set("If-None-Match", etag)
+
+			tracksHandler.GetArtistTracks(c2)
+
+			Expect(w2.Code).To(Equal(http.StatusNotModified))
+		})
+
+		It("should return server error when the service fails", func() {
+			c, w := testutil.SetupGinTestContext("GET", "/v1/artists/"+testPubkey+"/tracks", nil)
+			c.Params = []gin.Param{{Key: "pubkey", Value: testPubkey}}
+
+			mockNostrTrackService.EXPECT().
+				ListTracksByPubkey(gomock.Any(), testPubkey, gomock.Any(), gomock.Any()).
+				Return(nil, errors.New("firestore unavailable"))
+
+			tracksHandler.GetArtistTracks(c)
+
+			Expect(w.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+
 	Describe("GetTrack", func() {
 		Context("when track ID is provided", func() {
 			It("should return the track", func() {
@@ -403,6 +604,7 @@ var _ = Describe("TracksHandler", func() {
 				c.Params = []gin.Param{{Key: "trackId", Value: testTrackID}}
 
 				expectedTrack := testutil.ValidNostrTrack()
+				expectedTrack.CompressionVersions = []models.CompressionVersion{testutil.ValidCompressionVersion()}
 				
 				mockNostrTrackService.EXPECT().
 					GetTrack(c.Request.Context(), testTrackID).
@@ -418,6 +620,86 @@ var _ = Describe("TracksHandler", func() {
 				Expect(ok).To(BeTrue())
 				Expect(data["id"]).To(Equal(expectedTrack.ID))
 			})
+
+			It("should hide private fields and versions from other users", func() {
+				c, w := testutil.SetupGinTestContext("GET", "/v1/tracks/:trackId", nil)
+				c.Params = []gin.Param{{Key: "trackId", Value: testTrackID}}
+				testutil.SetAuthContext(c, "", testutil.TestPubkey2)
+
+				privateVersion := testutil.ValidCompressionVersion()
+				privateVersion.ID = "v2-320k"
+				privateVersion.IsPublic = false
+				expectedTrack := testutil.ValidNostrTrack()
+				expectedTrack.CompressionVersions = []models.CompressionVersion{testutil.ValidCompressionVersion(), privateVersion}
+
+				mockNostrTrackService.EXPECT().
+					GetTrack(c.Request.Context(), testTrackID).
+					Return(expectedTrack, nil)
+
+				tracksHandler.GetTrack(c)
+
+				response := testutil.AssertJSONResponse(w, http.StatusOK)
+				data := response["data"].(map[string]interface{})
+				Expect(data).ToNot(HaveKey("firebase_uid"))
+				Expect(data).ToNot(HaveKey("original_url"))
+				Expect(data["compression_versions"]).To(HaveLen(1))
+			})
+
+			It("should return the owner view to the track's pubkey", func() {
+				c, w := testutil.SetupGinTestContext("GET", "/v1/tracks/:trackId", nil)
+				c.Params = []gin.Param{{Key: "trackId", Value: testTrackID}}
+				testutil.SetAuthContext(c, "", testPubkey)
+
+				expectedTrack := testutil.ValidNostrTrack()
+				expectedTrack.IsProcessing = true
+
+				mockNostrTrackService.EXPECT().
+					GetTrack(c.Request.Context(), testTrackID).
+					Return(expectedTrack, nil)
+
+				tracksHandler.GetTrack(c)
+
+				response := testutil.AssertJSONResponse(w, http.StatusOK)
+				data := response["data"].(map[string]interface{})
+				Expect(data["original_url"]).To(Equal(expectedTrack.OriginalURL))
+				Expect(data["is_processing"]).To(BeTrue())
+				Expect(data).ToNot(HaveKey("firebase_uid"))
+			})
+		})
+
+		Context("when track is not published", func() {
+			It("should return not found for deleted tracks", func() {
+				c, w := testutil.SetupGinTestContext("GET", "/v1/tracks/:trackId", nil)
+				c.Params = []gin.Param{{Key: "trackId", Value: testTrackID}}
+
+				expectedTrack := testutil.ValidNostrTrack()
+				expectedTrack.CompressionVersions = []models.CompressionVersion{testutil.ValidCompressionVersion()}
+				expectedTrack.Deleted = true
+
+				mockNostrTrackService.EXPECT().
+					GetTrack(c.Request.Context(), testTrackID).
+					Return(expectedTrack, nil)
+
+				tracksHandler.GetTrack(c)
+
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+
+			It("should return not found for tracks that are still processing", func() {
+				c, w := testutil.SetupGinTestContext("GET", "/v1/tracks/:trackId", nil)
+				c.Params = []gin.Param{{Key: "trackId", Value: testTrackID}}
+
+				expectedTrack := testutil.ValidNostrTrack()
+				expectedTrack.IsProcessing = true
+
+				mockNostrTrackService.EXPECT().
+					GetTrack(c.Request.Context(), testTrackID).
+					Return(expectedTrack, nil)
+
+				tracksHandler.GetTrack(c)
+
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
 		})
 
 		Context("when track ID is missing", func() {
@@ -453,6 +735,123 @@ var _ = Describe("TracksHandler", func() {
 		})
 	})
 
+	Describe("GetUploadStatus", func() {
+		It("should report the committed offset to the owner", func() {
+			c, w := testutil.SetupGinTestContext("GET", "/v1/tracks/:trackId/upload", nil)
+			c.Params = []gin.Param{{Key: "trackId", Value: testTrackID}}
+			testutil.SetAuthContext(c, "", testPubkey)
+
+			track := testutil.ValidNostrTrack()
+			refreshed := testutil.ValidNostrTrack()
+			refreshed.UploadState = models.UploadStateUploading
+			refreshed.UploadSession = &models.UploadSession{URL: "https://storage.example.com/session", Offset: 4096}
+
+			mockNostrTrackService.EXPECT().
+				GetTrack(c.Request.Context(), testTrackID).
+				Return(track, nil)
+			mockNostrTrackService.EXPECT().
+				RefreshUploadStatus(c.Request.Context(), testTrackID).
+				Return(refreshed, nil)
+
+			tracksHandler.GetUploadStatus(c)
+
+			response := testutil.AssertJSONResponse(w, http.StatusOK)
+			data := response["data"].(map[string]interface{})
+			Expect(data["upload_state"]).To(Equal(models.UploadStateUploading))
+			Expect(data["upload_session"]).To(HaveKeyWithValue("offset", BeNumerically("==", 4096)))
+		})
+
+		It("should forbid other pubkeys", func() {
+			c, w := testutil.SetupGinTestContext("GET", "/v1/tracks/:trackId/upload", nil)
+			c.Params = []gin.Param{{Key: "trackId", Value: testTrackID}}
+			testutil.SetAuthContext(c, "", "different-pubkey")
+
+			mockNostrTrackService.EXPECT().
+				GetTrack(c.Request.Context(), testTrackID).
+				Return(testutil.ValidNostrTrack(), nil)
+
+			tracksHandler.GetUploadStatus(c)
+
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+		})
+
+		It("should report expired sessions as gone", func() {
+			c, w := testutil.SetupGinTestContext("GET", "/v1/tracks/:trackId/upload", nil)
+			c.Params = []gin.Param{{Key: "trackId", Value: testTrackID}}
+			testutil.SetAuthContext(c, "", testPubkey)
+
+			mockNostrTrackService.EXPECT().
+				GetTrack(c.Request.Context(), testTrackID).
+				Return(testutil.ValidNostrTrack(), nil)
+			mockNostrTrackService.EXPECT().
+				RefreshUploadStatus(c.Request.Context(), testTrackID).
+				Return(nil, fmt.Errorf("failed to query upload session: %w", utils.ErrUploadSessionExpired))
+
+			tracksHandler.GetUploadStatus(c)
+
+			Expect(w.Code).To(Equal(http.StatusGone))
+		})
+	})
+
+	Describe("RenewUpload", func() {
+		It("should return a fresh upload URL to the owner", func() {
+			c, w := testutil.SetupGinTestContext("POST", "/v1/tracks/:trackId/upload", nil)
+			c.Params = []gin.Param{{Key: "trackId", Value: testTrackID}}
+			testutil.SetAuthContext(c, "", testPubkey)
+
+			track := testutil.ValidNostrTrack()
+			track.UploadState = models.UploadStateExpired
+			renewed := testutil.ValidNostrTrack()
+			renewed.UploadState = models.UploadStatePending
+			renewed.PresignedURL = "https://storage.example.com/upload"
+
+			mockNostrTrackService.EXPECT().
+				GetTrack(c.Request.Context(), testTrackID).
+				Return(track, nil)
+			mockNostrTrackService.EXPECT().
+				RenewUpload(c.Request.Context(), testTrackID).
+				Return(renewed, nil)
+
+			tracksHandler.RenewUpload(c)
+
+			response := testutil.AssertJSONResponse(w, http.StatusOK)
+			data := response["data"].(map[string]interface{})
+			Expect(data["upload_state"]).To(Equal(models.UploadStatePending))
+			Expect(data["presigned_url"]).To(Equal("https://storage.example.com/upload"))
+		})
+
+		It("should forbid other pubkeys", func() {
+			c, w := testutil.SetupGinTestContext("POST", "/v1/tracks/:trackId/upload", nil)
+			c.Params = []gin.Param{{Key: "trackId", Value: testTrackID}}
+			testutil.SetAuthContext(c, "", "different-pubkey")
+
+			mockNostrTrackService.EXPECT().
+				GetTrack(c.Request.Context(), testTrackID).
+				Return(testutil.ValidNostrTrack(), nil)
+
+			tracksHandler.RenewUpload(c)
+
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+		})
+
+		It("should report tracks that already have their original as a conflict", func() {
+			c, w := testutil.SetupGinTestContext("POST", "/v1/tracks/:trackId/upload", nil)
+			c.Params = []gin.Param{{Key: "trackId", Value: testTrackID}}
+			testutil.SetAuthContext(c, "", testPubkey)
+
+			mockNostrTrackService.EXPECT().
+				GetTrack(c.Request.Context(), testTrackID).
+				Return(testutil.ValidNostrTrack(), nil)
+			mockNostrTrackService.EXPECT().
+				RenewUpload(c.Request.Context(), testTrackID).
+				Return(nil, services.ErrUploadComplete)
+
+			tracksHandler.RenewUpload(c)
+
+			Expect(w.Code).To(Equal(http.StatusConflict))
+		})
+	})
+
 	Describe("DeleteTrack", func() {
 		Context("when user owns the track", func() {
 			It("should successfully delete the track", func() {
@@ -58,41 +58,110 @@ type LinkedPubkeyInfo struct {
 
 // CompressionOption represents a user's choice for audio compression
 type CompressionOption struct {
-	Bitrate    int    `json:"bitrate"`               // e.g., 128, 256, 320
-	Format     string `json:"format"`                // e.g., "mp3", "aac", "ogg"
-	Quality    string `json:"quality"`               // e.g., "low", "medium", "high"
-	SampleRate int    `json:"sample_rate,omitempty"` // e.g., 44100, 48000
+	Bitrate    int             `json:"bitrate"`               // e.g., 128, 256, 320
+	Format     string          `json:"format"`                // "mp3", "aac" (raw ADTS), "m4a" (AAC in MP4), "ogg", "opus", "webm" (Opus in WebM), "flac" or "hls"
+	Quality    string          `json:"quality"`               // e.g., "low", "medium", "high"
+	SampleRate int             `json:"sample_rate,omitempty"` // e.g., 44100, 48000
+	Renditions []int           `json:"renditions,omitempty"`  // HLS only: AAC rendition bitrates in kbps
+	Loudnorm   *LoudnessTarget `json:"loudnorm,omitempty"`    // Two-pass EBU R128 normalization target, nil to keep source loudness
+	Preset     string          `json:"preset,omitempty"`      // Named preset from config; when requested, the preset's options replace the others
+}
+
+// LoudnessTarget is an EBU R128 normalization target for ffmpeg's loudnorm filter. Unset
+// values take the platform default; pointers keep an explicit 0 dBTP ceiling apart from unset.
+type LoudnessTarget struct {
+	IntegratedLUFS *float64 `firestore:"integrated_lufs,omitempty" json:"integrated_lufs,omitempty"` // Target integrated loudness, e.g. -14
+	TruePeak       *float64 `firestore:"true_peak,omitempty" json:"true_peak,omitempty"`             // Maximum true peak in dBTP, e.g. -1
+	LRA            *float64 `firestore:"lra,omitempty" json:"lra,omitempty"`                         // Target loudness range in LU, e.g. 11
+}
+
+// WaveformVariant is audiowaveform-compatible JSON peak data at one zoom level
+type WaveformVariant struct {
+	SamplesPerPixel int    `firestore:"samples_per_pixel" json:"samples_per_pixel"`
+	URL             string `firestore:"url" json:"url"`
+}
+
+// LoudnessInfo is the result of an EBU R128 loudness analysis
+type LoudnessInfo struct {
+	IntegratedLUFS float64 `firestore:"integrated_lufs" json:"integrated_lufs"` // Integrated loudness in LUFS
+	TruePeak       float64 `firestore:"true_peak" json:"true_peak"`             // True peak in dBTP
+	LRA            float64 `firestore:"lra" json:"lra"`                         // Loudness range in LU
+	Threshold      float64 `firestore:"threshold" json:"threshold"`             // Gating threshold in LUFS, needed for a second loudnorm pass
 }
 
 // CompressionVersion represents a generated compressed version
 type CompressionVersion struct {
-	ID         string            `firestore:"id" json:"id"`                   // Unique ID for this version
-	URL        string            `firestore:"url" json:"url"`                 // GCS URL
-	Bitrate    int               `firestore:"bitrate" json:"bitrate"`         // Actual bitrate
-	Format     string            `firestore:"format" json:"format"`           // File format
-	Quality    string            `firestore:"quality" json:"quality"`         // Quality level
-	SampleRate int               `firestore:"sample_rate" json:"sample_rate"` // Sample rate
-	Size       int64             `firestore:"size" json:"size"`               // File size in bytes
-	IsPublic   bool              `firestore:"is_public" json:"is_public"`     // Whether to include in Nostr event
-	CreatedAt  time.Time         `firestore:"created_at" json:"created_at"`
-	Options    CompressionOption `firestore:"options" json:"options"` // Original compression request
+	ID           string            `firestore:"id" json:"id"`                   // Unique ID for this version
+	URL          string            `firestore:"url" json:"url"`                 // GCS URL
+	Bitrate      int               `firestore:"bitrate" json:"bitrate"`         // Actual bitrate
+	Format       string            `firestore:"format" json:"format"`           // File format
+	Quality      string            `firestore:"quality" json:"quality"`         // Quality level
+	SampleRate   int               `firestore:"sample_rate" json:"sample_rate"` // Sample rate
+	Size         int64             `firestore:"size" json:"size"`               // File size in bytes
+	IsPublic     bool              `firestore:"is_public" json:"is_public"`     // Whether to include in Nostr event
+	CreatedAt    time.Time         `firestore:"created_at" json:"created_at"`
+	Options      CompressionOption `firestore:"options" json:"options"`                                 // Original compression request
+	Renditions   []int             `firestore:"renditions,omitempty" json:"renditions,omitempty"`       // HLS only: packaged rendition bitrates
+	FileHash     string            `firestore:"file_hash,omitempty" json:"file_hash,omitempty"`         // Hex SHA-256 of the output file
+	ObjectKey    string            `firestore:"object_key,omitempty" json:"object_key,omitempty"`       // Storage object of the file, or the master playlist for HLS
+	ObjectPrefix string            `firestore:"object_prefix,omitempty" json:"object_prefix,omitempty"` // HLS only: prefix of every object in the package
+}
+
+
+// Upload states of a track original
+const (
+	UploadStatePending   = "pending"        // Upload URL issued, no bytes confirmed yet
+	UploadStateUploading = "uploading"      // Resumable session has committed some bytes
+	UploadStateComplete  = "complete"       // Original is stored
+	UploadStateExpired   = "upload_expired" // Upload URL or session expired before the original arrived
+)
+
+// UploadSession is a resumable upload session for a track original. The client sends
+// chunks to URL with Content-Range headers and resumes from Offset after an interruption.
+type UploadSession struct {
+	URL         string    `firestore:"url" json:"url"`                       // Session URL, a bearer capability for the upload
+	ContentType string    `firestore:"content_type" json:"content_type"`     // Content-Type the session was started with
+	Size        int64     `firestore:"size,omitempty" json:"size,omitempty"` // Declared total size in bytes, 0 when unknown
+	Offset      int64     `firestore:"offset" json:"offset"`                 // Bytes committed as of the last status check
+	ExpiresAt   time.Time `firestore:"expires_at" json:"expires_at"`         // When storage discards the session
 }
 
 type NostrTrack struct {
 	ID                    string               `firestore:"id" json:"id"`                                                         // UUID
 	FirebaseUID           string               `firestore:"firebase_uid" json:"firebase_uid"`                                     // User who uploaded
 	Pubkey                string               `firestore:"pubkey" json:"pubkey"`                                                 // Nostr pubkey
+	Title                 string               `firestore:"title,omitempty" json:"title,omitempty"`                               // Track title
+	Lyrics                string               `firestore:"lyrics,omitempty" json:"lyrics,omitempty"`                             // Lyrics text
+	IsExplicit            bool                 `firestore:"is_explicit,omitempty" json:"is_explicit,omitempty"`                   // Explicit content flag
 	OriginalURL           string               `firestore:"original_url" json:"original_url"`                                     // GCS URL for original file
+	OriginalObjectKey     string               `firestore:"original_object_key,omitempty" json:"original_object_key,omitempty"`   // Storage object of the original file
 	PresignedURL          string               `firestore:"-" json:"presigned_url,omitempty"`                                     // Temporary upload URL (not stored)
+	UploadHeaders         map[string]string    `firestore:"-" json:"upload_headers,omitempty"`                                    // Headers the upload request must send (not stored)
+	UploadState           string               `firestore:"upload_state,omitempty" json:"upload_state,omitempty"`                 // UploadState* value, empty for tracks created before upload tracking
+	UploadSession         *UploadSession       `firestore:"upload_session,omitempty" json:"upload_session,omitempty"`             // Resumable upload of the original, nil for one-shot uploads
+	UploadExpiresAt       *time.Time           `firestore:"upload_expires_at,omitempty" json:"upload_expires_at,omitempty"`       // When the one-shot upload URL expires
 	Extension             string               `firestore:"extension" json:"extension"`                                           // File extension
 	Size                  int64                `firestore:"size,omitempty" json:"size,omitempty"`                                 // Original file size in bytes
 	Duration              int                  `firestore:"duration,omitempty" json:"duration,omitempty"`                         // Duration in seconds
 	IsProcessing          bool                 `firestore:"is_processing" json:"is_processing"`                                   // Processing status
 	CompressionVersions   []CompressionVersion `firestore:"compression_versions,omitempty" json:"compression_versions,omitempty"` // All compressed versions
+	HasPublicVersions     bool                 `firestore:"has_public_versions" json:"has_public_versions"`                       // Whether any compression version is public, kept in sync for queries
+	Formats               []string             `firestore:"formats" json:"formats,omitempty"`                                     // Lowercase extension of the original and every version format, kept in sync for queries
 	HasPendingCompression bool                 `firestore:"has_pending_compression" json:"has_pending_compression"`               // Whether compression is queued
 	Deleted               bool                 `firestore:"deleted" json:"deleted"`                                               // Soft delete flag
+	DeletedAt             *time.Time           `firestore:"deleted_at,omitempty" json:"deleted_at,omitempty"`                     // When the track was soft deleted
 	NostrKind             int                  `firestore:"nostr_kind,omitempty" json:"nostr_kind,omitempty"`                     // Nostr event kind
 	NostrDTag             string               `firestore:"nostr_d_tag,omitempty" json:"nostr_d_tag,omitempty"`                   // Nostr d tag
+	ArtworkURL            string               `firestore:"artwork_url,omitempty" json:"artwork_url,omitempty"`                   // Primary artwork variant
+	ArtworkVariants       []ArtworkVariant     `firestore:"artwork_variants,omitempty" json:"artwork_variants,omitempty"`         // All resized artwork variants
+	LegacyTrackID         string               `firestore:"legacy_track_id,omitempty" json:"legacy_track_id,omitempty"`           // Source track when migrated from the legacy catalog
+	Loudness              *LoudnessInfo        `firestore:"loudness,omitempty" json:"loudness,omitempty"`                         // EBU R128 analysis of the original
+	FileHash              string               `firestore:"file_hash,omitempty" json:"file_hash,omitempty"`                       // Hex SHA-256 of the original upload
+	DuplicateOf           string               `firestore:"duplicate_of,omitempty" json:"duplicate_of,omitempty"`                 // Track by another pubkey with the same original
+	Error                 string               `firestore:"error,omitempty" json:"error,omitempty"`                               // Why processing failed
+	RejectionReasons      []string             `firestore:"rejection_reasons,omitempty" json:"rejection_reasons,omitempty"`       // Upload policy violations of the original
+	WaveformURL           string               `firestore:"waveform_url,omitempty" json:"waveform_url,omitempty"`                 // Default-resolution peak data for the web player
+	Waveforms             []WaveformVariant    `firestore:"waveforms,omitempty" json:"waveforms,omitempty"`                       // Peak data at every generated resolution
 	CreatedAt             time.Time            `firestore:"created_at" json:"created_at"`
 	UpdatedAt             time.Time            `firestore:"updated_at" json:"updated_at"`
 
@@ -101,6 +170,205 @@ type NostrTrack struct {
 	IsCompressed  bool   `firestore:"is_compressed" json:"is_compressed"`                       // Legacy compression status
 }
 
+// LegacyTrackMapping records which new track a legacy catalog track was migrated to.
+// Documents are keyed by the legacy track ID so a track is only migrated once.
+type LegacyTrackMapping struct {
+	LegacyTrackID string    `firestore:"legacy_track_id" json:"legacy_track_id"`
+	TrackID       string    `firestore:"track_id" json:"track_id"`
+	Pubkey        string    `firestore:"pubkey" json:"pubkey"`
+	FirebaseUID   string    `firestore:"firebase_uid" json:"firebase_uid"`
+	MigratedAt    time.Time `firestore:"migrated_at" json:"migrated_at"`
+}
+
+// LegacyMigrationResult is the outcome of migrating a single legacy track
+type LegacyMigrationResult struct {
+	LegacyTrackID string `json:"legacy_track_id"`
+	Title         string `json:"title"`
+	Status        string `json:"status"` // "migrated", "would_migrate", "skipped", "failed"
+	TrackID       string `json:"track_id,omitempty"`
+	Message       string `json:"message,omitempty"`
+}
+
+// LegacyMigrationReport summarizes a legacy catalog migration run
+type LegacyMigrationReport struct {
+	FirebaseUID string                  `json:"firebase_uid"`
+	Pubkey      string                  `json:"pubkey"`
+	DryRun      bool                    `json:"dry_run"`
+	Migrated    int                     `json:"migrated"`
+	Skipped     int                     `json:"skipped"`
+	Failed      int                     `json:"failed"`
+	Results     []LegacyMigrationResult `json:"results"`
+}
+
+// ObjectKeyBackfillResult is the outcome of backfilling one track's storage object keys
+type ObjectKeyBackfillResult struct {
+	TrackID           string `json:"track_id"`
+	Status            string `json:"status"` // "updated", "would_update", "skipped", "failed"
+	OriginalObjectKey string `json:"original_object_key,omitempty"`
+	VersionKeys       int    `json:"version_keys,omitempty"`   // Versions given a key
+	VersionFields     bool   `json:"version_fields,omitempty"` // has_public_versions and formats written
+	Message           string `json:"message,omitempty"`
+}
+
+// ObjectKeyBackfillReport summarizes an object key backfill run
+type ObjectKeyBackfillReport struct {
+	DryRun  bool                      `json:"dry_run"`
+	Updated int                       `json:"updated"`
+	Skipped int                       `json:"skipped"`
+	Failed  int                       `json:"failed"`
+	Results []ObjectKeyBackfillResult `json:"results"`
+}
+
+// TrackPurgeResult is the outcome of hard deleting one soft-deleted track
+type TrackPurgeResult struct {
+	TrackID     string    `json:"track_id"`
+	DeletedAt   time.Time `json:"deleted_at"`
+	Status      string    `json:"status"`                 // "purged", "would_purge", "failed"
+	FailedFiles []string  `json:"failed_files,omitempty"` // Objects or prefixes that could not be deleted
+	Message     string    `json:"message,omitempty"`
+}
+
+// TrackPurgeReport summarizes a purge of tracks soft deleted before Cutoff
+type TrackPurgeReport struct {
+	Cutoff  time.Time          `json:"cutoff"`
+	DryRun  bool               `json:"dry_run"`
+	Purged  int                `json:"purged"`
+	Failed  int                `json:"failed"`
+	Results []TrackPurgeResult `json:"results"`
+}
+
+// StorageOrphan is a stored object under a track prefix whose track no longer exists
+type StorageOrphan struct {
+	ObjectName string    `json:"object_name"`
+	TrackID    string    `json:"track_id,omitempty"` // Track ID parsed from the object name
+	Size       int64     `json:"size"`
+	CreatedAt  time.Time `json:"created_at"`
+	Status     string    `json:"status"` // "deleted", "would_delete", "in_grace_period", "failed"
+	Message    string    `json:"message,omitempty"`
+}
+
+// AbandonedTrack is a track record whose original was never uploaded
+type AbandonedTrack struct {
+	TrackID     string    `json:"track_id"`
+	Pubkey      string    `json:"pubkey"`
+	UploadState string    `json:"upload_state,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	Status      string    `json:"status"` // "deleted", "would_delete", "failed"
+	Message     string    `json:"message,omitempty"`
+}
+
+// StorageGCReport summarizes a storage garbage collection run. Only orphans and abandoned
+// tracks older than Cutoff are deleted.
+type StorageGCReport struct {
+	Cutoff          time.Time        `json:"cutoff"`
+	DryRun          bool             `json:"dry_run"`
+	TracksScanned   int              `json:"tracks_scanned"`
+	ObjectsScanned  int              `json:"objects_scanned"`
+	Deleted         int              `json:"deleted"`
+	Failed          int              `json:"failed"`
+	Orphans         []StorageOrphan  `json:"orphans"`
+	AbandonedTracks []AbandonedTrack `json:"abandoned_tracks"`
+}
+
+// NostrArtist is an artist profile in the new catalog, keyed by the owning Nostr pubkey
+type NostrArtist struct {
+	Pubkey        string    `firestore:"pubkey" json:"pubkey"`             // Primary key, owner of the profile
+	FirebaseUID   string    `firestore:"firebase_uid" json:"firebase_uid"` // User who created the profile
+	Name          string    `firestore:"name" json:"name"`
+	Bio           string    `firestore:"bio,omitempty" json:"bio,omitempty"`
+	ArtworkURL    string    `firestore:"artwork_url,omitempty" json:"artwork_url,omitempty"`
+	Website       string    `firestore:"website,omitempty" json:"website,omitempty"`
+	Twitter       string    `firestore:"twitter,omitempty" json:"twitter,omitempty"`
+	Instagram     string    `firestore:"instagram,omitempty" json:"instagram,omitempty"`
+	Youtube       string    `firestore:"youtube,omitempty" json:"youtube,omitempty"`
+	DefaultLadder []string  `firestore:"default_ladder,omitempty" json:"default_ladder,omitempty"` // Compression presets generated on upload, empty for the platform default
+	Deleted       bool      `firestore:"deleted" json:"deleted"`                                   // Soft delete flag
+	CreatedAt     time.Time `firestore:"created_at" json:"created_at"`
+	UpdatedAt     time.Time `firestore:"updated_at" json:"updated_at"`
+}
+
+// NostrAlbum groups an artist's tracks in a fixed order
+type NostrAlbum struct {
+	ID              string           `firestore:"id" json:"id"`                     // UUID
+	Pubkey          string           `firestore:"pubkey" json:"pubkey"`             // Owning artist pubkey
+	FirebaseUID     string           `firestore:"firebase_uid" json:"firebase_uid"` // User who created the album
+	Title           string           `firestore:"title" json:"title"`
+	Description     string           `firestore:"description,omitempty" json:"description,omitempty"`
+	ArtworkURL      string           `firestore:"artwork_url,omitempty" json:"artwork_url,omitempty"`
+	ArtworkVariants []ArtworkVariant `firestore:"artwork_variants,omitempty" json:"artwork_variants,omitempty"`
+	Genre           string           `firestore:"genre,omitempty" json:"genre,omitempty"`
+	IsSingle        bool             `firestore:"is_single" json:"is_single"`
+	IsDraft         bool             `firestore:"is_draft" json:"is_draft"`   // Drafts are only visible to the owner
+	TrackIDs        []string         `firestore:"track_ids" json:"track_ids"` // Track order within the album
+	Deleted         bool             `firestore:"deleted" json:"deleted"`     // Soft delete flag
+	PublishedAt     time.Time        `firestore:"published_at,omitempty" json:"published_at,omitempty"`
+	CreatedAt       time.Time        `firestore:"created_at" json:"created_at"`
+	UpdatedAt       time.Time        `firestore:"updated_at" json:"updated_at"`
+}
+
+// ArtworkVariant is a square, resized copy of an uploaded artwork image
+type ArtworkVariant struct {
+	Size   int    `firestore:"size" json:"size"`     // Edge length in pixels
+	Format string `firestore:"format" json:"format"` // "jpg" or "webp"
+	URL    string `firestore:"url" json:"url"`
+}
+
+// NostrArtwork tracks an artwork upload and the variants generated from it
+type NostrArtwork struct {
+	ID            string            `firestore:"id" json:"id"`                                 // UUID
+	Pubkey        string            `firestore:"pubkey" json:"pubkey"`                         // Uploader pubkey
+	FirebaseUID   string            `firestore:"firebase_uid" json:"firebase_uid"`             // User who uploaded
+	TargetType    string            `firestore:"target_type" json:"target_type"`               // "track" or "album"
+	TargetID      string            `firestore:"target_id" json:"target_id"`                   // Track or album the artwork is linked to
+	Extension     string            `firestore:"extension" json:"extension"`                   // Uploaded file extension
+	OriginalURL   string            `firestore:"original_url" json:"original_url"`             // URL of the unprocessed upload
+	PresignedURL  string            `firestore:"-" json:"presigned_url,omitempty"`             // Temporary upload URL (not stored)
+	UploadHeaders map[string]string `firestore:"-" json:"upload_headers,omitempty"`            // Headers the upload request must send (not stored)
+	IsProcessing  bool              `firestore:"is_processing" json:"is_processing"`           // Processing status
+	Variants      []ArtworkVariant  `firestore:"variants,omitempty" json:"variants,omitempty"` // Generated variants
+	Width         int               `firestore:"width,omitempty" json:"width,omitempty"`       // Source image width
+	Height        int               `firestore:"height,omitempty" json:"height,omitempty"`     // Source image height
+	Error         string            `firestore:"error,omitempty" json:"error,omitempty"`       // Processing failure reason
+	CreatedAt     time.Time         `firestore:"created_at" json:"created_at"`
+	UpdatedAt     time.Time         `firestore:"updated_at" json:"updated_at"`
+}
+
+// TrackFingerprint is the acoustic fingerprint of a track's original, keyed by track ID
+type TrackFingerprint struct {
+	TrackID     string    `firestore:"track_id" json:"track_id"`
+	Pubkey      string    `firestore:"pubkey" json:"pubkey"`           // Track owner
+	Duration    float64   `firestore:"duration" json:"duration"`       // Seconds of audio fingerprinted
+	Fingerprint []int64   `firestore:"fingerprint" json:"fingerprint"` // Chromaprint raw sub-fingerprints
+	IndexKeys   []int64   `firestore:"index_keys" json:"index_keys"`   // Coarse keys for candidate lookup
+	CreatedAt   time.Time `firestore:"created_at" json:"created_at"`
+}
+
+// FingerprintMatch is an existing track whose fingerprint is close to a new upload's
+type FingerprintMatch struct {
+	TrackID    string  `json:"track_id"`
+	Pubkey     string  `json:"pubkey"`
+	Similarity float64 `json:"similarity"` // 0-1, one minus the bit error rate
+}
+
+// Moderation queue entry types and statuses
+const (
+	ModerationTypeFingerprintMatch = "fingerprint_match"
+	ModerationStatusPending        = "pending"
+)
+
+// ModerationEntry is an item awaiting review in the moderation queue
+type ModerationEntry struct {
+	ID             string    `firestore:"id" json:"id"`                             // UUID
+	Type           string    `firestore:"type" json:"type"`                         // e.g. "fingerprint_match"
+	Status         string    `firestore:"status" json:"status"`                     // "pending" until reviewed
+	TrackID        string    `firestore:"track_id" json:"track_id"`                 // The new upload
+	Pubkey         string    `firestore:"pubkey" json:"pubkey"`                     // Owner of the new upload
+	MatchedTrackID string    `firestore:"matched_track_id" json:"matched_track_id"` // Existing track it resembles
+	MatchedPubkey  string    `firestore:"matched_pubkey" json:"matched_pubkey"`     // Owner of the existing track
+	Similarity     float64   `firestore:"similarity" json:"similarity"`
+	CreatedAt      time.Time `firestore:"created_at" json:"created_at"`
+}
+
 // VersionUpdate represents a request to update compression version visibility
 type VersionUpdate struct {
 	VersionID string `json:"version_id"`
@@ -183,8 +451,60 @@ type LegacyAlbum struct {
 	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
 }
 
+// === Pagination ===
+
+// ListOptions controls cursor pagination and ordering for list endpoints
+type ListOptions struct {
+	Limit     int    // Maximum number of items to return
+	Cursor    string // Opaque next_cursor from the previous page
+	SortBy    string // Field to sort by, e.g. "created_at"
+	SortOrder string // "asc" or "desc"
+}
+
+// TrackFilter narrows track listings. Nil fields are not filtered on.
+type TrackFilter struct {
+	IsProcessing      *bool
+	Deleted           *bool // Defaults to excluding deleted tracks
+	HasPublicVersions *bool
+	Format            string // Original file extension or compressed version format
+}
+
+// NostrTrackPage is one page of tracks and the cursor for the next page
+type NostrTrackPage struct {
+	Tracks     []*NostrTrack
+	NextCursor string
+}
+
+// LegacyTrackPage is one page of legacy tracks and the cursor for the next page
+type LegacyTrackPage struct {
+	Tracks     []LegacyTrack
+	NextCursor string
+}
+
+// LegacyArtistPage is one page of legacy artists and the cursor for the next page
+type LegacyArtistPage struct {
+	Artists    []LegacyArtist
+	NextCursor string
+}
+
+// LegacyAlbumPage is one page of legacy albums and the cursor for the next page
+type LegacyAlbumPage struct {
+	Albums     []LegacyAlbum
+	NextCursor string
+}
+
 // === Phase 2 Models ===
 
+// SignedURLOptions controls a signed storage URL
+type SignedURLOptions struct {
+	Method             string        // HTTP method the URL is valid for, defaults to GET
+	Expiration         time.Duration // How long the URL stays valid
+	ContentDisposition string        // Overrides the response Content-Disposition, e.g. `attachment; filename="song.mp3"`
+	MaxContentLength   int64         // Uploads only: largest accepted upload in bytes, 0 for no limit
+	Resumable          bool          // The URL starts a resumable upload session; implies POST
+	ContentType        string        // Resumable only: Content-Type of the uploaded object
+}
+
 // FileUploadToken represents a token for file upload authentication
 type FileUploadToken struct {
 	Token     string    `json:"token"`
@@ -201,6 +521,7 @@ type FileMetadata struct {
 	ContentType string            `json:"content_type"`
 	Bucket      string            `json:"bucket"`
 	URL         string            `json:"url,omitempty"`
+	ETag        string            `json:"etag,omitempty"`
 	Metadata    map[string]string `json:"metadata,omitempty"`
 	CreatedAt   time.Time         `json:"created_at"`
 	UpdatedAt   time.Time         `json:"updated_at"`
@@ -246,13 +567,15 @@ type LogEntry struct {
 
 // ProcessingStatus represents the status of track processing
 type ProcessingStatus struct {
-	TrackID     string    `json:"track_id"`
-	Status      string    `json:"status"` // "queued", "processing", "completed", "failed"
-	Progress    int       `json:"progress"` // 0-100
-	Message     string    `json:"message,omitempty"`
-	Error       string    `json:"error,omitempty"`
-	StartedAt   time.Time `json:"started_at,omitempty"`
-	CompletedAt time.Time `json:"completed_at,omitempty"`
+	TrackID          string    `json:"track_id"`
+	Status           string    `json:"status"`   // "queued", "processing", "completed", "failed"
+	Progress         int       `json:"progress"` // 0-100
+	Message          string    `json:"message,omitempty"`
+	Error            string    `json:"error,omitempty"`
+	StartedAt        time.Time `json:"started_at,omitempty"`
+	CompletedAt      time.Time `json:"completed_at,omitempty"`
+	DuplicateOf      string    `json:"duplicate_of,omitempty"`      // Track by another pubkey with the same original file
+	RejectionReasons []string  `json:"rejection_reasons,omitempty"` // Upload policy violations of the original
 }
 
 // AudioMetadata represents metadata extracted from audio files
@@ -0,0 +1,341 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/google/uuid"
+	"github.com/wavlake/monorepo/internal/models"
+	"github.com/wavlake/monorepo/internal/utils"
+)
+
+const (
+	ArtworkTargetTrack = "track"
+	ArtworkTargetAlbum = "album"
+
+	// primaryArtworkSize is the variant linked as artwork_url on tracks and albums
+	primaryArtworkSize = 600
+)
+
+var (
+	// ErrUnsupportedImageFormat is returned when an artwork upload has an unsupported extension
+	ErrUnsupportedImageFormat = errors.New("unsupported image format")
+	// ErrInvalidArtworkTarget is returned when artwork is attached to a track or album the caller cannot use
+	ErrInvalidArtworkTarget = errors.New("invalid artwork target")
+)
+
+type ArtworkService struct {
+	firestoreClient   *firestore.Client
+	storageService    StorageServiceInterface
+	nostrTrackService NostrTrackServiceInterface
+	nostrAlbumService NostrAlbumServiceInterface
+	imageProcessor    *utils.ImageProcessor
+	tempDir           string
+	pathConfig        *utils.StoragePathConfig
+}
+
+func NewArtworkService(firestoreClient *firestore.Client, storageService StorageServiceInterface, nostrTrackService NostrTrackServiceInterface, nostrAlbumService NostrAlbumServiceInterface, imageProcessor *utils.ImageProcessor, tempDir string) *ArtworkService {
+	return &ArtworkService{
+		firestoreClient:   firestoreClient,
+		storageService:    storageService,
+		nostrTrackService: nostrTrackService,
+		nostrAlbumService: nostrAlbumService,
+		imageProcessor:    imageProcessor,
+		tempDir:           tempDir,
+		pathConfig:        utils.GetStoragePathConfig(),
+	}
+}
+
+// CreateArtworkUpload creates an artwork record for a track or album and returns a presigned upload URL.
+// The URL only accepts uploads up to utils.MaxArtworkFileSize, and the upload must send the
+// returned UploadHeaders.
+func (s *ArtworkService) CreateArtworkUpload(ctx context.Context, pubkey, firebaseUID, targetType, targetID, extension string) (*models.NostrArtwork, error) {
+	extension = strings.ToLower(strings.TrimPrefix(extension, "."))
+	if !s.imageProcessor.IsImageFormatSupported(extension) {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedImageFormat, extension)
+	}
+
+	if err := s.validateTarget(ctx, pubkey, targetType, targetID); err != nil {
+		return nil, err
+	}
+
+	artworkID := uuid.New().String()
+	now := time.Now()
+
+	originalObjectName := s.pathConfig.GetArtworkOriginalPath(artworkID, extension)
+
+	// Generate presigned URL for upload (valid for 1 hour)
+	presignedURL, err := s.storageService.GenerateSignedURL(ctx, originalObjectName, models.SignedURLOptions{
+		Method:           http.MethodPut,
+		Expiration:       time.Hour,
+		MaxContentLength: utils.MaxArtworkFileSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate presigned URL: %w", err)
+	}
+
+	artwork := &models.NostrArtwork{
+		ID:           artworkID,
+		Pubkey:       pubkey,
+		FirebaseUID:  firebaseUID,
+		TargetType:   targetType,
+		TargetID:     targetID,
+		Extension:    extension,
+		OriginalURL:  s.storageService.GetPublicURL(originalObjectName),
+		PresignedURL: presignedURL,
+		UploadHeaders: map[string]string{
+			ContentLengthRangeHeader: ContentLengthRangeValue(utils.MaxArtworkFileSize),
+		},
+		IsProcessing: true,
+		Variants:     []models.ArtworkVariant{},
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	_, err = s.firestoreClient.Collection("nostr_artwork").Doc(artworkID).Set(ctx, artwork)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save artwork to firestore: %w", err)
+	}
+
+	log.Printf("Created artwork upload %s for %s %s", artworkID, targetType, targetID)
+	return artwork, nil
+}
+
+// GetArtwork retrieves an artwork record by ID
+func (s *ArtworkService) GetArtwork(ctx context.Context, artworkID string) (*models.NostrArtwork, error) {
+	doc, err := s.firestoreClient.Collection("nostr_artwork").Doc(artworkID).Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get artwork: %w", err)
+	}
+
+	var artwork models.NostrArtwork
+	if err := doc.DataTo(&artwork); err != nil {
+		return nil, fmt.Errorf("failed to decode artwork: %w", err)
+	}
+
+	return &artwork, nil
+}
+
+// ProcessArtwork validates an uploaded image, generates square variants and links them to the target
+func (s *ArtworkService) ProcessArtwork(ctx context.Context, artworkID string) error {
+	log.Printf("Starting artwork processing for %s", artworkID)
+
+	artwork, err := s.GetArtwork(ctx, artworkID)
+	if err != nil {
+		return err
+	}
+
+	workDir, err := os.MkdirTemp(s.tempDir, "artwork_"+artworkID+"_")
+	if err != nil {
+		return fmt.Errorf("failed to create work directory: %w", err)
+	}
+	defer func() {
+		_ = os.RemoveAll(workDir) // #nosec G104 -- Cleanup operation, errors not critical
+	}()
+
+	originalPath := filepath.Join(workDir, "original."+artwork.Extension)
+	if err := s.downloadOriginal(ctx, artwork, originalPath); err != nil {
+		return s.markArtworkFailed(ctx, artworkID, fmt.Sprintf("download failed: %v", err))
+	}
+
+	info, err := s.imageProcessor.ValidateImageFile(ctx, originalPath)
+	if err != nil {
+		return s.markArtworkFailed(ctx, artworkID, fmt.Sprintf("invalid image: %v", err))
+	}
+
+	var variants []models.ArtworkVariant
+	for _, size := range s.imageProcessor.GetVariantSizes(info.Width, info.Height) {
+		for _, format := range utils.ArtworkVariantFormats {
+			variant, err := s.generateVariant(ctx, artworkID, originalPath, workDir, size, format)
+			if err != nil {
+				return s.markArtworkFailed(ctx, artworkID, err.Error())
+			}
+			variants = append(variants, *variant)
+		}
+	}
+
+	if err := s.updateArtwork(ctx, artworkID, map[string]interface{}{
+		"is_processing": false,
+		"variants":      variants,
+		"width":         info.Width,
+		"height":        info.Height,
+		"error":         firestore.Delete,
+	}); err != nil {
+		return err
+	}
+
+	if err := s.linkToTarget(ctx, artwork, variants); err != nil {
+		return fmt.Errorf("failed to link artwork to %s %s: %w", artwork.TargetType, artwork.TargetID, err)
+	}
+
+	log.Printf("Successfully processed artwork %s (%d variants)", artworkID, len(variants))
+	return nil
+}
+
+// ProcessArtworkAsync starts artwork processing in a goroutine
+func (s *ArtworkService) ProcessArtworkAsync(ctx context.Context, artworkID string) {
+	go func() {
+		processCtx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+
+		if err := s.ProcessArtwork(processCtx, artworkID); err != nil {
+			log.Printf("Async artwork processing failed for %s: %v", artworkID, err)
+		}
+	}()
+}
+
+// validateTarget checks that the track or album exists, is not deleted and is owned by pubkey
+func (s *ArtworkService) validateTarget(ctx context.Context, pubkey, targetType, targetID string) error {
+	if targetID == "" {
+		return fmt.Errorf("%w: target_id is required", ErrInvalidArtworkTarget)
+	}
+
+	var owner string
+	switch targetType {
+	case ArtworkTargetTrack:
+		track, err := s.nostrTrackService.GetTrack(ctx, targetID)
+		if err != nil || track.Deleted {
+			return fmt.Errorf("%w: track %s not found", ErrInvalidArtworkTarget, targetID)
+		}
+		owner = track.Pubkey
+	case ArtworkTargetAlbum:
+		album, err := s.nostrAlbumService.GetAlbum(ctx, targetID)
+		if err != nil || album.Deleted {
+			return fmt.Errorf("%w: album %s not found", ErrInvalidArtworkTarget, targetID)
+		}
+		owner = album.Pubkey
+	default:
+		return fmt.Errorf("%w: target_type must be %q or %q", ErrInvalidArtworkTarget, ArtworkTargetTrack, ArtworkTargetAlbum)
+	}
+
+	if owner != pubkey {
+		return fmt.Errorf("%w: %s %s is not owned by this pubkey", ErrInvalidArtworkTarget, targetType, targetID)
+	}
+
+	return nil
+}
+
+// downloadOriginal copies the uploaded image from storage to a local path. Images over
+// utils.MaxArtworkFileSize are rejected before downloading.
+func (s *ArtworkService) downloadOriginal(ctx context.Context, artwork *models.NostrArtwork, filePath string) error {
+	objectName := s.pathConfig.GetArtworkOriginalPath(artwork.ID, artwork.Extension)
+	info, err := s.storageService.GetObjectInfo(ctx, objectName)
+	if err != nil {
+		return fmt.Errorf("failed to get image info: %w", err)
+	}
+	if info.Size > utils.MaxArtworkFileSize {
+		return fmt.Errorf("image is too large: %d bytes (max %d)", info.Size, utils.MaxArtworkFileSize)
+	}
+
+	reader, err := s.storageService.GetObjectReader(ctx, objectName)
+	if err != nil {
+		return fmt.Errorf("failed to create storage reader: %w", err)
+	}
+	defer reader.Close()
+
+	file, err := os.Create(filePath) // #nosec G304 -- Creating controlled temp file for processing
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.ReadFrom(reader); err != nil {
+		return fmt.Errorf("failed to download file: %w", err)
+	}
+
+	return nil
+}
+
+// generateVariant resizes the original to one size/format and uploads the result
+func (s *ArtworkService) generateVariant(ctx context.Context, artworkID, originalPath, workDir string, size int, format string) (*models.ArtworkVariant, error) {
+	variantPath := filepath.Join(workDir, fmt.Sprintf("%d.%s", size, format))
+	if err := s.imageProcessor.ResizeSquare(ctx, originalPath, variantPath, size, format); err != nil {
+		return nil, fmt.Errorf("resize to %dpx %s failed: %v", size, format, err)
+	}
+
+	variantFile, err := os.Open(variantPath) // #nosec G304 -- Opening controlled temp file for upload
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %dpx %s variant: %v", size, format, err)
+	}
+	defer variantFile.Close()
+
+	objectName := s.pathConfig.GetArtworkVariantPath(artworkID, size, format)
+	if err := s.storageService.UploadObject(ctx, objectName, variantFile, utils.GetImageContentType(format)); err != nil {
+		return nil, fmt.Errorf("failed to upload %dpx %s variant: %v", size, format, err)
+	}
+
+	return &models.ArtworkVariant{
+		Size:   size,
+		Format: format,
+		URL:    s.storageService.GetPublicURL(objectName),
+	}, nil
+}
+
+// linkToTarget stores the variants on the track or album the artwork was uploaded for
+func (s *ArtworkService) linkToTarget(ctx context.Context, artwork *models.NostrArtwork, variants []models.ArtworkVariant) error {
+	updates := map[string]interface{}{
+		"artwork_url":      primaryArtworkURL(variants),
+		"artwork_variants": variants,
+	}
+
+	switch artwork.TargetType {
+	case ArtworkTargetTrack:
+		return s.nostrTrackService.UpdateTrack(ctx, artwork.TargetID, updates)
+	case ArtworkTargetAlbum:
+		return s.nostrAlbumService.UpdateAlbum(ctx, artwork.TargetID, updates)
+	default:
+		return fmt.Errorf("unknown artwork target type: %s", artwork.TargetType)
+	}
+}
+
+// primaryArtworkURL picks the largest JPEG variant no bigger than primaryArtworkSize
+func primaryArtworkURL(variants []models.ArtworkVariant) string {
+	url := ""
+	best := 0
+	for _, variant := range variants {
+		if variant.Format == "jpg" && variant.Size <= primaryArtworkSize && variant.Size > best {
+			url = variant.URL
+			best = variant.Size
+		}
+	}
+	return url
+}
+
+// updateArtwork applies updates to an artwork record
+func (s *ArtworkService) updateArtwork(ctx context.Context, artworkID string, updates map[string]interface{}) error {
+	updates["updated_at"] = time.Now()
+
+	var updatePaths []firestore.Update
+	for path, value := range updates {
+		updatePaths = append(updatePaths, firestore.Update{Path: path, Value: value})
+	}
+
+	_, err := s.firestoreClient.Collection("nostr_artwork").Doc(artworkID).Update(ctx, updatePaths)
+	if err != nil {
+		return fmt.Errorf("failed to update artwork: %w", err)
+	}
+
+	return nil
+}
+
+// markArtworkFailed records a processing failure on the artwork record
+func (s *ArtworkService) markArtworkFailed(ctx context.Context, artworkID, errorMsg string) error {
+	log.Printf("Artwork processing failed for %s: %s", artworkID, errorMsg)
+
+	if err := s.updateArtwork(ctx, artworkID, map[string]interface{}{
+		"is_processing": false,
+		"error":         errorMsg,
+	}); err != nil {
+		return err
+	}
+
+	return fmt.Errorf("artwork processing failed: %s", errorMsg)
+}
@@ -49,16 +49,26 @@ func (s *CompressionService) GetCompressionStatus(ctx context.Context, trackID s
 	status := "completed"
 	if track.IsProcessing {
 		status = "processing"
+	} else if track.Error != "" {
+		status = "failed"
 	} else if track.HasPendingCompression {
 		status = "queued"
 	}
 
+	message := "Compression status retrieved"
+	if track.DuplicateOf != "" {
+		message = fmt.Sprintf("Upload matches track %s by another artist", track.DuplicateOf)
+	}
+
 	return &models.ProcessingStatus{
-		TrackID:   trackID,
-		Status:    status,
-		Progress:  100,
-		Message:   "Compression status retrieved",
-		StartedAt: track.CreatedAt,
+		TrackID:          trackID,
+		Status:           status,
+		Progress:         100,
+		Message:          message,
+		Error:            track.Error,
+		StartedAt:        track.CreatedAt,
+		DuplicateOf:      track.DuplicateOf,
+		RejectionReasons: track.RejectionReasons,
 	}, nil
 }
 
@@ -95,15 +105,7 @@ func (s *CompressionService) GetPublicVersions(ctx context.Context, trackID stri
 	return publicVersions, nil
 }
 
-// DeleteCompressionVersion deletes a compression version
+// DeleteCompressionVersion deletes a compression version and its stored file
 func (s *CompressionService) DeleteCompressionVersion(ctx context.Context, trackID, versionID string) error {
-	// In a real implementation, this would remove the version from storage and update the track
-	// For now, just validate the track exists
-	_, err := s.nostrTrackService.GetTrack(ctx, trackID)
-	if err != nil {
-		return fmt.Errorf("track not found: %w", err)
-	}
-
-	// In practice, would modify the CompressionVersions slice
-	return nil
+	return s.nostrTrackService.DeleteCompressionVersion(ctx, trackID, versionID)
 }
\ No newline at end of file
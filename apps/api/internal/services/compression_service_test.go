@@ -147,6 +147,26 @@ var _ = Describe("CompressionService", func() {
 			Expect(status.Status).To(Equal("queued"))
 		})
 
+		It("should surface rejected duplicate uploads", func() {
+			track := &models.NostrTrack{
+				ID:          testTrackID,
+				DuplicateOf: "other-track",
+				Error:       "duplicate upload: matches track other-track",
+				CreatedAt:   time.Now(),
+			}
+
+			mockNostrTrack.EXPECT().
+				GetTrack(ctx, testTrackID).
+				Return(track, nil)
+
+			status, err := compressionService.GetCompressionStatus(ctx, testTrackID)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(status.Status).To(Equal("failed"))
+			Expect(status.DuplicateOf).To(Equal("other-track"))
+			Expect(status.Error).To(ContainSubstring("duplicate upload"))
+		})
+
 		It("should return error for invalid track ID", func() {
 			mockNostrTrack.EXPECT().
 				GetTrack(ctx, testTrackID).
@@ -295,27 +315,24 @@ var _ = Describe("CompressionService", func() {
 			versionID := "version-123"
 
 			mockNostrTrack.EXPECT().
-				GetTrack(ctx, testTrackID).
-				Return(&models.NostrTrack{
-					ID: testTrackID,
-				}, nil)
+				DeleteCompressionVersion(ctx, testTrackID, versionID).
+				Return(nil)
 
 			err := compressionService.DeleteCompressionVersion(ctx, testTrackID, versionID)
 			
 			Expect(err).ToNot(HaveOccurred())
 		})
 
-		It("should return error when track not found", func() {
+		It("should return error when the version cannot be deleted", func() {
 			versionID := "version-123"
 
 			mockNostrTrack.EXPECT().
-				GetTrack(ctx, testTrackID).
-				Return(nil, errors.New("track not found"))
+				DeleteCompressionVersion(ctx, testTrackID, versionID).
+				Return(services.ErrVersionNotFound)
 
 			err := compressionService.DeleteCompressionVersion(ctx, testTrackID, versionID)
 			
-			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(ContainSubstring("track not found"))
+			Expect(err).To(MatchError(services.ErrVersionNotFound))
 		})
 	})
 })
\ No newline at end of file
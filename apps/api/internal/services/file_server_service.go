@@ -9,18 +9,24 @@ import (
 	"time"
 
 	"github.com/wavlake/monorepo/internal/models"
+	"github.com/wavlake/monorepo/internal/utils"
 )
 
 // FileServerService handles file server operations
 type FileServerService struct {
 	basePath     string
+	baseURL      string
+	signingKey   []byte
 	tokenService TokenServiceInterface
 }
 
-// NewFileServerService creates a new file server service
-func NewFileServerService(basePath string, tokenService TokenServiceInterface) *FileServerService {
+// NewFileServerService creates a new file server service. baseURL is the public address of
+// the file server and signingKey must match the FILE_SERVER_SIGNING_KEY it was started with.
+func NewFileServerService(basePath, baseURL string, signingKey []byte, tokenService TokenServiceInterface) *FileServerService {
 	return &FileServerService{
 		basePath:     basePath,
+		baseURL:      baseURL,
+		signingKey:   signingKey,
 		tokenService: tokenService,
 	}
 }
@@ -131,4 +137,12 @@ func (s *FileServerService) GetFileMetadata(ctx context.Context, path string) (*
 // GenerateUploadToken generates a token for file upload
 func (s *FileServerService) GenerateUploadToken(ctx context.Context, path, userID string, expiration time.Duration) (*models.FileUploadToken, error) {
 	return s.tokenService.GenerateUploadToken(ctx, path, userID, expiration)
-}
\ No newline at end of file
+}
+
+// GenerateSignedURL creates a signed file server URL, mirroring GCS signed URLs
+func (s *FileServerService) GenerateSignedURL(ctx context.Context, path string, opts models.SignedURLOptions) (string, error) {
+	if len(s.signingKey) == 0 {
+		return "", fmt.Errorf("file server signing key is not configured")
+	}
+	return utils.BuildFileServerSignedURL(s.baseURL, s.signingKey, path, time.Now().Add(opts.Expiration), opts), nil
+}
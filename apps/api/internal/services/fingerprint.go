@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/google/uuid"
+	"github.com/wavlake/monorepo/internal/models"
+	"github.com/wavlake/monorepo/internal/utils"
+	"google.golang.org/api/iterator"
+)
+
+// FingerprintService stores acoustic fingerprints and finds near-duplicate recordings
+type FingerprintService struct {
+	firestoreClient *firestore.Client
+}
+
+func NewFingerprintService(firestoreClient *firestore.Client) *FingerprintService {
+	return &FingerprintService{
+		firestoreClient: firestoreClient,
+	}
+}
+
+// SaveFingerprint stores a track's fingerprint, replacing any earlier one
+func (s *FingerprintService) SaveFingerprint(ctx context.Context, fingerprint *models.TrackFingerprint) error {
+	fingerprint.CreatedAt = time.Now()
+
+	_, err := s.firestoreClient.Collection("track_fingerprints").Doc(fingerprint.TrackID).Set(ctx, fingerprint)
+	if err != nil {
+		return fmt.Errorf("failed to save fingerprint: %w", err)
+	}
+
+	return nil
+}
+
+// FindMatches returns tracks whose fingerprint shares an index key with the given one and
+// scores at least threshold, best match first. The fingerprint's own track is excluded.
+func (s *FingerprintService) FindMatches(ctx context.Context, fingerprint *models.TrackFingerprint, threshold float64) ([]models.FingerprintMatch, error) {
+	if len(fingerprint.IndexKeys) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]interface{}, 0, len(fingerprint.IndexKeys))
+	for _, key := range fingerprint.IndexKeys {
+		keys = append(keys, key)
+	}
+
+	iter := s.firestoreClient.Collection("track_fingerprints").
+		Where("index_keys", "array-contains-any", keys).
+		Documents(ctx)
+	defer iter.Stop()
+
+	values := toFingerprintValues(fingerprint.Fingerprint)
+	var matches []models.FingerprintMatch
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate fingerprints: %w", err)
+		}
+
+		var candidate models.TrackFingerprint
+		if err := doc.DataTo(&candidate); err != nil {
+			log.Printf("Failed to decode fingerprint %s: %v", doc.Ref.ID, err)
+			continue
+		}
+		if candidate.TrackID == fingerprint.TrackID {
+			continue
+		}
+
+		similarity := utils.CompareFingerprints(values, toFingerprintValues(candidate.Fingerprint))
+		if similarity >= threshold {
+			matches = append(matches, models.FingerprintMatch{
+				TrackID:    candidate.TrackID,
+				Pubkey:     candidate.Pubkey,
+				Similarity: similarity,
+			})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Similarity > matches[j].Similarity
+	})
+
+	return matches, nil
+}
+
+// CreateModerationEntry adds a pending item to the moderation queue
+func (s *FingerprintService) CreateModerationEntry(ctx context.Context, entry *models.ModerationEntry) error {
+	entry.ID = uuid.New().String()
+	entry.Status = models.ModerationStatusPending
+	entry.CreatedAt = time.Now()
+
+	_, err := s.firestoreClient.Collection("moderation_queue").Doc(entry.ID).Set(ctx, entry)
+	if err != nil {
+		return fmt.Errorf("failed to create moderation entry: %w", err)
+	}
+
+	return nil
+}
+
+// NewTrackFingerprint converts a computed fingerprint into its stored form
+func NewTrackFingerprint(trackID, pubkey string, fingerprint *utils.Fingerprint) *models.TrackFingerprint {
+	values := make([]int64, len(fingerprint.Values))
+	for i, value := range fingerprint.Values {
+		values[i] = int64(value)
+	}
+
+	return &models.TrackFingerprint{
+		TrackID:     trackID,
+		Pubkey:      pubkey,
+		Duration:    fingerprint.Duration,
+		Fingerprint: values,
+		IndexKeys:   utils.FingerprintIndexKeys(fingerprint.Values),
+	}
+}
+
+func toFingerprintValues(stored []int64) []uint32 {
+	values := make([]uint32, len(stored))
+	for i, value := range stored {
+		values[i] = uint32(value) // #nosec G115 -- Stored values were converted from uint32
+	}
+	return values
+}
@@ -25,10 +25,18 @@ type PostgresServiceInterface interface {
 	GetUserTracks(ctx context.Context, firebaseUID string) ([]models.LegacyTrack, error)
 	GetUserArtists(ctx context.Context, firebaseUID string) ([]models.LegacyArtist, error)
 	GetUserAlbums(ctx context.Context, firebaseUID string) ([]models.LegacyAlbum, error)
+	ListUserTracks(ctx context.Context, firebaseUID string, opts models.ListOptions, filter models.TrackFilter) (*models.LegacyTrackPage, error)
+	ListUserArtists(ctx context.Context, firebaseUID string, opts models.ListOptions) (*models.LegacyArtistPage, error)
+	ListUserAlbums(ctx context.Context, firebaseUID string, opts models.ListOptions) (*models.LegacyAlbumPage, error)
 	GetTracksByArtist(ctx context.Context, artistID string) ([]models.LegacyTrack, error)
 	GetTracksByAlbum(ctx context.Context, albumID string) ([]models.LegacyTrack, error)
 }
 
+// LegacyMigrationServiceInterface defines the interface for migrating legacy catalog data
+type LegacyMigrationServiceInterface interface {
+	MigrateUserTracks(ctx context.Context, firebaseUID, pubkey string, dryRun bool) (*models.LegacyMigrationReport, error)
+}
+
 // StorageServiceInterface defines the interface for storage operations
 type StorageServiceInterface interface {
 	GeneratePresignedURL(ctx context.Context, objectName string, expiration time.Duration) (string, error)
@@ -36,8 +44,12 @@ type StorageServiceInterface interface {
 	UploadObject(ctx context.Context, objectName string, data io.Reader, contentType string) error
 	CopyObject(ctx context.Context, srcObject, dstObject string) error
 	DeleteObject(ctx context.Context, objectName string) error
+	ListObjects(ctx context.Context, prefix string) ([]string, error)
 	GetObjectMetadata(ctx context.Context, objectName string) (interface{}, error)
 	GetObjectReader(ctx context.Context, objectName string) (io.ReadCloser, error)
+	GetObjectRangeReader(ctx context.Context, objectName string, offset, length int64) (io.ReadCloser, error)
+	GetObjectInfo(ctx context.Context, objectName string) (*models.FileMetadata, error)
+	GenerateSignedURL(ctx context.Context, objectName string, opts models.SignedURLOptions) (string, error)
 	GetBucketName() string
 	Close() error
 }
@@ -45,19 +57,51 @@ type StorageServiceInterface interface {
 // NostrTrackServiceInterface defines the interface for Nostr track operations
 type NostrTrackServiceInterface interface {
 	CreateTrack(ctx context.Context, pubkey, firebaseUID, extension string) (*models.NostrTrack, error)
+	CreateResumableTrack(ctx context.Context, pubkey, firebaseUID, extension string, size int64) (*models.NostrTrack, error)
+	RefreshUploadStatus(ctx context.Context, trackID string) (*models.NostrTrack, error)
+	RenewUpload(ctx context.Context, trackID string) (*models.NostrTrack, error)
 	GetTrack(ctx context.Context, trackID string) (*models.NostrTrack, error)
 	GetTracksByPubkey(ctx context.Context, pubkey string) ([]*models.NostrTrack, error)
+	FindTracksByFileHash(ctx context.Context, fileHash string) ([]*models.NostrTrack, error)
 	GetTracksByFirebaseUID(ctx context.Context, firebaseUID string) ([]*models.NostrTrack, error)
+	ListTracksByPubkey(ctx context.Context, pubkey string, opts models.ListOptions, filter models.TrackFilter) (*models.NostrTrackPage, error)
 	UpdateTrack(ctx context.Context, trackID string, updates map[string]interface{}) error
 	MarkTrackAsProcessed(ctx context.Context, trackID string, size int64, duration int) error
 	MarkTrackAsCompressed(ctx context.Context, trackID, compressedURL string) error
 	DeleteTrack(ctx context.Context, trackID string) error
 	HardDeleteTrack(ctx context.Context, trackID string) error
+	DeleteCompressionVersion(ctx context.Context, trackID, versionID string) error
 	UpdateCompressionVisibility(ctx context.Context, trackID string, updates []models.VersionUpdate) error
 	AddCompressionVersion(ctx context.Context, trackID string, version models.CompressionVersion) error
 	SetPendingCompression(ctx context.Context, trackID string, pending bool) error
 }
 
+// NostrArtistServiceInterface defines the interface for artist profile operations
+type NostrArtistServiceInterface interface {
+	CreateArtist(ctx context.Context, artist *models.NostrArtist) error
+	GetArtist(ctx context.Context, pubkey string) (*models.NostrArtist, error)
+	UpdateArtist(ctx context.Context, pubkey string, updates map[string]interface{}) error
+	DeleteArtist(ctx context.Context, pubkey string) error
+}
+
+// NostrAlbumServiceInterface defines the interface for album operations
+type NostrAlbumServiceInterface interface {
+	CreateAlbum(ctx context.Context, album *models.NostrAlbum) error
+	GetAlbum(ctx context.Context, albumID string) (*models.NostrAlbum, error)
+	GetAlbumsByPubkey(ctx context.Context, pubkey string) ([]*models.NostrAlbum, error)
+	UpdateAlbum(ctx context.Context, albumID string, updates map[string]interface{}) error
+	SetAlbumTracks(ctx context.Context, albumID string, trackIDs []string) error
+	DeleteAlbum(ctx context.Context, albumID string) error
+}
+
+// ArtworkServiceInterface defines the interface for artwork upload and processing
+type ArtworkServiceInterface interface {
+	CreateArtworkUpload(ctx context.Context, pubkey, firebaseUID, targetType, targetID, extension string) (*models.NostrArtwork, error)
+	GetArtwork(ctx context.Context, artworkID string) (*models.NostrArtwork, error)
+	ProcessArtwork(ctx context.Context, artworkID string) error
+	ProcessArtworkAsync(ctx context.Context, artworkID string)
+}
+
 // ProcessingServiceInterface defines the interface for track processing operations
 type ProcessingServiceInterface interface {
 	ProcessTrack(ctx context.Context, trackID string) error
@@ -65,6 +109,15 @@ type ProcessingServiceInterface interface {
 	RequestCompressionVersions(ctx context.Context, trackID string, compressionOptions []models.CompressionOption) error
 	ProcessCompressionAsync(ctx context.Context, trackID string, option models.CompressionOption)
 	ProcessCompression(ctx context.Context, trackID string, option models.CompressionOption) error
+	ProcessCompressionsAsync(ctx context.Context, trackID string, options []models.CompressionOption)
+	ProcessCompressions(ctx context.Context, trackID string, options []models.CompressionOption) error
+}
+
+// FingerprintServiceInterface defines the interface for acoustic fingerprint operations
+type FingerprintServiceInterface interface {
+	SaveFingerprint(ctx context.Context, fingerprint *models.TrackFingerprint) error
+	FindMatches(ctx context.Context, fingerprint *models.TrackFingerprint, threshold float64) ([]models.FingerprintMatch, error)
+	CreateModerationEntry(ctx context.Context, entry *models.ModerationEntry) error
 }
 
 // AudioProcessorInterface defines the interface for audio processing operations
@@ -79,6 +132,7 @@ type AudioProcessorInterface interface {
 type StoragePathConfigInterface interface {
 	GetOriginalPath(trackID, extension string) string
 	GetCompressedPath(trackID string) string
+	GetDerivedFilesPrefix(trackID string) string
 }
 
 // CompressionServiceInterface defines the interface for compression version management
@@ -99,6 +153,7 @@ type FileServerServiceInterface interface {
 	ListFiles(ctx context.Context, prefix string) ([]string, error)
 	GetFileMetadata(ctx context.Context, path string) (*models.FileMetadata, error)
 	GenerateUploadToken(ctx context.Context, path, userID string, expiration time.Duration) (*models.FileUploadToken, error)
+	GenerateSignedURL(ctx context.Context, path string, opts models.SignedURLOptions) (string, error)
 }
 
 // MockStorageServiceInterface defines the interface for mock storage operations
@@ -148,4 +203,9 @@ var _ UserServiceInterface = (*UserService)(nil)
 var _ StorageServiceInterface = (*StorageService)(nil)
 var _ PostgresServiceInterface = (*PostgresService)(nil)
 var _ NostrTrackServiceInterface = (*NostrTrackService)(nil)
-var _ ProcessingServiceInterface = (*ProcessingService)(nil)
\ No newline at end of file
+var _ NostrArtistServiceInterface = (*NostrArtistService)(nil)
+var _ NostrAlbumServiceInterface = (*NostrAlbumService)(nil)
+var _ ArtworkServiceInterface = (*ArtworkService)(nil)
+var _ LegacyMigrationServiceInterface = (*LegacyMigrationService)(nil)
+var _ ProcessingServiceInterface = (*ProcessingService)(nil)
+var _ FingerprintServiceInterface = (*FingerprintService)(nil)
\ No newline at end of file
@@ -0,0 +1,306 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/google/uuid"
+	"github.com/wavlake/monorepo/internal/models"
+	"github.com/wavlake/monorepo/internal/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Per-track migration outcomes reported in models.LegacyMigrationResult
+const (
+	MigrationStatusMigrated     = "migrated"
+	MigrationStatusWouldMigrate = "would_migrate"
+	MigrationStatusSkipped      = "skipped"
+	MigrationStatusFailed       = "failed"
+)
+
+// ErrPubkeyNotLinked is returned when migrating to a pubkey that is not linked to the Firebase user
+var ErrPubkeyNotLinked = errors.New("pubkey is not linked to this user")
+
+// LegacyMigrationService copies a user's legacy catalog tracks into nostr_tracks
+type LegacyMigrationService struct {
+	firestoreClient *firestore.Client
+	postgresService PostgresServiceInterface
+	storageService  StorageServiceInterface
+	userService     UserServiceInterface
+	pathConfig      *utils.StoragePathConfig
+}
+
+func NewLegacyMigrationService(firestoreClient *firestore.Client, postgresService PostgresServiceInterface, storageService StorageServiceInterface, userService UserServiceInterface) *LegacyMigrationService {
+	return &LegacyMigrationService{
+		firestoreClient: firestoreClient,
+		postgresService: postgresService,
+		storageService:  storageService,
+		userService:     userService,
+		pathConfig:      utils.GetStoragePathConfig(),
+	}
+}
+
+// MigrateUserTracks migrates every legacy track owned by firebaseUID to new track records
+// attributed to pubkey. Tracks that were already migrated are skipped, so the migration can
+// be re-run safely. With dryRun set nothing is written and the report lists what would change.
+func (s *LegacyMigrationService) MigrateUserTracks(ctx context.Context, firebaseUID, pubkey string, dryRun bool) (*models.LegacyMigrationReport, error) {
+	linkedUID, err := s.userService.GetFirebaseUIDByPubkey(ctx, pubkey)
+	if err != nil || linkedUID != firebaseUID {
+		return nil, ErrPubkeyNotLinked
+	}
+
+	tracks, err := s.postgresService.GetUserTracks(ctx, firebaseUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get legacy tracks: %w", err)
+	}
+
+	report := &models.LegacyMigrationReport{
+		FirebaseUID: firebaseUID,
+		Pubkey:      pubkey,
+		DryRun:      dryRun,
+		Results:     []models.LegacyMigrationResult{},
+	}
+
+	for _, legacyTrack := range tracks {
+		result := s.migrateTrack(ctx, legacyTrack, firebaseUID, pubkey, dryRun)
+
+		switch result.Status {
+		case MigrationStatusMigrated, MigrationStatusWouldMigrate:
+			report.Migrated++
+		case MigrationStatusSkipped:
+			report.Skipped++
+		case MigrationStatusFailed:
+			report.Failed++
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	log.Printf("Legacy migration for %s -> %s (dry run: %t): %d migrated, %d skipped, %d failed",
+		firebaseUID, pubkey, dryRun, report.Migrated, report.Skipped, report.Failed)
+	return report, nil
+}
+
+// migrateTrack migrates a single legacy track and never returns an error; failures are
+// reported in the result so the remaining tracks are still processed
+func (s *LegacyMigrationService) migrateTrack(ctx context.Context, legacyTrack models.LegacyTrack, firebaseUID, pubkey string, dryRun bool) models.LegacyMigrationResult {
+	result := models.LegacyMigrationResult{
+		LegacyTrackID: legacyTrack.ID,
+		Title:         legacyTrack.Title,
+	}
+
+	mapping, err := s.getMapping(ctx, legacyTrack.ID)
+	if err != nil {
+		result.Status = MigrationStatusFailed
+		result.Message = err.Error()
+		return result
+	}
+	if mapping != nil {
+		result.Status = MigrationStatusSkipped
+		result.TrackID = mapping.TrackID
+		result.Message = "already migrated"
+		return result
+	}
+
+	bucket := s.storageService.GetBucketName()
+	rawObject, err := LegacyObjectName(legacyTrack.RawURL, bucket)
+	if err != nil {
+		result.Status = MigrationStatusFailed
+		result.Message = err.Error()
+		return result
+	}
+	liveObject, err := LegacyObjectName(legacyTrack.LiveURL, bucket)
+	if err != nil {
+		result.Status = MigrationStatusFailed
+		result.Message = err.Error()
+		return result
+	}
+
+	// Prefer the uploaded original; fall back to the compressed file when it is missing
+	originalObject := rawObject
+	if originalObject == "" {
+		originalObject = liveObject
+	}
+	if originalObject == "" {
+		result.Status = MigrationStatusFailed
+		result.Message = "legacy track has no audio URL"
+		return result
+	}
+
+	if dryRun {
+		result.Status = MigrationStatusWouldMigrate
+		result.Message = fmt.Sprintf("would copy %s", originalObject)
+		return result
+	}
+
+	trackID := uuid.New().String()
+	extension := strings.TrimPrefix(path.Ext(originalObject), ".")
+	if extension == "" {
+		extension = "mp3"
+	}
+
+	originalObjectName := s.pathConfig.GetOriginalPath(trackID, extension)
+	if err := s.storageService.CopyObject(ctx, originalObject, originalObjectName); err != nil {
+		result.Status = MigrationStatusFailed
+		result.Message = fmt.Sprintf("failed to copy audio: %v", err)
+		return result
+	}
+
+	now := time.Now()
+	track := &models.NostrTrack{
+		ID:                    trackID,
+		FirebaseUID:           firebaseUID,
+		Pubkey:                pubkey,
+		Title:                 legacyTrack.Title,
+		Lyrics:                legacyTrack.Lyrics,
+		IsExplicit:            legacyTrack.IsExplicit,
+		OriginalURL:           s.storageService.GetPublicURL(originalObjectName),
+		OriginalObjectKey:     originalObjectName,
+		Extension:             extension,
+		Size:                  int64(legacyTrack.Size),
+		Duration:              legacyTrack.Duration,
+		IsProcessing:          false,
+		CompressionVersions:   []models.CompressionVersion{},
+		HasPendingCompression: false,
+		Deleted:               false,
+		LegacyTrackID:         legacyTrack.ID,
+		CreatedAt:             now,
+		UpdatedAt:             now,
+	}
+
+	// The legacy live file is already a streaming MP3, so reuse it as the default version
+	if liveObject != "" {
+		compressedObjectName := s.pathConfig.GetCompressedPath(trackID)
+		if err := s.storageService.CopyObject(ctx, liveObject, compressedObjectName); err != nil {
+			log.Printf("Warning: Failed to copy live file for legacy track %s: %v", legacyTrack.ID, err)
+		} else {
+			compressedURL := s.storageService.GetPublicURL(compressedObjectName)
+			track.CompressedURL = compressedURL
+			track.IsCompressed = true
+			track.CompressionVersions = append(track.CompressionVersions, models.CompressionVersion{
+				ID:        "default-128k-mp3",
+				URL:       compressedURL,
+				Bitrate:   128,
+				Format:    "mp3",
+				Quality:   "medium",
+				IsPublic:  true,
+				CreatedAt: now,
+				ObjectKey: compressedObjectName,
+				Options: models.CompressionOption{
+					Bitrate: 128,
+					Format:  "mp3",
+					Quality: "medium",
+				},
+			})
+		}
+	}
+
+	syncVersionFields(track)
+
+	// Claim the legacy ID and create the track in one transaction. Create fails if another run
+	// already claimed the mapping, so concurrent or repeated migrations never produce a second
+	// track for the same legacy ID.
+	mapping = &models.LegacyTrackMapping{
+		LegacyTrackID: legacyTrack.ID,
+		TrackID:       trackID,
+		Pubkey:        pubkey,
+		FirebaseUID:   firebaseUID,
+		MigratedAt:    now,
+	}
+	err = s.firestoreClient.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		if err := tx.Create(s.firestoreClient.Collection("legacy_track_mappings").Doc(legacyTrack.ID), mapping); err != nil {
+			return err
+		}
+		return tx.Create(s.firestoreClient.Collection("nostr_tracks").Doc(trackID), track)
+	})
+	if err != nil {
+		s.deleteCopies(ctx, track)
+		if status.Code(err) == codes.AlreadyExists {
+			result.Status = MigrationStatusSkipped
+			result.Message = "already migrated"
+			if existing, err := s.getMapping(ctx, legacyTrack.ID); err == nil && existing != nil {
+				result.TrackID = existing.TrackID
+			}
+			return result
+		}
+		result.Status = MigrationStatusFailed
+		result.Message = fmt.Sprintf("failed to save track: %v", err)
+		return result
+	}
+
+	result.Status = MigrationStatusMigrated
+	result.TrackID = trackID
+	return result
+}
+
+// deleteCopies removes the objects copied for a track that was not saved, so a lost race or a
+// failed save does not leave orphans behind
+func (s *LegacyMigrationService) deleteCopies(ctx context.Context, track *models.NostrTrack) {
+	objects := []string{track.OriginalObjectKey}
+	for _, version := range track.CompressionVersions {
+		objects = append(objects, version.ObjectKey)
+	}
+	for _, objectName := range objects {
+		if err := s.storageService.DeleteObject(ctx, objectName); err != nil {
+			log.Printf("Warning: Failed to delete copied object %s: %v", objectName, err)
+		}
+	}
+}
+
+// getMapping returns the existing mapping for a legacy track, or nil if it was never migrated
+func (s *LegacyMigrationService) getMapping(ctx context.Context, legacyTrackID string) (*models.LegacyTrackMapping, error) {
+	doc, err := s.firestoreClient.Collection("legacy_track_mappings").Doc(legacyTrackID).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get legacy mapping: %w", err)
+	}
+
+	var mapping models.LegacyTrackMapping
+	if err := doc.DataTo(&mapping); err != nil {
+		return nil, fmt.Errorf("failed to decode legacy mapping: %w", err)
+	}
+
+	return &mapping, nil
+}
+
+// LegacyObjectName converts a legacy audio URL into an object name in bucket. GCS URLs name
+// their bucket, as the first path segment or the host's subdomain, and an error is returned when
+// it is not bucket, since objects are only copied within it. CDN URLs map their path directly.
+// URLs that are empty or name no object give "".
+func LegacyObjectName(rawURL, bucket string) (string, error) {
+	if rawURL == "" {
+		return "", nil
+	}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "", nil
+	}
+
+	objectName := strings.TrimPrefix(parsed.Path, "/")
+	urlBucket := ""
+	switch {
+	case parsed.Host == "storage.googleapis.com":
+		i := strings.Index(objectName, "/")
+		if i < 0 {
+			return "", nil
+		}
+		urlBucket, objectName = objectName[:i], objectName[i+1:]
+	case strings.HasSuffix(parsed.Host, ".storage.googleapis.com"):
+		urlBucket = strings.TrimSuffix(parsed.Host, ".storage.googleapis.com")
+	}
+
+	if urlBucket != "" && urlBucket != bucket {
+		return "", fmt.Errorf("audio %s is in bucket %s, not %s", rawURL, urlBucket, bucket)
+	}
+	return objectName, nil
+}
@@ -0,0 +1,44 @@
+package services_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/wavlake/monorepo/internal/services"
+)
+
+var _ = Describe("Legacy migration", func() {
+	Describe("LegacyObjectName", func() {
+		It("should strip the bucket from GCS URLs in the configured bucket", func() {
+			objectName, err := services.LegacyObjectName("https://storage.googleapis.com/wavlake-audio/raw/track.wav", "wavlake-audio")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(objectName).To(Equal("raw/track.wav"))
+
+			objectName, err = services.LegacyObjectName("https://wavlake-audio.storage.googleapis.com/raw/track.wav", "wavlake-audio")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(objectName).To(Equal("raw/track.wav"))
+		})
+
+		It("should reject GCS URLs in another bucket", func() {
+			_, err := services.LegacyObjectName("https://storage.googleapis.com/other-bucket/raw/track.wav", "wavlake-audio")
+			Expect(err).To(MatchError(ContainSubstring("other-bucket")))
+
+			_, err = services.LegacyObjectName("https://other-bucket.storage.googleapis.com/raw/track.wav", "wavlake-audio")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should map CDN URLs by path", func() {
+			objectName, err := services.LegacyObjectName("https://cdn.wavlake.com/track.mp3", "wavlake-audio")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(objectName).To(Equal("track.mp3"))
+		})
+
+		It("should return nothing for URLs without an object", func() {
+			for _, rawURL := range []string{"", "https://storage.googleapis.com/wavlake-audio"} {
+				objectName, err := services.LegacyObjectName(rawURL, "wavlake-audio")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(objectName).To(BeEmpty())
+			}
+		})
+	})
+})
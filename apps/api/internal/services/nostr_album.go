@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/google/uuid"
+	"github.com/wavlake/monorepo/internal/models"
+	"google.golang.org/api/iterator"
+)
+
+// ErrInvalidAlbumTrack is returned when an album references a track the owner cannot use
+var ErrInvalidAlbumTrack = errors.New("invalid album track")
+
+type NostrAlbumService struct {
+	firestoreClient   *firestore.Client
+	nostrTrackService NostrTrackServiceInterface
+}
+
+func NewNostrAlbumService(firestoreClient *firestore.Client, nostrTrackService NostrTrackServiceInterface) *NostrAlbumService {
+	return &NostrAlbumService{
+		firestoreClient:   firestoreClient,
+		nostrTrackService: nostrTrackService,
+	}
+}
+
+// CreateAlbum validates the album's tracks and saves it as a new draft
+func (s *NostrAlbumService) CreateAlbum(ctx context.Context, album *models.NostrAlbum) error {
+	if album.TrackIDs == nil {
+		album.TrackIDs = []string{}
+	}
+	if err := s.validateTrackIDs(ctx, album.Pubkey, album.TrackIDs); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	album.ID = uuid.New().String()
+	album.IsDraft = true
+	album.Deleted = false
+	album.CreatedAt = now
+	album.UpdatedAt = now
+
+	_, err := s.firestoreClient.Collection("nostr_albums").Doc(album.ID).Set(ctx, album)
+	if err != nil {
+		return fmt.Errorf("failed to save album to firestore: %w", err)
+	}
+
+	log.Printf("Created new album with ID: %s for pubkey: %s", album.ID, album.Pubkey)
+	return nil
+}
+
+// GetAlbum retrieves an album by ID
+func (s *NostrAlbumService) GetAlbum(ctx context.Context, albumID string) (*models.NostrAlbum, error) {
+	doc, err := s.firestoreClient.Collection("nostr_albums").Doc(albumID).Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get album: %w", err)
+	}
+
+	var album models.NostrAlbum
+	if err := doc.DataTo(&album); err != nil {
+		return nil, fmt.Errorf("failed to decode album: %w", err)
+	}
+
+	return &album, nil
+}
+
+// GetAlbumsByPubkey retrieves all non-deleted albums for a given pubkey, drafts included
+func (s *NostrAlbumService) GetAlbumsByPubkey(ctx context.Context, pubkey string) ([]*models.NostrAlbum, error) {
+	query := s.firestoreClient.Collection("nostr_albums").
+		Where("pubkey", "==", pubkey).
+		Where("deleted", "==", false).
+		OrderBy("created_at", firestore.Desc)
+
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	var albums []*models.NostrAlbum
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate albums: %w", err)
+		}
+
+		var album models.NostrAlbum
+		if err := doc.DataTo(&album); err != nil {
+			log.Printf("Failed to decode album %s: %v", doc.Ref.ID, err)
+			continue
+		}
+
+		albums = append(albums, &album)
+	}
+
+	return albums, nil
+}
+
+// UpdateAlbum updates album metadata
+func (s *NostrAlbumService) UpdateAlbum(ctx context.Context, albumID string, updates map[string]interface{}) error {
+	updates["updated_at"] = time.Now()
+
+	var updatePaths []firestore.Update
+	for path, value := range updates {
+		updatePaths = append(updatePaths, firestore.Update{Path: path, Value: value})
+	}
+
+	_, err := s.firestoreClient.Collection("nostr_albums").Doc(albumID).Update(ctx, updatePaths)
+	if err != nil {
+		return fmt.Errorf("failed to update album: %w", err)
+	}
+
+	return nil
+}
+
+// SetAlbumTracks replaces the album's track list, preserving the given order
+func (s *NostrAlbumService) SetAlbumTracks(ctx context.Context, albumID string, trackIDs []string) error {
+	album, err := s.GetAlbum(ctx, albumID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.validateTrackIDs(ctx, album.Pubkey, trackIDs); err != nil {
+		return err
+	}
+
+	updates := map[string]interface{}{
+		"track_ids": trackIDs,
+	}
+
+	return s.UpdateAlbum(ctx, albumID, updates)
+}
+
+// DeleteAlbum soft deletes an album; its tracks are left untouched
+func (s *NostrAlbumService) DeleteAlbum(ctx context.Context, albumID string) error {
+	updates := map[string]interface{}{
+		"deleted": true,
+	}
+
+	return s.UpdateAlbum(ctx, albumID, updates)
+}
+
+// validateTrackIDs checks that every track exists, is owned by pubkey, is not deleted
+// and appears only once
+func (s *NostrAlbumService) validateTrackIDs(ctx context.Context, pubkey string, trackIDs []string) error {
+	seen := make(map[string]bool, len(trackIDs))
+	for _, trackID := range trackIDs {
+		if seen[trackID] {
+			return fmt.Errorf("%w: track %s is listed more than once", ErrInvalidAlbumTrack, trackID)
+		}
+		seen[trackID] = true
+
+		track, err := s.nostrTrackService.GetTrack(ctx, trackID)
+		if err != nil {
+			return fmt.Errorf("%w: track %s not found", ErrInvalidAlbumTrack, trackID)
+		}
+		if track.Pubkey != pubkey {
+			return fmt.Errorf("%w: track %s is not owned by this pubkey", ErrInvalidAlbumTrack, trackID)
+		}
+		if track.Deleted {
+			return fmt.Errorf("%w: track %s has been deleted", ErrInvalidAlbumTrack, trackID)
+		}
+	}
+
+	return nil
+}
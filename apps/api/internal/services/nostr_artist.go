@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/wavlake/monorepo/internal/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrArtistAlreadyExists is returned when a pubkey already has an artist profile
+var ErrArtistAlreadyExists = errors.New("artist profile already exists")
+
+type NostrArtistService struct {
+	firestoreClient *firestore.Client
+}
+
+func NewNostrArtistService(firestoreClient *firestore.Client) *NostrArtistService {
+	return &NostrArtistService{
+		firestoreClient: firestoreClient,
+	}
+}
+
+// CreateArtist creates the artist profile for the artist's pubkey. A soft-deleted profile is
+// replaced, so an artist can recreate a profile they deleted.
+func (s *NostrArtistService) CreateArtist(ctx context.Context, artist *models.NostrArtist) error {
+	now := time.Now()
+	artist.Deleted = false
+	artist.CreatedAt = now
+	artist.UpdatedAt = now
+
+	ref := s.firestoreClient.Collection("nostr_artists").Doc(artist.Pubkey)
+	err := s.firestoreClient.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(ref)
+		if status.Code(err) == codes.NotFound {
+			return tx.Create(ref, artist)
+		}
+		if err != nil {
+			return err
+		}
+
+		var existing models.NostrArtist
+		if err := doc.DataTo(&existing); err != nil {
+			return fmt.Errorf("failed to decode artist: %w", err)
+		}
+		if !existing.Deleted {
+			return ErrArtistAlreadyExists
+		}
+		return tx.Set(ref, artist)
+	})
+	if err != nil {
+		if errors.Is(err, ErrArtistAlreadyExists) || status.Code(err) == codes.AlreadyExists {
+			return ErrArtistAlreadyExists
+		}
+		return fmt.Errorf("failed to save artist to firestore: %w", err)
+	}
+
+	log.Printf("Created artist profile for pubkey: %s", artist.Pubkey)
+	return nil
+}
+
+// GetArtist retrieves an artist profile by pubkey
+func (s *NostrArtistService) GetArtist(ctx context.Context, pubkey string) (*models.NostrArtist, error) {
+	doc, err := s.firestoreClient.Collection("nostr_artists").Doc(pubkey).Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get artist: %w", err)
+	}
+
+	var artist models.NostrArtist
+	if err := doc.DataTo(&artist); err != nil {
+		return nil, fmt.Errorf("failed to decode artist: %w", err)
+	}
+
+	return &artist, nil
+}
+
+// UpdateArtist updates artist profile fields
+func (s *NostrArtistService) UpdateArtist(ctx context.Context, pubkey string, updates map[string]interface{}) error {
+	updates["updated_at"] = time.Now()
+
+	var updatePaths []firestore.Update
+	for path, value := range updates {
+		updatePaths = append(updatePaths, firestore.Update{Path: path, Value: value})
+	}
+
+	_, err := s.firestoreClient.Collection("nostr_artists").Doc(pubkey).Update(ctx, updatePaths)
+	if err != nil {
+		return fmt.Errorf("failed to update artist: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteArtist soft deletes an artist profile
+func (s *NostrArtistService) DeleteArtist(ctx context.Context, pubkey string) error {
+	updates := map[string]interface{}{
+		"deleted": true,
+	}
+
+	return s.UpdateArtist(ctx, pubkey, updates)
+}
@@ -2,27 +2,65 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"slices"
+	"strings"
 	"time"
 
 	"cloud.google.com/go/firestore"
 	"github.com/google/uuid"
+	"github.com/wavlake/monorepo/internal/config"
 	"github.com/wavlake/monorepo/internal/models"
+	"github.com/wavlake/monorepo/internal/utils"
 	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type NostrTrackService struct {
 	firestoreClient *firestore.Client
 	storageService  StorageServiceInterface
 	pathConfig      StoragePathConfigInterface
+	uploadPolicy    *config.UploadPolicy
+	httpClient      *http.Client
 }
 
-func NewNostrTrackService(firestoreClient *firestore.Client, storageService StorageServiceInterface, pathConfig StoragePathConfigInterface) *NostrTrackService {
+// UploadURLExpiry is how long a one-shot upload URL for a track original is valid
+const UploadURLExpiry = time.Hour
+
+// ResumableUploadTTL is how long storage keeps a resumable upload session open
+const ResumableUploadTTL = 7 * 24 * time.Hour
+
+// resumableStartExpiry is how long the signed URL that opens a resumable session is valid;
+// the API uses it immediately
+const resumableStartExpiry = 10 * time.Minute
+
+// ErrUploadTooLarge is returned when a declared upload size exceeds the upload policy
+var ErrUploadTooLarge = errors.New("upload exceeds the maximum size")
+
+// ErrIncompleteDelete is returned when a delete could not remove every stored file
+var ErrIncompleteDelete = errors.New("some files could not be deleted")
+
+// ErrUploadComplete is returned when a new upload URL is requested for a track whose original
+// is already stored
+var ErrUploadComplete = errors.New("track original already uploaded")
+
+// ErrVersionNotFound is returned when a track has no compression version with the given ID
+var ErrVersionNotFound = errors.New("compression version not found")
+
+func NewNostrTrackService(firestoreClient *firestore.Client, storageService StorageServiceInterface, pathConfig StoragePathConfigInterface, uploadPolicy *config.UploadPolicy) *NostrTrackService {
+	if uploadPolicy == nil {
+		uploadPolicy = config.NewUploadPolicy()
+	}
 	return &NostrTrackService{
 		firestoreClient: firestoreClient,
 		storageService:  storageService,
 		pathConfig:      pathConfig,
+		uploadPolicy:    uploadPolicy,
+		httpClient:      &http.Client{Timeout: 30 * time.Second},
 	}
 }
 
@@ -34,19 +72,153 @@ func (s *NostrTrackService) CreateTrack(ctx context.Context, pubkey, firebaseUID
 	// Generate storage object names using path configuration
 	originalObjectName := s.pathConfig.GetOriginalPath(trackID, extension)
 
-	// Generate presigned URL for upload (valid for 1 hour)
-	presignedURL, err := s.storageService.GeneratePresignedURL(ctx, originalObjectName, time.Hour)
+	// Create the track record with a presigned upload URL
+	track := s.newTrack(trackID, pubkey, firebaseUID, extension, originalObjectName, now)
+	if err := s.signUploadURL(ctx, track, originalObjectName, now); err != nil {
+		return nil, err
+	}
+
+	if err := s.saveNewTrack(ctx, track); err != nil {
+		return nil, err
+	}
+	return track, nil
+}
+
+// CreateResumableTrack creates a new NostrTrack record and opens a resumable upload session
+// for its original. size is the declared upload size in bytes, 0 when unknown.
+func (s *NostrTrackService) CreateResumableTrack(ctx context.Context, pubkey, firebaseUID, extension string, size int64) (*models.NostrTrack, error) {
+	if size > s.uploadPolicy.MaxBytes {
+		return nil, fmt.Errorf("%w: %d bytes, limit is %d", ErrUploadTooLarge, size, s.uploadPolicy.MaxBytes)
+	}
+
+	trackID := uuid.New().String()
+	now := time.Now()
+	originalObjectName := s.pathConfig.GetOriginalPath(trackID, extension)
+
+	session, err := s.startUploadSession(ctx, originalObjectName, extension, size)
+	if err != nil {
+		return nil, err
+	}
+
+	track := s.newTrack(trackID, pubkey, firebaseUID, extension, originalObjectName, now)
+	track.UploadSession = session
+
+	if err := s.saveNewTrack(ctx, track); err != nil {
+		return nil, err
+	}
+	return track, nil
+}
+
+// signUploadURL sets a presigned PUT URL for the track's original, valid for UploadURLExpiry
+// and limited to the policy's max size
+func (s *NostrTrackService) signUploadURL(ctx context.Context, track *models.NostrTrack, objectName string, now time.Time) error {
+	presignedURL, err := s.storageService.GenerateSignedURL(ctx, objectName, models.SignedURLOptions{
+		Method:           http.MethodPut,
+		Expiration:       UploadURLExpiry,
+		MaxContentLength: s.uploadPolicy.MaxBytes,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to generate presigned URL: %w", err)
+	}
+
+	expiresAt := now.Add(UploadURLExpiry)
+	track.PresignedURL = presignedURL
+	track.UploadHeaders = map[string]string{ContentLengthRangeHeader: ContentLengthRangeValue(s.uploadPolicy.MaxBytes)}
+	track.UploadExpiresAt = &expiresAt
+	return nil
+}
+
+// RenewUpload issues a fresh upload URL for a track whose original has not arrived, e.g. after
+// the reaper marked it UploadStateExpired. Tracks created with a resumable session get a new
+// session; the others get a new one-shot URL. The track returns to UploadStatePending.
+func (s *NostrTrackService) RenewUpload(ctx context.Context, trackID string) (*models.NostrTrack, error) {
+	track, err := s.GetTrack(ctx, trackID)
+	if err != nil {
+		return nil, err
+	}
+	if track.UploadState == models.UploadStateComplete || (!track.IsProcessing && track.UploadState != models.UploadStateExpired) {
+		return nil, ErrUploadComplete
+	}
+
+	objectName := OriginalObjectKey(s.storageService, track)
+	if objectName == "" {
+		objectName = s.pathConfig.GetOriginalPath(track.ID, track.Extension)
+	}
+	if _, err := s.storageService.GetObjectInfo(ctx, objectName); err == nil {
+		return nil, ErrUploadComplete
+	}
+
+	now := time.Now()
+	updates := map[string]interface{}{
+		"upload_state":        models.UploadStatePending,
+		"is_processing":       true,
+		"original_object_key": objectName,
+	}
+	if track.UploadSession != nil {
+		session, err := s.startUploadSession(ctx, objectName, track.Extension, track.UploadSession.Size)
+		if err != nil {
+			return nil, err
+		}
+		track.UploadSession = session
+		updates["upload_session"] = session
+	} else {
+		if err := s.signUploadURL(ctx, track, objectName, now); err != nil {
+			return nil, err
+		}
+		updates["upload_expires_at"] = *track.UploadExpiresAt
+	}
+
+	if err := s.UpdateTrack(ctx, trackID, updates); err != nil {
+		return nil, err
+	}
+
+	track.UploadState = models.UploadStatePending
+	track.IsProcessing = true
+	track.OriginalObjectKey = objectName
+	track.UpdatedAt = now
+	log.Printf("Renewed upload of track %s", trackID)
+	return track, nil
+}
+
+// startUploadSession opens a resumable upload session for an original, limited to the
+// policy's max size
+func (s *NostrTrackService) startUploadSession(ctx context.Context, objectName, extension string, size int64) (*models.UploadSession, error) {
+	contentType := utils.ContentTypeForFormat(extension)
+	startURL, err := s.storageService.GenerateSignedURL(ctx, objectName, models.SignedURLOptions{
+		Resumable:        true,
+		Expiration:       resumableStartExpiry,
+		MaxContentLength: s.uploadPolicy.MaxBytes,
+		ContentType:      contentType,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate upload session URL: %w", err)
+	}
+
+	sessionURL, err := utils.StartResumableUpload(ctx, s.httpClient, startURL, map[string]string{
+		"Content-Type":           contentType,
+		ContentLengthRangeHeader: ContentLengthRangeValue(s.uploadPolicy.MaxBytes),
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate presigned URL: %w", err)
+		return nil, err
 	}
 
-	// Create the track record
-	track := &models.NostrTrack{
+	return &models.UploadSession{
+		URL:         sessionURL,
+		ContentType: contentType,
+		Size:        size,
+		ExpiresAt:   time.Now().Add(ResumableUploadTTL),
+	}, nil
+}
+
+// newTrack builds the record of a track whose original has not been uploaded yet
+func (s *NostrTrackService) newTrack(trackID, pubkey, firebaseUID, extension, originalObjectName string, now time.Time) *models.NostrTrack {
+	return &models.NostrTrack{
 		ID:                    trackID,
 		FirebaseUID:           firebaseUID,
 		Pubkey:                pubkey,
 		OriginalURL:           s.storageService.GetPublicURL(originalObjectName),
-		PresignedURL:          presignedURL,
+		OriginalObjectKey:     originalObjectName,
+		UploadState:           models.UploadStatePending,
 		Extension:             extension,
 		IsProcessing:          true,
 		IsCompressed:          false,
@@ -56,14 +228,55 @@ func (s *NostrTrackService) CreateTrack(ctx context.Context, pubkey, firebaseUID
 		CreatedAt:             now,
 		UpdatedAt:             now,
 	}
+}
 
-	// Save to Firestore
-	_, err = s.firestoreClient.Collection("nostr_tracks").Doc(trackID).Set(ctx, track)
+// saveNewTrack writes a newly created track to Firestore
+func (s *NostrTrackService) saveNewTrack(ctx context.Context, track *models.NostrTrack) error {
+	syncVersionFields(track)
+	if _, err := s.firestoreClient.Collection("nostr_tracks").Doc(track.ID).Set(ctx, track); err != nil {
+		return fmt.Errorf("failed to save track to firestore: %w", err)
+	}
+
+	log.Printf("Created new Nostr track with ID: %s for pubkey: %s", track.ID, track.Pubkey)
+	return nil
+}
+
+// RefreshUploadStatus asks storage how much of a resumable upload has been committed and
+// records it on the track. Tracks without an open session are returned unchanged.
+func (s *NostrTrackService) RefreshUploadStatus(ctx context.Context, trackID string) (*models.NostrTrack, error) {
+	track, err := s.GetTrack(ctx, trackID)
+	if err != nil {
+		return nil, err
+	}
+	if track.UploadSession == nil || track.UploadState == models.UploadStateComplete {
+		return track, nil
+	}
+
+	offset, complete, err := utils.QueryResumableUpload(ctx, s.httpClient, track.UploadSession.URL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to save track to firestore: %w", err)
+		return nil, fmt.Errorf("failed to query upload session: %w", err)
+	}
+
+	state := models.UploadStatePending
+	switch {
+	case complete:
+		state = models.UploadStateComplete
+		offset = max(track.UploadSession.Size, track.UploadSession.Offset)
+	case offset > 0:
+		state = models.UploadStateUploading
+	}
+
+	if state != track.UploadState || offset != track.UploadSession.Offset {
+		if err := s.UpdateTrack(ctx, trackID, map[string]interface{}{
+			"upload_state":          state,
+			"upload_session.offset": offset,
+		}); err != nil {
+			return nil, err
+		}
 	}
 
-	log.Printf("Created new Nostr track with ID: %s for pubkey: %s", trackID, pubkey)
+	track.UploadState = state
+	track.UploadSession.Offset = offset
 	return track, nil
 }
 
@@ -82,6 +295,36 @@ func (s *NostrTrackService) GetTrack(ctx context.Context, trackID string) (*mode
 	return &track, nil
 }
 
+// FindTracksByFileHash returns the non-deleted tracks whose original has the given SHA-256
+func (s *NostrTrackService) FindTracksByFileHash(ctx context.Context, fileHash string) ([]*models.NostrTrack, error) {
+	iter := s.firestoreClient.Collection("nostr_tracks").
+		Where("file_hash", "==", fileHash).
+		Where("deleted", "==", false).
+		Documents(ctx)
+	defer iter.Stop()
+
+	var tracks []*models.NostrTrack
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate tracks: %w", err)
+		}
+
+		var track models.NostrTrack
+		if err := doc.DataTo(&track); err != nil {
+			log.Printf("Failed to decode track %s: %v", doc.Ref.ID, err)
+			continue
+		}
+
+		tracks = append(tracks, &track)
+	}
+
+	return tracks, nil
+}
+
 // GetTracksByPubkey retrieves all tracks for a given pubkey
 func (s *NostrTrackService) GetTracksByPubkey(ctx context.Context, pubkey string) ([]*models.NostrTrack, error) {
 	query := s.firestoreClient.Collection("nostr_tracks").
@@ -146,6 +389,95 @@ func (s *NostrTrackService) GetTracksByFirebaseUID(ctx context.Context, firebase
 	return tracks, nil
 }
 
+// ListTracksByPubkey returns one page of a pubkey's tracks, sorted and filtered by the given options.
+// Every filter is applied in the query, using the fields syncVersionFields keeps on the track for
+// version visibility and format.
+func (s *NostrTrackService) ListTracksByPubkey(ctx context.Context, pubkey string, opts models.ListOptions, filter models.TrackFilter) (*models.NostrTrackPage, error) {
+	sortField, sortOrder := utils.NormalizeSort(opts.SortBy, opts.SortOrder)
+	if sortField != "created_at" && sortField != "updated_at" {
+		return nil, fmt.Errorf("unsupported sort field: %s", sortField)
+	}
+
+	direction := firestore.Desc
+	if sortOrder == "asc" {
+		direction = firestore.Asc
+	}
+
+	deleted := false
+	if filter.Deleted != nil {
+		deleted = *filter.Deleted
+	}
+
+	query := s.firestoreClient.Collection("nostr_tracks").
+		Where("pubkey", "==", pubkey).
+		Where("deleted", "==", deleted)
+	if filter.IsProcessing != nil {
+		query = query.Where("is_processing", "==", *filter.IsProcessing)
+	}
+	if filter.HasPublicVersions != nil {
+		query = query.Where("has_public_versions", "==", *filter.HasPublicVersions)
+	}
+	if filter.Format != "" {
+		query = query.Where("formats", "array-contains", strings.ToLower(strings.TrimPrefix(filter.Format, ".")))
+	}
+	query = query.OrderBy(sortField, direction).OrderBy(firestore.DocumentID, direction)
+
+	if opts.Cursor != "" {
+		cursor, err := utils.DecodeCursor(opts.Cursor, sortField, sortOrder)
+		if err != nil {
+			return nil, err
+		}
+		cursorTime, err := time.Parse(time.RFC3339Nano, cursor.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor: %w", err)
+		}
+		query = query.StartAfter(cursorTime, cursor.ID)
+	}
+
+	// One extra document is enough to detect another page
+	limit := utils.NormalizePageLimit(opts.Limit)
+	query = query.Limit(limit + 1)
+
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	tracks := []*models.NostrTrack{}
+	hasMore := false
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate tracks: %w", err)
+		}
+
+		var track models.NostrTrack
+		if err := doc.DataTo(&track); err != nil {
+			log.Printf("Failed to decode track %s: %v", doc.Ref.ID, err)
+			continue
+		}
+
+		if len(tracks) == limit {
+			hasMore = true
+			break
+		}
+		tracks = append(tracks, &track)
+	}
+
+	page := &models.NostrTrackPage{Tracks: tracks}
+	if hasMore {
+		last := tracks[len(tracks)-1]
+		sortValue := last.CreatedAt
+		if sortField == "updated_at" {
+			sortValue = last.UpdatedAt
+		}
+		page.NextCursor = utils.EncodeCursor(sortField, sortOrder, sortValue.Format(time.RFC3339Nano), last.ID)
+	}
+
+	return page, nil
+}
+
 // UpdateTrack updates track metadata
 func (s *NostrTrackService) UpdateTrack(ctx context.Context, trackID string, updates map[string]interface{}) error {
 	updates["updated_at"] = time.Now()
@@ -188,42 +520,198 @@ func (s *NostrTrackService) MarkTrackAsCompressed(ctx context.Context, trackID,
 
 // DeleteTrack soft deletes a track
 func (s *NostrTrackService) DeleteTrack(ctx context.Context, trackID string) error {
+	now := time.Now()
 	updates := map[string]interface{}{
 		"deleted":    true,
-		"updated_at": time.Now(),
+		"deleted_at": now,
+		"updated_at": now,
 	}
 
 	return s.UpdateTrack(ctx, trackID, updates)
 }
 
-// HardDeleteTrack permanently deletes a track and its files
+// HardDeleteTrack permanently deletes a track with every file stored for it: the original,
+// all compression versions and HLS segments, waveforms and artwork. The record is only removed
+// once every file is gone, and deleting a missing file succeeds, so a partially failed delete
+// can be re-run. Partial failures wrap ErrIncompleteDelete and list the files left behind.
 func (s *NostrTrackService) HardDeleteTrack(ctx context.Context, trackID string) error {
-	// Get track first to know which files to delete
-	track, err := s.GetTrack(ctx, trackID)
+	doc, err := s.firestoreClient.Collection("nostr_tracks").Doc(trackID).Get(ctx)
 	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			log.Printf("Track %s already hard deleted", trackID)
+			return nil
+		}
 		return fmt.Errorf("failed to get track for deletion: %w", err)
 	}
 
-	// Delete files from storage using path configuration
-	originalObjectName := s.pathConfig.GetOriginalPath(trackID, track.Extension)
-	if err := s.storageService.DeleteObject(ctx, originalObjectName); err != nil {
-		log.Printf("Failed to delete original file for track %s: %v", trackID, err)
+	var track models.NostrTrack
+	if err := doc.DataTo(&track); err != nil {
+		return fmt.Errorf("failed to decode track: %w", err)
+	}
+	track.ID = trackID
+
+	failed, err := s.hardDelete(ctx, &track)
+	if err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%w: %s", ErrIncompleteDelete, strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// hardDelete deletes a track's files, artwork and fingerprint, then the track record. It
+// returns the objects and prefixes that could not be deleted, in which case the record is kept.
+func (s *NostrTrackService) hardDelete(ctx context.Context, track *models.NostrTrack) ([]string, error) {
+	objects, prefixes := TrackObjects(s.storageService, s.pathConfig, track)
+	failed := s.deleteObjects(ctx, objects, prefixes)
+	failed = append(failed, s.deleteTrackArtwork(ctx, track.ID)...)
+
+	if len(failed) > 0 {
+		log.Printf("Hard delete of track %s left %d files behind: %s", track.ID, len(failed), strings.Join(failed, ", "))
+		return failed, nil
+	}
+
+	if _, err := s.firestoreClient.Collection("track_fingerprints").Doc(track.ID).Delete(ctx); err != nil {
+		return nil, fmt.Errorf("failed to delete track fingerprint: %w", err)
+	}
+
+	if _, err := s.firestoreClient.Collection("nostr_tracks").Doc(track.ID).Delete(ctx); err != nil {
+		return nil, fmt.Errorf("failed to delete track from firestore: %w", err)
 	}
 
-	if track.CompressedURL != "" {
-		compressedObjectName := s.pathConfig.GetCompressedPath(trackID)
-		if err := s.storageService.DeleteObject(ctx, compressedObjectName); err != nil {
-			log.Printf("Failed to delete compressed file for track %s: %v", trackID, err)
+	log.Printf("Hard deleted track %s", track.ID)
+	return nil, nil
+}
+
+// deleteTrackArtwork deletes the artwork uploaded for a track: each upload's original and
+// variants, then its record. It returns what could not be deleted.
+func (s *NostrTrackService) deleteTrackArtwork(ctx context.Context, trackID string) []string {
+	iter := s.firestoreClient.Collection("nostr_artwork").
+		Where("target_type", "==", ArtworkTargetTrack).
+		Where("target_id", "==", trackID).
+		Documents(ctx)
+	defer iter.Stop()
+
+	var failed []string
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			log.Printf("Failed to list artwork of track %s: %v", trackID, err)
+			return append(failed, "artwork of track "+trackID)
+		}
+
+		var artwork models.NostrArtwork
+		if err := doc.DataTo(&artwork); err != nil {
+			log.Printf("Failed to decode artwork %s: %v", doc.Ref.ID, err)
+			failed = append(failed, "artwork "+doc.Ref.ID)
+			continue
+		}
+
+		objects := []string{objectKeyFromURL(s.storageService, artwork.OriginalURL)}
+		for _, variant := range artwork.Variants {
+			objects = append(objects, objectKeyFromURL(s.storageService, variant.URL))
+		}
+		artworkFailed := s.deleteObjects(ctx, objects, nil)
+		if len(artworkFailed) > 0 {
+			failed = append(failed, artworkFailed...)
+			continue
+		}
+
+		if _, err := doc.Ref.Delete(ctx); err != nil {
+			log.Printf("Failed to delete artwork record %s: %v", doc.Ref.ID, err)
+			failed = append(failed, "artwork "+doc.Ref.ID)
+		}
+	}
+	return failed
+}
+
+// deleteObjects deletes the given objects and everything under the given prefixes, returning
+// the objects and prefixes that could not be deleted
+func (s *NostrTrackService) deleteObjects(ctx context.Context, objects, prefixes []string) []string {
+	deleted := make(map[string]bool)
+	var failed []string
+	deleteObject := func(objectName string) {
+		if objectName == "" || deleted[objectName] {
+			return
+		}
+		deleted[objectName] = true
+		if err := s.storageService.DeleteObject(ctx, objectName); err != nil {
+			log.Printf("Failed to delete %s: %v", objectName, err)
+			failed = append(failed, objectName)
 		}
 	}
 
-	// Delete from Firestore
-	_, err = s.firestoreClient.Collection("nostr_tracks").Doc(trackID).Delete(ctx)
+	for _, objectName := range objects {
+		deleteObject(objectName)
+	}
+	for _, prefix := range prefixes {
+		names, err := s.storageService.ListObjects(ctx, prefix)
+		if err != nil {
+			log.Printf("Failed to list objects under %s: %v", prefix, err)
+			failed = append(failed, prefix)
+			continue
+		}
+		for _, objectName := range names {
+			deleteObject(objectName)
+		}
+	}
+	return failed
+}
+
+// DeleteCompressionVersion deletes a compression version's file and removes it from the track
+func (s *NostrTrackService) DeleteCompressionVersion(ctx context.Context, trackID, versionID string) error {
+	track, err := s.GetTrack(ctx, trackID)
 	if err != nil {
-		return fmt.Errorf("failed to delete track from firestore: %w", err)
+		return fmt.Errorf("failed to get track: %w", err)
+	}
+
+	index := slices.IndexFunc(track.CompressionVersions, func(version models.CompressionVersion) bool {
+		return version.ID == versionID
+	})
+	if index < 0 {
+		return fmt.Errorf("%w: %s", ErrVersionNotFound, versionID)
+	}
+	version := track.CompressionVersions[index]
+
+	// Keep the record if the file cannot be deleted, so the delete can be retried
+	if err := s.deleteVersionObject(ctx, &version); err != nil {
+		return fmt.Errorf("failed to delete compression version file: %w", err)
 	}
 
-	log.Printf("Hard deleted track %s", trackID)
+	track.CompressionVersions = slices.Delete(track.CompressionVersions, index, index+1)
+	if track.CompressedURL == version.URL {
+		track.CompressedURL = ""
+	}
+	track.UpdatedAt = time.Now()
+	syncVersionFields(track)
+
+	if _, err := s.firestoreClient.Collection("nostr_tracks").Doc(trackID).Set(ctx, track); err != nil {
+		return fmt.Errorf("failed to update track: %w", err)
+	}
+
+	log.Printf("Deleted compression version %s of track %s", versionID, trackID)
+	return nil
+}
+
+// deleteVersionObject deletes the stored files of a compression version. For HLS this is the
+// master playlist and every segment of the package.
+func (s *NostrTrackService) deleteVersionObject(ctx context.Context, version *models.CompressionVersion) error {
+	objectName := VersionObjectKey(s.storageService, version)
+	if objectName == "" {
+		return fmt.Errorf("no storage object recorded for version %s", version.ID)
+	}
+
+	var prefixes []string
+	if prefix := VersionObjectPrefix(s.storageService, version); prefix != "" {
+		prefixes = append(prefixes, prefix)
+	}
+	if failed := s.deleteObjects(ctx, []string{objectName}, prefixes); len(failed) > 0 {
+		return fmt.Errorf("%w: %s", ErrIncompleteDelete, strings.Join(failed, ", "))
+	}
 	return nil
 }
 
@@ -246,6 +734,7 @@ func (s *NostrTrackService) UpdateCompressionVisibility(ctx context.Context, tra
 	}
 
 	// Save updated track
+	syncVersionFields(track)
 	_, err = s.firestoreClient.Collection("nostr_tracks").Doc(trackID).Set(ctx, track)
 	if err != nil {
 		return fmt.Errorf("failed to update track: %w", err)
@@ -271,6 +760,7 @@ func (s *NostrTrackService) AddCompressionVersion(ctx context.Context, trackID s
 			log.Printf("Updated existing compression version %s for track %s", version.ID, trackID)
 
 			// Save updated track
+			syncVersionFields(track)
 			_, err = s.firestoreClient.Collection("nostr_tracks").Doc(trackID).Set(ctx, track)
 			return err
 		}
@@ -279,6 +769,7 @@ func (s *NostrTrackService) AddCompressionVersion(ctx context.Context, trackID s
 	// Add new version
 	track.CompressionVersions = append(track.CompressionVersions, version)
 	track.HasPendingCompression = false // Clear pending flag
+	syncVersionFields(track)
 
 	// Save updated track
 	_, err = s.firestoreClient.Collection("nostr_tracks").Doc(trackID).Set(ctx, track)
@@ -290,6 +781,27 @@ func (s *NostrTrackService) AddCompressionVersion(ctx context.Context, trackID s
 	return nil
 }
 
+// syncVersionFields recomputes the fields derived from a track's compression versions. They are
+// stored so list queries can filter on them; call it before saving changed versions.
+func syncVersionFields(track *models.NostrTrack) {
+	track.HasPublicVersions = slices.ContainsFunc(track.CompressionVersions, func(version models.CompressionVersion) bool {
+		return version.IsPublic
+	})
+
+	formats := []string{}
+	addFormat := func(format string) {
+		format = strings.ToLower(format)
+		if format != "" && !slices.Contains(formats, format) {
+			formats = append(formats, format)
+		}
+	}
+	addFormat(track.Extension)
+	for _, version := range track.CompressionVersions {
+		addFormat(version.Format)
+	}
+	track.Formats = formats
+}
+
 // SetPendingCompression marks a track as having pending compression requests
 func (s *NostrTrackService) SetPendingCompression(ctx context.Context, trackID string, pending bool) error {
 	updates := []firestore.Update{
@@ -0,0 +1,260 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path"
+	"slices"
+	"strings"
+
+	"cloud.google.com/go/firestore"
+	"github.com/wavlake/monorepo/internal/models"
+	"github.com/wavlake/monorepo/internal/utils"
+	"google.golang.org/api/iterator"
+)
+
+// Per-track backfill outcomes reported in models.ObjectKeyBackfillResult
+const (
+	BackfillStatusUpdated     = "updated"
+	BackfillStatusWouldUpdate = "would_update"
+	BackfillStatusSkipped     = "skipped"
+	BackfillStatusFailed      = "failed"
+)
+
+// OriginalObjectKey returns the storage object of a track's original. Tracks created before
+// keys were stored fall back to the object their URL points at; "" when neither is known.
+func OriginalObjectKey(storageService StorageServiceInterface, track *models.NostrTrack) string {
+	if track.OriginalObjectKey != "" {
+		return track.OriginalObjectKey
+	}
+	return objectKeyFromURL(storageService, track.OriginalURL)
+}
+
+// VersionObjectKey returns the storage object of a compression version, the master playlist
+// for HLS, falling back to the object its URL points at like OriginalObjectKey
+func VersionObjectKey(storageService StorageServiceInterface, version *models.CompressionVersion) string {
+	if version.ObjectKey != "" {
+		return version.ObjectKey
+	}
+	return objectKeyFromURL(storageService, version.URL)
+}
+
+// VersionObjectPrefix returns the prefix holding every object of an HLS version, "" for
+// other formats
+func VersionObjectPrefix(storageService StorageServiceInterface, version *models.CompressionVersion) string {
+	if version.Format != utils.HLSFormat {
+		return ""
+	}
+	if version.ObjectPrefix != "" {
+		return version.ObjectPrefix
+	}
+	if key := VersionObjectKey(storageService, version); key != "" {
+		return path.Dir(key) + "/"
+	}
+	return ""
+}
+
+// TrackObjects returns the storage objects recorded on a track: its original, compressed
+// versions, the legacy compressed file, waveforms and artwork variants. prefixes holds the HLS
+// packages and the prefix of every file derived from the track, so files that were never
+// recorded are found too. Both lists are free of duplicates.
+func TrackObjects(storageService StorageServiceInterface, pathConfig StoragePathConfigInterface, track *models.NostrTrack) (objects, prefixes []string) {
+	seen := make(map[string]bool)
+	add := func(list *[]string, key string) {
+		if key != "" && !seen[key] {
+			seen[key] = true
+			*list = append(*list, key)
+		}
+	}
+
+	add(&objects, OriginalObjectKey(storageService, track))
+	for i := range track.CompressionVersions {
+		version := &track.CompressionVersions[i]
+		add(&objects, VersionObjectKey(storageService, version))
+		add(&prefixes, VersionObjectPrefix(storageService, version))
+	}
+	add(&objects, objectKeyFromURL(storageService, track.CompressedURL))
+	add(&objects, pathConfig.GetCompressedPath(track.ID))
+	add(&objects, objectKeyFromURL(storageService, track.WaveformURL))
+	for _, waveform := range track.Waveforms {
+		add(&objects, objectKeyFromURL(storageService, waveform.URL))
+	}
+	for _, variant := range track.ArtworkVariants {
+		add(&objects, objectKeyFromURL(storageService, variant.URL))
+	}
+	add(&prefixes, pathConfig.GetDerivedFilesPrefix(track.ID))
+
+	return objects, prefixes
+}
+
+// objectKeyFromURL returns the object a public URL of the storage service points at, or ""
+// when the URL belongs to another host or bucket
+func objectKeyFromURL(storageService StorageServiceInterface, url string) string {
+	prefix := storageService.GetPublicURL("")
+	if url == "" || !strings.HasPrefix(url, prefix) {
+		return ""
+	}
+	return strings.TrimPrefix(url, prefix)
+}
+
+// ObjectKeyBackfillService records storage object keys on tracks created before they were
+// stored, so storage operations stop depending on URL layout
+type ObjectKeyBackfillService struct {
+	firestoreClient *firestore.Client
+	storageService  StorageServiceInterface
+	pathConfig      *utils.StoragePathConfig
+}
+
+func NewObjectKeyBackfillService(firestoreClient *firestore.Client, storageService StorageServiceInterface) *ObjectKeyBackfillService {
+	return &ObjectKeyBackfillService{
+		firestoreClient: firestoreClient,
+		storageService:  storageService,
+		pathConfig:      utils.GetStoragePathConfig(),
+	}
+}
+
+// BackfillObjectKeys records the object keys of every track and version missing them. Keys
+// are taken from the stored URL, or the standard path when the URL is not a storage URL, and
+// only recorded once the object is confirmed to exist. The fields derived from the versions,
+// has_public_versions and formats, are backfilled alongside. Tracks with nothing missing are
+// skipped, so the backfill can be re-run safely. With dryRun set nothing is written.
+func (s *ObjectKeyBackfillService) BackfillObjectKeys(ctx context.Context, dryRun bool) (*models.ObjectKeyBackfillReport, error) {
+	iter := s.firestoreClient.Collection("nostr_tracks").Documents(ctx)
+	defer iter.Stop()
+
+	report := &models.ObjectKeyBackfillReport{
+		DryRun:  dryRun,
+		Results: []models.ObjectKeyBackfillResult{},
+	}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate tracks: %w", err)
+		}
+
+		var track models.NostrTrack
+		var result models.ObjectKeyBackfillResult
+		if err := doc.DataTo(&track); err != nil {
+			result = models.ObjectKeyBackfillResult{TrackID: doc.Ref.ID, Status: BackfillStatusFailed, Message: fmt.Sprintf("failed to decode track: %v", err)}
+		} else {
+			track.ID = doc.Ref.ID
+			_, hasPublicErr := doc.DataAt("has_public_versions")
+			_, formatsErr := doc.DataAt("formats")
+			result = s.backfillTrack(ctx, &track, hasPublicErr == nil && formatsErr == nil, dryRun)
+		}
+
+		switch result.Status {
+		case BackfillStatusUpdated, BackfillStatusWouldUpdate:
+			report.Updated++
+		case BackfillStatusSkipped:
+			report.Skipped++
+		case BackfillStatusFailed:
+			report.Failed++
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	log.Printf("Object key backfill (dry run: %t): %d updated, %d skipped, %d failed",
+		dryRun, report.Updated, report.Skipped, report.Failed)
+	return report, nil
+}
+
+// backfillTrack records the missing keys of one track and never returns an error; failures
+// are reported in the result so the remaining tracks are still processed. Keys that resolve
+// are recorded even when others do not. The fields derived from the versions are written when
+// they were never stored or are out of date.
+func (s *ObjectKeyBackfillService) backfillTrack(ctx context.Context, track *models.NostrTrack, versionFieldsStored, dryRun bool) models.ObjectKeyBackfillResult {
+	result := models.ObjectKeyBackfillResult{TrackID: track.ID}
+	var updates []firestore.Update
+	var problems []string
+
+	hasPublicVersions, formats := track.HasPublicVersions, track.Formats
+	syncVersionFields(track)
+	if !versionFieldsStored || track.HasPublicVersions != hasPublicVersions || !slices.Equal(track.Formats, formats) {
+		result.VersionFields = true
+		updates = append(updates,
+			firestore.Update{Path: "has_public_versions", Value: track.HasPublicVersions},
+			firestore.Update{Path: "formats", Value: track.Formats},
+		)
+	}
+
+	if track.OriginalObjectKey == "" {
+		key := s.resolveKey(ctx, track.OriginalURL, s.pathConfig.GetOriginalPath(track.ID, track.Extension))
+		if key == "" {
+			problems = append(problems, "original not found in storage")
+		} else {
+			track.OriginalObjectKey = key
+			result.OriginalObjectKey = key
+			updates = append(updates, firestore.Update{Path: "original_object_key", Value: key})
+		}
+	}
+
+	for i := range track.CompressionVersions {
+		version := &track.CompressionVersions[i]
+		if version.ObjectKey != "" {
+			continue
+		}
+
+		fallback := s.pathConfig.GetCompressedVersionPath(track.ID, version.ID, utils.OutputExtension(version.Format))
+		if version.Format == utils.HLSFormat {
+			fallback = s.pathConfig.GetHLSPath(track.ID, version.ID, utils.HLSMasterPlaylist)
+		}
+		key := s.resolveKey(ctx, version.URL, fallback)
+		if key == "" {
+			problems = append(problems, fmt.Sprintf("version %s not found in storage", version.ID))
+			continue
+		}
+
+		version.ObjectKey = key
+		if version.Format == utils.HLSFormat {
+			version.ObjectPrefix = path.Dir(key) + "/"
+		}
+		result.VersionKeys++
+	}
+	if result.VersionKeys > 0 {
+		updates = append(updates, firestore.Update{Path: "compression_versions", Value: track.CompressionVersions})
+	}
+
+	result.Message = strings.Join(problems, "; ")
+	switch {
+	case len(updates) == 0 && len(problems) == 0:
+		result.Status = BackfillStatusSkipped
+		return result
+	case len(updates) == 0:
+		result.Status = BackfillStatusFailed
+		return result
+	case dryRun:
+		result.Status = BackfillStatusWouldUpdate
+		return result
+	}
+
+	if _, err := s.firestoreClient.Collection("nostr_tracks").Doc(track.ID).Update(ctx, updates); err != nil {
+		result.Status = BackfillStatusFailed
+		result.Message = fmt.Sprintf("failed to save keys: %v", err)
+		return result
+	}
+
+	result.Status = BackfillStatusUpdated
+	if len(problems) > 0 {
+		result.Status = BackfillStatusFailed
+	}
+	return result
+}
+
+// resolveKey returns the object a URL points at, or fallback when the URL is not a storage
+// URL, provided the object exists
+func (s *ObjectKeyBackfillService) resolveKey(ctx context.Context, url, fallback string) string {
+	for _, key := range []string{objectKeyFromURL(s.storageService, url), fallback} {
+		if key == "" {
+			continue
+		}
+		if _, err := s.storageService.GetObjectInfo(ctx, key); err == nil {
+			return key
+		}
+	}
+	return ""
+}
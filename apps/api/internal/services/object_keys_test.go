@@ -0,0 +1,103 @@
+package services_test
+
+import (
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/wavlake/monorepo/internal/models"
+	"github.com/wavlake/monorepo/internal/services"
+	"github.com/wavlake/monorepo/internal/utils"
+	"github.com/wavlake/monorepo/tests/mocks"
+)
+
+var _ = Describe("Object keys", func() {
+	const publicPrefix = "https://storage.googleapis.com/bucket/"
+
+	var (
+		ctrl        *gomock.Controller
+		mockStorage *mocks.MockStorageServiceInterface
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockStorage = mocks.NewMockStorageServiceInterface(ctrl)
+		mockStorage.EXPECT().GetPublicURL("").Return(publicPrefix).AnyTimes()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("OriginalObjectKey", func() {
+		It("should prefer the recorded key", func() {
+			track := &models.NostrTrack{
+				OriginalObjectKey: "uploads/legacy/song.wav",
+				OriginalURL:       publicPrefix + "tracks/original/track-1.wav",
+			}
+			Expect(services.OriginalObjectKey(mockStorage, track)).To(Equal("uploads/legacy/song.wav"))
+		})
+
+		It("should fall back to the object the URL points at", func() {
+			track := &models.NostrTrack{OriginalURL: publicPrefix + "tracks/original/track-1.wav"}
+			Expect(services.OriginalObjectKey(mockStorage, track)).To(Equal("tracks/original/track-1.wav"))
+		})
+
+		It("should not guess for URLs outside the bucket", func() {
+			track := &models.NostrTrack{OriginalURL: "https://cdn.example.com/track-1.wav"}
+			Expect(services.OriginalObjectKey(mockStorage, track)).To(BeEmpty())
+		})
+	})
+
+	Describe("VersionObjectKey and VersionObjectPrefix", func() {
+		It("should resolve single-file versions without a prefix", func() {
+			version := &models.CompressionVersion{Format: "mp3", URL: publicPrefix + "tracks/compressed/track-1_v1.mp3"}
+			Expect(services.VersionObjectKey(mockStorage, version)).To(Equal("tracks/compressed/track-1_v1.mp3"))
+			Expect(services.VersionObjectPrefix(mockStorage, version)).To(BeEmpty())
+		})
+
+		It("should resolve the package prefix of HLS versions", func() {
+			version := &models.CompressionVersion{Format: "hls", URL: publicPrefix + "tracks/compressed/track-1_v2/master.m3u8"}
+			Expect(services.VersionObjectKey(mockStorage, version)).To(Equal("tracks/compressed/track-1_v2/master.m3u8"))
+			Expect(services.VersionObjectPrefix(mockStorage, version)).To(Equal("tracks/compressed/track-1_v2/"))
+
+			version.ObjectKey = "custom/v2/master.m3u8"
+			version.ObjectPrefix = "custom/v2/"
+			Expect(services.VersionObjectKey(mockStorage, version)).To(Equal("custom/v2/master.m3u8"))
+			Expect(services.VersionObjectPrefix(mockStorage, version)).To(Equal("custom/v2/"))
+		})
+	})
+
+	Describe("TrackObjects", func() {
+		It("should list every recorded object once and the prefixes of derived files", func() {
+			track := &models.NostrTrack{
+				ID:                "track-1",
+				OriginalObjectKey: "tracks/original/track-1.wav",
+				CompressedURL:     publicPrefix + "tracks/compressed/track-1_v1.mp3",
+				CompressionVersions: []models.CompressionVersion{
+					{ID: "v1", Format: "mp3", URL: publicPrefix + "tracks/compressed/track-1_v1.mp3"},
+					{ID: "v2", Format: "hls", ObjectKey: "custom/v2/master.m3u8", ObjectPrefix: "custom/v2/"},
+				},
+				WaveformURL: publicPrefix + "tracks/compressed/track-1_waveform_256.json",
+				Waveforms: []models.WaveformVariant{
+					{SamplesPerPixel: 256, URL: publicPrefix + "tracks/compressed/track-1_waveform_256.json"},
+				},
+				ArtworkVariants: []models.ArtworkVariant{
+					{Size: 500, Format: "jpg", URL: publicPrefix + "artwork/art-1/500.jpg"},
+					{Size: 500, Format: "webp", URL: "https://cdn.example.com/art-1/500.webp"},
+				},
+			}
+
+			objects, prefixes := services.TrackObjects(mockStorage, utils.GetStoragePathConfig(), track)
+			Expect(objects).To(Equal([]string{
+				"tracks/original/track-1.wav",
+				"tracks/compressed/track-1_v1.mp3",
+				"custom/v2/master.m3u8",
+				"tracks/compressed/track-1.mp3",
+				"tracks/compressed/track-1_waveform_256.json",
+				"artwork/art-1/500.jpg",
+			}))
+			Expect(prefixes).To(Equal([]string{"custom/v2/", "tracks/compressed/track-1_"}))
+		})
+	})
+})
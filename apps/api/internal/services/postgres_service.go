@@ -4,9 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 
 	_ "github.com/lib/pq" // PostgreSQL driver
 	"github.com/wavlake/monorepo/internal/models"
+	"github.com/wavlake/monorepo/internal/utils"
 )
 
 // PostgreSQL Service for legacy database access
@@ -354,5 +357,298 @@ func (p *PostgresService) GetTracksByAlbum(ctx context.Context, albumID string)
 	return tracks, nil
 }
 
+// legacySortColumns maps the sort fields accepted by the List* methods to SQL columns
+var (
+	legacyTrackSortColumns  = map[string]string{"created_at": "t.created_at", "updated_at": "t.updated_at", "title": "t.title"}
+	legacyArtistSortColumns = map[string]string{"created_at": "created_at", "updated_at": "updated_at", "name": "name"}
+	legacyAlbumSortColumns  = map[string]string{"created_at": "al.created_at", "updated_at": "al.updated_at", "title": "al.title"}
+)
+
+// keysetPage builds the keyset pagination part of a List* query. It appends the cursor
+// condition to where (using args for parameters) and returns the ORDER BY/LIMIT clause.
+// One extra row is requested so callers can tell whether another page exists.
+func keysetPage(opts models.ListOptions, sortColumns map[string]string, idColumn string, where []string, args []interface{}) ([]string, []interface{}, string, error) {
+	sortBy, sortOrder := utils.NormalizeSort(opts.SortBy, opts.SortOrder)
+	sortColumn, ok := sortColumns[sortBy]
+	if !ok {
+		return nil, nil, "", fmt.Errorf("unsupported sort field: %s", sortBy)
+	}
+
+	direction, comparison := "DESC", "<"
+	if sortOrder == "asc" {
+		direction, comparison = "ASC", ">"
+	}
+
+	if opts.Cursor != "" {
+		cursor, err := utils.DecodeCursor(opts.Cursor, sortBy, sortOrder)
+		if err != nil {
+			return nil, nil, "", err
+		}
+
+		var value interface{} = cursor.Value
+		if sortBy == "created_at" || sortBy == "updated_at" {
+			cursorTime, err := time.Parse(time.RFC3339Nano, cursor.Value)
+			if err != nil {
+				return nil, nil, "", fmt.Errorf("invalid cursor: %w", err)
+			}
+			value = cursorTime
+		}
+
+		args = append(args, value, cursor.ID)
+		where = append(where, fmt.Sprintf("(%s, %s) %s ($%d, $%d)", sortColumn, idColumn, comparison, len(args)-1, len(args)))
+	}
+
+	limit := utils.NormalizePageLimit(opts.Limit)
+	orderBy := fmt.Sprintf("ORDER BY %s %s, %s %s LIMIT %d", sortColumn, direction, idColumn, direction, limit+1)
+
+	return where, args, orderBy, nil
+}
+
+// keysetCursor returns the cursor for the last item of a page
+func keysetCursor(opts models.ListOptions, createdAt, updatedAt time.Time, text, id string) string {
+	sortBy, sortOrder := utils.NormalizeSort(opts.SortBy, opts.SortOrder)
+	switch sortBy {
+	case "updated_at":
+		return utils.EncodeCursor(sortBy, sortOrder, updatedAt.Format(time.RFC3339Nano), id)
+	case "title", "name":
+		return utils.EncodeCursor(sortBy, sortOrder, text, id)
+	default:
+		return utils.EncodeCursor(sortBy, sortOrder, createdAt.Format(time.RFC3339Nano), id)
+	}
+}
+
+// ListUserTracks retrieves one page of a user's tracks using keyset pagination
+func (p *PostgresService) ListUserTracks(ctx context.Context, firebaseUID string, opts models.ListOptions, filter models.TrackFilter) (*models.LegacyTrackPage, error) {
+	where := []string{"ar.user_id = $1"}
+	args := []interface{}{firebaseUID}
+
+	deleted := false
+	if filter.Deleted != nil {
+		deleted = *filter.Deleted
+	}
+	args = append(args, deleted)
+	where = append(where, fmt.Sprintf("COALESCE(t.deleted, false) = $%d", len(args)))
+
+	if filter.IsProcessing != nil {
+		args = append(args, *filter.IsProcessing)
+		where = append(where, fmt.Sprintf("COALESCE(t.is_processing, false) = $%d", len(args)))
+	}
+	if filter.HasPublicVersions != nil {
+		// Legacy tracks have a single published live file
+		args = append(args, *filter.HasPublicVersions)
+		where = append(where, fmt.Sprintf("(COALESCE(t.live_url, '') <> '') = $%d", len(args)))
+	}
+	if filter.Format != "" {
+		args = append(args, "%."+strings.ToLower(strings.TrimPrefix(filter.Format, ".")))
+		where = append(where, fmt.Sprintf("LOWER(COALESCE(t.raw_url, '')) LIKE $%d", len(args)))
+	}
+
+	where, args, orderBy, err := keysetPage(opts, legacyTrackSortColumns, "t.id", where, args)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT t.id, t.artist_id, t.album_id, t.title, t."order", 
+		       COALESCE(t.play_count, 0) as play_count, COALESCE(t.msat_total, 0) as msat_total,
+		       t.live_url, COALESCE(t.raw_url, '') as raw_url, 
+		       COALESCE(t.size, 0) as size, COALESCE(t.duration, 0) as duration,
+		       COALESCE(t.is_processing, false) as is_processing, COALESCE(t.is_draft, false) as is_draft,
+		       COALESCE(t.is_explicit, false) as is_explicit, COALESCE(t.compressor_error, false) as compressor_error,
+		       COALESCE(t.deleted, false) as deleted, COALESCE(t.lyrics, '') as lyrics,
+		       t.created_at, t.updated_at, t.published_at
+		FROM track t
+		JOIN album al ON t.album_id = al.id
+		JOIN artist ar ON al.artist_id = ar.id
+		WHERE ` + strings.Join(where, " AND ") + `
+		` + orderBy
+
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tracks: %w", err)
+	}
+	defer rows.Close()
+
+	tracks := []models.LegacyTrack{}
+	for rows.Next() {
+		var track models.LegacyTrack
+		err := rows.Scan(
+			&track.ID,
+			&track.ArtistID,
+			&track.AlbumID,
+			&track.Title,
+			&track.Order,
+			&track.PlayCount,
+			&track.MSatTotal,
+			&track.LiveURL,
+			&track.RawURL,
+			&track.Size,
+			&track.Duration,
+			&track.IsProcessing,
+			&track.IsDraft,
+			&track.IsExplicit,
+			&track.CompressorError,
+			&track.Deleted,
+			&track.Lyrics,
+			&track.CreatedAt,
+			&track.UpdatedAt,
+			&track.PublishedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan track: %w", err)
+		}
+		tracks = append(tracks, track)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate tracks: %w", err)
+	}
+
+	page := &models.LegacyTrackPage{Tracks: tracks}
+	if limit := utils.NormalizePageLimit(opts.Limit); len(tracks) > limit {
+		page.Tracks = tracks[:limit]
+		last := page.Tracks[limit-1]
+		page.NextCursor = keysetCursor(opts, last.CreatedAt, last.UpdatedAt, last.Title, last.ID)
+	}
+
+	return page, nil
+}
+
+// ListUserArtists retrieves one page of a user's artists using keyset pagination
+func (p *PostgresService) ListUserArtists(ctx context.Context, firebaseUID string, opts models.ListOptions) (*models.LegacyArtistPage, error) {
+	where := []string{"user_id = $1", "NOT COALESCE(deleted, false)"}
+	args := []interface{}{firebaseUID}
+
+	where, args, orderBy, err := keysetPage(opts, legacyArtistSortColumns, "id", where, args)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT id, user_id, name, COALESCE(artwork_url, '') as artwork_url,
+		       artist_url, COALESCE(bio, '') as bio, COALESCE(twitter, '') as twitter,
+		       COALESCE(instagram, '') as instagram, COALESCE(youtube, '') as youtube,
+		       COALESCE(website, '') as website, COALESCE(npub, '') as npub,
+		       COALESCE(verified, false) as verified, COALESCE(deleted, false) as deleted,
+		       COALESCE(msat_total, 0) as msat_total, created_at, updated_at
+		FROM artist 
+		WHERE ` + strings.Join(where, " AND ") + `
+		` + orderBy
+
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query artists: %w", err)
+	}
+	defer rows.Close()
+
+	artists := []models.LegacyArtist{}
+	for rows.Next() {
+		var artist models.LegacyArtist
+		err := rows.Scan(
+			&artist.ID,
+			&artist.UserID,
+			&artist.Name,
+			&artist.ArtworkURL,
+			&artist.ArtistURL,
+			&artist.Bio,
+			&artist.Twitter,
+			&artist.Instagram,
+			&artist.Youtube,
+			&artist.Website,
+			&artist.Npub,
+			&artist.Verified,
+			&artist.Deleted,
+			&artist.MSatTotal,
+			&artist.CreatedAt,
+			&artist.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan artist: %w", err)
+		}
+		artists = append(artists, artist)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate artists: %w", err)
+	}
+
+	page := &models.LegacyArtistPage{Artists: artists}
+	if limit := utils.NormalizePageLimit(opts.Limit); len(artists) > limit {
+		page.Artists = artists[:limit]
+		last := page.Artists[limit-1]
+		page.NextCursor = keysetCursor(opts, last.CreatedAt, last.UpdatedAt, last.Name, last.ID)
+	}
+
+	return page, nil
+}
+
+// ListUserAlbums retrieves one page of a user's albums using keyset pagination
+func (p *PostgresService) ListUserAlbums(ctx context.Context, firebaseUID string, opts models.ListOptions) (*models.LegacyAlbumPage, error) {
+	where := []string{"ar.user_id = $1", "NOT COALESCE(al.deleted, false)"}
+	args := []interface{}{firebaseUID}
+
+	where, args, orderBy, err := keysetPage(opts, legacyAlbumSortColumns, "al.id", where, args)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT al.id, al.artist_id, al.title, COALESCE(al.artwork_url, '') as artwork_url,
+		       COALESCE(al.description, '') as description, COALESCE(al.genre_id, 0) as genre_id,
+		       COALESCE(al.subgenre_id, 0) as subgenre_id, COALESCE(al.is_draft, false) as is_draft,
+		       COALESCE(al.is_single, false) as is_single, COALESCE(al.deleted, false) as deleted,
+		       COALESCE(al.msat_total, 0) as msat_total, COALESCE(al.is_feed_published, true) as is_feed_published,
+		       al.published_at, al.created_at, al.updated_at
+		FROM album al
+		JOIN artist ar ON al.artist_id = ar.id
+		WHERE ` + strings.Join(where, " AND ") + `
+		` + orderBy
+
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query albums: %w", err)
+	}
+	defer rows.Close()
+
+	albums := []models.LegacyAlbum{}
+	for rows.Next() {
+		var album models.LegacyAlbum
+		err := rows.Scan(
+			&album.ID,
+			&album.ArtistID,
+			&album.Title,
+			&album.ArtworkURL,
+			&album.Description,
+			&album.GenreID,
+			&album.SubgenreID,
+			&album.IsDraft,
+			&album.IsSingle,
+			&album.Deleted,
+			&album.MSatTotal,
+			&album.IsFeedPublished,
+			&album.PublishedAt,
+			&album.CreatedAt,
+			&album.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan album: %w", err)
+		}
+		albums = append(albums, album)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate albums: %w", err)
+	}
+
+	page := &models.LegacyAlbumPage{Albums: albums}
+	if limit := utils.NormalizePageLimit(opts.Limit); len(albums) > limit {
+		page.Albums = albums[:limit]
+		last := page.Albums[limit-1]
+		page.NextCursor = keysetCursor(opts, last.CreatedAt, last.UpdatedAt, last.Title, last.ID)
+	}
+
+	return page, nil
+}
+
 // Ensure PostgresService implements the interface
 var _ PostgresServiceInterface = (*PostgresService)(nil)
\ No newline at end of file
@@ -1,33 +1,52 @@
 package services
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/wavlake/monorepo/internal/config"
 	"github.com/wavlake/monorepo/internal/models"
 	"github.com/wavlake/monorepo/internal/utils"
 )
 
 type ProcessingService struct {
-	storageService    StorageServiceInterface
-	nostrTrackService *NostrTrackService
-	audioProcessor    *utils.AudioProcessor
-	tempDir           string
-	pathConfig        *utils.StoragePathConfig
+	storageService     StorageServiceInterface
+	nostrTrackService  *NostrTrackService
+	audioProcessor     *utils.AudioProcessor
+	tempDir            string
+	pathConfig         *utils.StoragePathConfig
+	processingConfig   *config.ProcessingConfig
+	fingerprintService FingerprintServiceInterface
+	artistService      NostrArtistServiceInterface
+	albumService       NostrAlbumServiceInterface
+	workingSets        *WorkingSetCache
 }
 
-func NewProcessingService(storageService StorageServiceInterface, nostrTrackService *NostrTrackService, audioProcessor *utils.AudioProcessor, tempDir string) *ProcessingService {
+func NewProcessingService(storageService StorageServiceInterface, nostrTrackService *NostrTrackService, audioProcessor *utils.AudioProcessor, tempDir string, processingConfig *config.ProcessingConfig, fingerprintService FingerprintServiceInterface, artistService NostrArtistServiceInterface, albumService NostrAlbumServiceInterface) *ProcessingService {
 	return &ProcessingService{
-		storageService:    storageService,
-		nostrTrackService: nostrTrackService,
-		audioProcessor:    audioProcessor,
-		tempDir:           tempDir,
-		pathConfig:        utils.GetStoragePathConfig(),
+		storageService:     storageService,
+		nostrTrackService:  nostrTrackService,
+		audioProcessor:     audioProcessor,
+		tempDir:            tempDir,
+		pathConfig:         utils.GetStoragePathConfig(),
+		processingConfig:   processingConfig,
+		fingerprintService: fingerprintService,
+		artistService:      artistService,
+		albumService:       albumService,
+		workingSets:        NewWorkingSetCache(tempDir),
 	}
 }
 
@@ -41,148 +60,474 @@ func (p *ProcessingService) ProcessTrack(ctx context.Context, trackID string) er
 		return fmt.Errorf("failed to get track: %w", err)
 	}
 
-	// Create temp files
-	originalPath := filepath.Join(p.tempDir, fmt.Sprintf("%s_original.%s", trackID, track.Extension))
-	compressedPath := filepath.Join(p.tempDir, fmt.Sprintf("%s_compressed.mp3", trackID))
+	// Download original file from GCS, unless another job on the track already has it
+	ladderNames := p.ladderNames(ctx, track)
+	workingSet, release, err := p.acquireOriginal(ctx, track, len(ladderNames))
+	if err != nil {
+		return p.markProcessingFailed(ctx, trackID, fmt.Sprintf("download failed: %v", err))
+	}
+	defer release()
+	originalPath, fileHash := workingSet.OriginalPath, workingSet.FileHash
 
-	defer func() {
-		_ = os.Remove(originalPath)   // #nosec G104 -- Cleanup operation, errors not critical
-		_ = os.Remove(compressedPath) // #nosec G104 -- Cleanup operation, errors not critical
-	}()
+	if track.UploadState != models.UploadStateComplete {
+		if err := p.nostrTrackService.UpdateTrack(ctx, trackID, map[string]interface{}{"upload_state": models.UploadStateComplete}); err != nil {
+			log.Printf("Warning: Could not mark upload of track %s complete: %v", trackID, err)
+		}
+	}
 
-	// Download original file from GCS
-	if err := p.downloadFile(ctx, track.OriginalURL, originalPath); err != nil {
-		return p.markProcessingFailed(ctx, trackID, fmt.Sprintf("download failed: %v", err))
+	// Check whether another pubkey already uploaded the same file
+	duplicate, reject, err := p.findDuplicate(ctx, track, fileHash)
+	if err != nil {
+		log.Printf("Warning: Could not check track %s for duplicates: %v", trackID, err)
+	}
+	if reject {
+		if err := p.nostrTrackService.UpdateTrack(ctx, trackID, map[string]interface{}{
+			"file_hash":    fileHash,
+			"duplicate_of": duplicate.ID,
+		}); err != nil {
+			log.Printf("Failed to record duplicate for track %s: %v", trackID, err)
+		}
+		return p.markProcessingFailed(ctx, trackID, fmt.Sprintf("duplicate upload: matches track %s", duplicate.ID))
 	}
 
-	// Validate it's a valid audio file
-	if err := p.audioProcessor.ValidateAudioFile(ctx, originalPath); err != nil {
-		return p.markProcessingFailed(ctx, trackID, fmt.Sprintf("invalid audio file: %v", err))
+	// Enforce the upload policy; the signed URL only bounds the size
+	probe, reasons := p.checkUploadPolicy(ctx, originalPath)
+	if len(reasons) > 0 {
+		return p.markUploadRejected(ctx, trackID, reasons)
+	}
+
+	// Fingerprinting only feeds moderation, so it never blocks processing
+	if p.processingConfig.FingerprintEnabled && p.fingerprintService != nil {
+		if err := p.fingerprintTrack(ctx, track, originalPath); err != nil {
+			log.Printf("Warning: Could not fingerprint track %s: %v", trackID, err)
+		}
 	}
 
 	// Get audio metadata
-	audioInfo, err := p.audioProcessor.GetAudioInfo(ctx, originalPath)
+	audioInfo, err := probe.AudioInfo()
 	if err != nil {
 		log.Printf("Warning: Could not get audio info for %s: %v", trackID, err)
 		// Continue processing even if we can't get metadata
 	}
 
-	// Compress the audio with default options
-	defaultOptions := models.CompressionOption{
-		Bitrate:    128,
-		Format:     "mp3",
-		Quality:    "medium",
-		SampleRate: 44100,
-	}
-	if err := p.audioProcessor.CompressAudio(ctx, originalPath, compressedPath, defaultOptions); err != nil {
-		return p.markProcessingFailed(ctx, trackID, fmt.Sprintf("compression failed: %v", err))
-	}
-
-	// Upload compressed file to GCS
-	compressedObjectName := p.pathConfig.GetCompressedPath(trackID)
-	compressedFile, err := os.Open(compressedPath) // #nosec G304 -- Opening controlled temp file for upload
+	// Measure EBU R128 loudness of the original
+	loudness, err := p.audioProcessor.AnalyzeLoudness(ctx, originalPath)
 	if err != nil {
-		return p.markProcessingFailed(ctx, trackID, fmt.Sprintf("failed to open compressed file: %v", err))
+		log.Printf("Warning: Could not analyze loudness for %s: %v", trackID, err)
+		// Continue processing, outputs are just not tagged with ReplayGain
 	}
-	defer compressedFile.Close()
 
-	if err := p.storageService.UploadObject(ctx, compressedObjectName, compressedFile, "audio/mpeg"); err != nil {
-		return p.markProcessingFailed(ctx, trackID, fmt.Sprintf("failed to upload compressed file: %v", err))
+	// Generate the artist's compression ladder
+	tags, cleanupTags := p.buildOutputTags(ctx, track)
+	defer cleanupTags()
+
+	ladder := p.compressionLadder(track, ladderNames, audioInfo)
+	if len(ladder) == 0 {
+		return p.markProcessingFailed(ctx, trackID, "compression failed: no compression preset applies to this file")
 	}
 
-	compressedURL := p.storageService.GetPublicURL(compressedObjectName)
+	encode := utils.EncodeContext{Loudness: loudness, Tags: tags}
+	var versions []models.CompressionVersion
+	var compressErr error
+	for _, result := range p.createVersions(ctx, trackID, workingSet, ladder, encode) {
+		if result.err != nil {
+			log.Printf("Warning: Failed to generate %s preset for track %s: %v", result.option.Preset, trackID, result.err)
+			compressErr = result.err
+			continue
+		}
+		// Ladder versions are public, as the single default version was
+		result.version.IsPublic = true
+		versions = append(versions, *result.version)
+	}
+	if len(versions) == 0 {
+		return p.markProcessingFailed(ctx, trackID, fmt.Sprintf("compression failed: %v", compressErr))
+	}
+	compressedURL := legacyCompressedURL(versions)
 
 	// Update track with processing results (legacy fields for backwards compatibility)
 	updates := map[string]interface{}{
 		"is_processing":  false,
 		"is_compressed":  true,
 		"compressed_url": compressedURL,
+		"file_hash":      fileHash,
+	}
+
+	if duplicate != nil {
+		updates["duplicate_of"] = duplicate.ID
 	}
 
 	if audioInfo != nil {
 		updates["size"] = audioInfo.Size
 		updates["duration"] = audioInfo.Duration
 	}
+	if loudness != nil {
+		updates["loudness"] = loudness
+	}
+
+	// Waveform peaks are a player nicety; a failure leaves the track playable without them
+	if waveforms, err := p.generateWaveforms(ctx, trackID, originalPath); err != nil {
+		log.Printf("Warning: Could not generate waveforms for %s: %v", trackID, err)
+	} else {
+		updates["waveforms"] = waveforms
+		updates["waveform_url"] = primaryWaveformURL(waveforms)
+	}
 
 	if err := p.nostrTrackService.UpdateTrack(ctx, trackID, updates); err != nil {
 		log.Printf("Failed to update track %s after processing: %v", trackID, err)
 		// Don't return error since processing succeeded
 	}
 
-	// Also add as a compression version for new system compatibility
-	defaultVersion := models.CompressionVersion{
-		ID:         "default-128k-mp3",
-		URL:        compressedURL,
-		Bitrate:    128,
-		Format:     "mp3",
-		Quality:    "medium",
-		SampleRate: 44100,
-		Size:       0,    // Will be updated if we can get file info
-		IsPublic:   true, // Default compressed version is public for backwards compatibility
-		CreatedAt:  time.Now(),
-		Options: models.CompressionOption{
-			Bitrate:    128,
-			Format:     "mp3",
-			Quality:    "medium",
-			SampleRate: 44100,
-		},
+	for _, version := range versions {
+		if err := p.nostrTrackService.AddCompressionVersion(ctx, trackID, version); err != nil {
+			log.Printf("Warning: Failed to add compression version %s for track %s: %v", version.ID, trackID, err)
+		}
 	}
 
-	// Try to get compressed file size
-	if compressedInfo, err := os.Stat(compressedPath); err == nil {
-		defaultVersion.Size = compressedInfo.Size()
+	log.Printf("Successfully processed track %s", trackID)
+	return nil
+}
+
+// ladderNames returns the presets generated on upload: the default ladder of the artist's
+// profile unless it was deleted, or the platform default
+func (p *ProcessingService) ladderNames(ctx context.Context, track *models.NostrTrack) []string {
+	if p.artistService != nil {
+		if artist, err := p.artistService.GetArtist(ctx, track.Pubkey); err == nil && !artist.Deleted && len(artist.DefaultLadder) > 0 {
+			return artist.DefaultLadder
+		}
 	}
+	return p.processingConfig.DefaultLadder
+}
 
-	// Add default compression version (ignore errors to maintain backwards compatibility)
-	if err := p.nostrTrackService.AddCompressionVersion(ctx, trackID, defaultVersion); err != nil {
-		log.Printf("Warning: Failed to add default compression version for track %s: %v", trackID, err)
+// compressionLadder resolves the ladder's presets, without those that would upsample the
+// original
+func (p *ProcessingService) compressionLadder(track *models.NostrTrack, names []string, audioInfo *utils.AudioInfo) []models.CompressionOption {
+	presets, err := p.processingConfig.ResolvePresets(names)
+	if err != nil {
+		// A preset was removed from config after the artist chose it
+		log.Printf("Warning: Using the default ladder for track %s: %v", track.ID, err)
+		if presets, err = p.processingConfig.ResolvePresets(p.processingConfig.DefaultLadder); err != nil {
+			log.Printf("Warning: Invalid default ladder: %v", err)
+			return nil
+		}
 	}
 
-	log.Printf("Successfully processed track %s", trackID)
-	return nil
+	return utils.PlanCompressionLadder(presets, audioInfo, utils.IsLosslessExtension(track.Extension))
 }
 
-// downloadFile downloads a file from a URL to local path
-func (p *ProcessingService) downloadFile(ctx context.Context, url, filePath string) error {
-	// For GCS URLs, we can use the storage client directly
-	// This is more efficient than HTTP download for files in the same project
+// legacyCompressedURL picks the version exposed as compressed_url, preferring MP3 for older
+// clients
+func legacyCompressedURL(versions []models.CompressionVersion) string {
+	for _, version := range versions {
+		if version.Format == "mp3" {
+			return version.URL
+		}
+	}
+	return versions[0].URL
+}
 
-	// Create temp file
-	tempFile, err := os.Create(filePath) // #nosec G304 -- Creating controlled temp file for processing
+// generateWaveforms computes peak data from the decoded original at each default resolution
+// and uploads it as audiowaveform-compatible JSON
+func (p *ProcessingService) generateWaveforms(ctx context.Context, trackID, originalPath string) ([]models.WaveformVariant, error) {
+	waveforms, err := p.audioProcessor.GenerateWaveforms(ctx, originalPath, utils.DefaultWaveformResolutions)
 	if err != nil {
-		return fmt.Errorf("failed to create temp file: %w", err)
+		return nil, err
 	}
-	defer tempFile.Close()
 
-	// Extract object name from URL
-	// URL format: https://storage.googleapis.com/bucket/object
-	// We need to get the object name part
-	objectName := ""
-	if len(url) > 0 {
-		// Simple extraction - in production you might want more robust parsing
-		parts := filepath.Base(url)
-		if track, err := p.nostrTrackService.GetTrack(ctx, parts[:len(parts)-len(filepath.Ext(parts))]); err == nil {
-			objectName = p.pathConfig.GetOriginalPath(track.ID, track.Extension)
+	variants := make([]models.WaveformVariant, 0, len(waveforms))
+	for _, waveform := range waveforms {
+		data, err := json.Marshal(waveform)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode waveform: %w", err)
+		}
+
+		objectName := p.pathConfig.GetWaveformPath(trackID, waveform.SamplesPerPixel)
+		if err := p.storageService.UploadObject(ctx, objectName, bytes.NewReader(data), "application/json"); err != nil {
+			return nil, fmt.Errorf("failed to upload waveform: %w", err)
 		}
+
+		variants = append(variants, models.WaveformVariant{
+			SamplesPerPixel: waveform.SamplesPerPixel,
+			URL:             p.storageService.GetPublicURL(objectName),
+		})
 	}
 
+	return variants, nil
+}
+
+// primaryWaveformURL picks the variant exposed as the track's waveform_url
+func primaryWaveformURL(variants []models.WaveformVariant) string {
+	for _, variant := range variants {
+		if variant.SamplesPerPixel == utils.PrimaryWaveformResolution {
+			return variant.URL
+		}
+	}
+	if len(variants) > 0 {
+		return variants[0].URL
+	}
+	return ""
+}
+
+// downloadFile downloads a storage object to local path and returns its hex SHA-256
+func (p *ProcessingService) downloadFile(ctx context.Context, objectName, filePath string) (string, error) {
 	if objectName == "" {
-		return fmt.Errorf("could not determine object name from URL")
+		return "", fmt.Errorf("track has no storage object recorded for its original")
+	}
+
+	// Create temp file
+	tempFile, err := os.Create(filePath) // #nosec G304 -- Creating controlled temp file for processing
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
 	}
+	defer tempFile.Close()
 
 	// Download from storage
 	reader, err := p.storageService.GetObjectReader(ctx, objectName)
 	if err != nil {
-		return fmt.Errorf("failed to create storage reader: %w", err)
+		return "", fmt.Errorf("failed to create storage reader: %w", err)
 	}
 	defer reader.Close()
 
-	// Copy to temp file
-	if _, err := tempFile.ReadFrom(reader); err != nil {
-		return fmt.Errorf("failed to download file: %w", err)
+	// Copy to temp file, hashing as we go
+	hasher := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tempFile, hasher), reader); err != nil {
+		return "", fmt.Errorf("failed to download file: %w", err)
 	}
 
-	return nil
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// acquireOriginal returns the track's working set, downloading the original if no other job
+// holds it. It first checks the temp directory has room for the original and outputs
+// versions, each estimated at the original's size.
+func (p *ProcessingService) acquireOriginal(ctx context.Context, track *models.NostrTrack, outputs int) (*WorkingSet, func(), error) {
+	objectName := OriginalObjectKey(p.storageService, track)
+	size := track.Size
+	if objectName != "" {
+		if info, err := p.storageService.GetObjectInfo(ctx, objectName); err == nil && info.Size > 0 {
+			size = info.Size
+		}
+	}
+	need := size*int64(1+outputs) + p.processingConfig.MinFreeDiskBytes
+	if err := utils.CheckDiskSpace(p.tempDir, need); err != nil {
+		return nil, nil, err
+	}
+
+	return p.workingSets.Acquire(ctx, track.ID, track.Extension, func(ctx context.Context, path string) (string, error) {
+		return p.downloadFile(ctx, objectName, path)
+	})
+}
+
+// findDuplicate looks up the tracks whose original has the same hash and applies FindDuplicate
+// with the configured policy
+func (p *ProcessingService) findDuplicate(ctx context.Context, track *models.NostrTrack, fileHash string) (duplicate *models.NostrTrack, reject bool, err error) {
+	matches, err := p.nostrTrackService.FindTracksByFileHash(ctx, fileHash)
+	if err != nil {
+		return nil, false, err
+	}
+	duplicate, reject = FindDuplicate(track, matches, p.processingConfig.DuplicatePolicy)
+	return duplicate, reject, nil
+}
+
+// FindDuplicate returns the oldest of matches owned by another pubkey than track, or nil.
+// Re-uploads by the same pubkey are not duplicates. reject reports whether policy fails
+// processing because of the duplicate; config.DuplicatePolicyFlag only records it.
+func FindDuplicate(track *models.NostrTrack, matches []*models.NostrTrack, policy string) (duplicate *models.NostrTrack, reject bool) {
+	for _, match := range matches {
+		if match.ID == track.ID || match.Pubkey == track.Pubkey {
+			continue
+		}
+		if duplicate == nil || match.CreatedAt.Before(duplicate.CreatedAt) {
+			duplicate = match
+		}
+	}
+	return duplicate, duplicate != nil && policy == config.DuplicatePolicyReject
+}
+
+// fingerprintTrack stores the acoustic fingerprint of the original and queues a moderation
+// entry for each near-duplicate track owned by another pubkey
+func (p *ProcessingService) fingerprintTrack(ctx context.Context, track *models.NostrTrack, originalPath string) error {
+	fingerprint, err := p.audioProcessor.GenerateFingerprint(ctx, originalPath)
+	if err != nil {
+		return err
+	}
+
+	stored := NewTrackFingerprint(track.ID, track.Pubkey, fingerprint)
+	matches, err := p.fingerprintService.FindMatches(ctx, stored, p.processingConfig.FingerprintMatchThreshold)
+	if err != nil {
+		return err
+	}
+
+	for _, match := range matches {
+		if match.Pubkey == track.Pubkey {
+			continue
+		}
+		log.Printf("Track %s matches track %s (similarity %.2f), queueing for moderation", track.ID, match.TrackID, match.Similarity)
+		if err := p.fingerprintService.CreateModerationEntry(ctx, &models.ModerationEntry{
+			Type:           models.ModerationTypeFingerprintMatch,
+			TrackID:        track.ID,
+			Pubkey:         track.Pubkey,
+			MatchedTrackID: match.TrackID,
+			MatchedPubkey:  match.Pubkey,
+			Similarity:     match.Similarity,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return p.fingerprintService.SaveFingerprint(ctx, stored)
+}
+
+// buildOutputTags assembles the tags written into compressed outputs from the track record,
+// its artist profile and the album that lists it, downloading cover art to a temp file.
+// Lookups that fail only leave tags out. The returned cleanup removes the cover art.
+func (p *ProcessingService) buildOutputTags(ctx context.Context, track *models.NostrTrack) (*utils.OutputTags, func()) {
+	tags := &utils.OutputTags{
+		Title: track.Title,
+		Year:  track.CreatedAt.Year(),
+	}
+	if p.processingConfig.TrackURLTemplate != "" {
+		tags.URL = fmt.Sprintf(p.processingConfig.TrackURLTemplate, track.ID)
+	}
+
+	if p.artistService != nil {
+		if artist, err := p.artistService.GetArtist(ctx, track.Pubkey); err == nil && !artist.Deleted {
+			tags.Artist = artist.Name
+			tags.AlbumArtist = artist.Name
+		}
+	}
+
+	artwork := track.ArtworkVariants
+	artworkURL := track.ArtworkURL
+	if p.albumService != nil {
+		if album := p.findTrackAlbum(ctx, track); album != nil {
+			tags.Album = album.Title
+			tags.Genre = album.Genre
+			if !album.PublishedAt.IsZero() {
+				tags.Year = album.PublishedAt.Year()
+			}
+			for i, trackID := range album.TrackIDs {
+				if trackID == track.ID {
+					tags.TrackNumber = i + 1
+				}
+			}
+			if len(artwork) == 0 && artworkURL == "" {
+				artwork = album.ArtworkVariants
+				artworkURL = album.ArtworkURL
+			}
+		}
+	}
+
+	cleanup := func() {}
+	if coverURL := coverArtURL(artwork, artworkURL); coverURL != "" {
+		coverPath, err := p.downloadCoverArt(ctx, track.ID, coverURL)
+		if err != nil {
+			log.Printf("Warning: Could not download cover art for track %s: %v", track.ID, err)
+		} else {
+			tags.CoverArtPath = coverPath
+			cleanup = func() {
+				_ = os.Remove(coverPath) // #nosec G104 -- Cleanup operation, errors not critical
+			}
+		}
+	}
+
+	return tags, cleanup
+}
+
+// findTrackAlbum returns the owner's album listing the track, preferring published albums
+func (p *ProcessingService) findTrackAlbum(ctx context.Context, track *models.NostrTrack) *models.NostrAlbum {
+	albums, err := p.albumService.GetAlbumsByPubkey(ctx, track.Pubkey)
+	if err != nil {
+		log.Printf("Warning: Could not list albums for track %s: %v", track.ID, err)
+		return nil
+	}
+
+	var found *models.NostrAlbum
+	for _, album := range albums {
+		if album.Deleted {
+			continue
+		}
+		for _, trackID := range album.TrackIDs {
+			if trackID == track.ID && (found == nil || (found.IsDraft && !album.IsDraft)) {
+				found = album
+			}
+		}
+	}
+	return found
+}
+
+// coverArtURL picks the JPEG variant closest to 600px, since ID3v2 and MP4 only carry JPEG
+// and PNG covers, falling back to the primary artwork when it is one of those
+func coverArtURL(variants []models.ArtworkVariant, primaryURL string) string {
+	const preferredSize = 600
+
+	best := -1
+	for i, variant := range variants {
+		if variant.Format != "jpg" {
+			continue
+		}
+		if best < 0 || absInt(variant.Size-preferredSize) < absInt(variants[best].Size-preferredSize) {
+			best = i
+		}
+	}
+	if best >= 0 {
+		return variants[best].URL
+	}
+
+	switch strings.ToLower(filepath.Ext(primaryURL)) {
+	case ".jpg", ".jpeg", ".png":
+		return primaryURL
+	}
+	return ""
+}
+
+// downloadCoverArt copies cover art from storage to a temp file
+func (p *ProcessingService) downloadCoverArt(ctx context.Context, trackID, coverURL string) (string, error) {
+	prefix := p.storageService.GetPublicURL("")
+	if !strings.HasPrefix(coverURL, prefix) {
+		return "", fmt.Errorf("cover art is not in the storage bucket: %s", coverURL)
+	}
+
+	reader, err := p.storageService.GetObjectReader(ctx, strings.TrimPrefix(coverURL, prefix))
+	if err != nil {
+		return "", fmt.Errorf("failed to open cover art: %w", err)
+	}
+	defer reader.Close()
+
+	coverPath := filepath.Join(p.tempDir, fmt.Sprintf("%s_%s_cover%s", trackID, uuid.New().String(), strings.ToLower(filepath.Ext(coverURL))))
+	file, err := os.Create(coverPath) // #nosec G304 -- Creating controlled temp file for processing
+	if err != nil {
+		return "", fmt.Errorf("failed to create cover art file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, reader); err != nil {
+		_ = os.Remove(coverPath) // #nosec G104 -- Cleanup operation, errors not critical
+		return "", fmt.Errorf("failed to download cover art: %w", err)
+	}
+
+	return coverPath, nil
+}
+
+func absInt(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// hashFile returns the hex SHA-256 of a local file
+func hashFile(filePath string) (string, error) {
+	file, err := os.Open(filePath) // #nosec G304 -- Hashing controlled temp file
+	if err != nil {
+		return "", fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
 }
 
 // markProcessingFailed marks a track as failed processing
@@ -197,6 +542,45 @@ func (p *ProcessingService) markProcessingFailed(ctx context.Context, trackID, e
 	return p.nostrTrackService.UpdateTrack(ctx, trackID, updates)
 }
 
+// checkUploadPolicy probes a downloaded original and returns the probe with every upload policy
+// violation. Files that cannot be probed or fully decoded are rejected as corrupted.
+func (p *ProcessingService) checkUploadPolicy(ctx context.Context, originalPath string) (*utils.ProbeResult, []string) {
+	info, err := os.Stat(originalPath)
+	if err != nil {
+		return nil, []string{fmt.Sprintf("file could not be read: %v", err)}
+	}
+
+	probe, err := p.audioProcessor.Probe(ctx, originalPath)
+	if err != nil {
+		return nil, []string{"file could not be read as audio; it may be corrupted"}
+	}
+
+	policy := p.processingConfig.UploadPolicy
+	if policy == nil {
+		policy = config.NewUploadPolicy()
+	}
+	if reasons := policy.Check(probe, info.Size()); len(reasons) > 0 {
+		return probe, reasons
+	}
+
+	if err := p.audioProcessor.CheckDecodes(ctx, originalPath); err != nil {
+		log.Printf("Decode check failed for %s: %v", originalPath, err)
+		return probe, []string{"audio could not be decoded; the file may be corrupted or truncated"}
+	}
+	return probe, nil
+}
+
+// markUploadRejected fails processing with the upload policy violations stored on the track
+func (p *ProcessingService) markUploadRejected(ctx context.Context, trackID string, reasons []string) error {
+	log.Printf("Upload rejected for track %s: %s", trackID, strings.Join(reasons, "; "))
+
+	return p.nostrTrackService.UpdateTrack(ctx, trackID, map[string]interface{}{
+		"is_processing":     false,
+		"error":             "upload rejected: " + strings.Join(reasons, "; "),
+		"rejection_reasons": reasons,
+	})
+}
+
 // ProcessTrackAsync starts track processing in a goroutine
 func (p *ProcessingService) ProcessTrackAsync(ctx context.Context, trackID string) {
 	go func() {
@@ -214,36 +598,61 @@ func (p *ProcessingService) ProcessTrackAsync(ctx context.Context, trackID strin
 func (p *ProcessingService) RequestCompressionVersions(ctx context.Context, trackID string, compressionOptions []models.CompressionOption) error {
 	log.Printf("Requesting compression versions for track %s with %d options", trackID, len(compressionOptions))
 
+	// Presets are resolved into a new slice; the caller's options are left untouched
+	resolved := make([]models.CompressionOption, 0, len(compressionOptions))
+	for _, option := range compressionOptions {
+		if option.Preset != "" {
+			presets, err := p.processingConfig.ResolvePresets([]string{option.Preset})
+			if err != nil {
+				return err
+			}
+			option = presets[0]
+		}
+		if err := utils.ValidateCompressionOption(option); err != nil {
+			return fmt.Errorf("invalid compression option: %w", err)
+		}
+		resolved = append(resolved, option)
+	}
+
 	// Mark track as having pending compression
 	if err := p.nostrTrackService.SetPendingCompression(ctx, trackID, true); err != nil {
 		return fmt.Errorf("failed to mark track as pending compression: %w", err)
 	}
 
-	// Process each compression option asynchronously
-	for _, option := range compressionOptions {
-		p.ProcessCompressionAsync(ctx, trackID, option)
-	}
+	// Encode every option in one job, so the original is downloaded and decoded once
+	p.ProcessCompressionsAsync(ctx, trackID, resolved)
 
 	return nil
 }
 
 // ProcessCompressionAsync processes a single compression option in background
 func (p *ProcessingService) ProcessCompressionAsync(ctx context.Context, trackID string, option models.CompressionOption) {
+	p.ProcessCompressionsAsync(ctx, trackID, []models.CompressionOption{option})
+}
+
+// ProcessCompressionsAsync processes compression options in background
+func (p *ProcessingService) ProcessCompressionsAsync(ctx context.Context, trackID string, options []models.CompressionOption) {
 	go func() {
 		// Create a background context with timeout
 		processCtx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
 		defer cancel()
 
-		if err := p.ProcessCompression(processCtx, trackID, option); err != nil {
-			log.Printf("Async compression failed for track %s (option: %+v): %v", trackID, option, err)
+		if err := p.ProcessCompressions(processCtx, trackID, options); err != nil {
+			log.Printf("Async compression failed for track %s (options: %+v): %v", trackID, options, err)
 		}
 	}()
 }
 
 // ProcessCompression creates a single compressed version of a track
 func (p *ProcessingService) ProcessCompression(ctx context.Context, trackID string, option models.CompressionOption) error {
-	versionID := uuid.New().String()
-	log.Printf("Starting compression for track %s, version %s (bitrate: %d, format: %s)", trackID, versionID, option.Bitrate, option.Format)
+	return p.ProcessCompressions(ctx, trackID, []models.CompressionOption{option})
+}
+
+// ProcessCompressions creates a compressed version of a track for each option. The original
+// is downloaded once, and the options are encoded in a single ffmpeg run where possible.
+// Versions that succeed are saved even if others fail.
+func (p *ProcessingService) ProcessCompressions(ctx context.Context, trackID string, options []models.CompressionOption) error {
+	log.Printf("Starting compression for track %s with %d options", trackID, len(options))
 
 	// Get track info
 	track, err := p.nostrTrackService.GetTrack(ctx, trackID)
@@ -251,53 +660,155 @@ func (p *ProcessingService) ProcessCompression(ctx context.Context, trackID stri
 		return fmt.Errorf("failed to get track: %w", err)
 	}
 
-	// Create temp files
-	originalPath := filepath.Join(p.tempDir, fmt.Sprintf("%s_original.%s", trackID, track.Extension))
-	compressedPath := filepath.Join(p.tempDir, fmt.Sprintf("%s_%s_compressed.%s", trackID, versionID, option.Format))
-
-	defer func() {
-		_ = os.Remove(originalPath)   // #nosec G104 -- Cleanup operation, errors not critical
-		_ = os.Remove(compressedPath) // #nosec G104 -- Cleanup operation, errors not critical
-	}()
+	// Download original file from GCS, unless another job on the track already has it
+	workingSet, release, err := p.acquireOriginal(ctx, track, len(options))
+	if err != nil {
+		return fmt.Errorf("download failed: %w", err)
+	}
+	defer release()
 
-	// Download original file from GCS
-	if err := p.downloadFile(ctx, track.OriginalURL, originalPath); err != nil {
-		return fmt.Errorf("download failed: %v", err)
+	if track.FileHash == "" {
+		// Tracks processed before hashing existed
+		if err := p.nostrTrackService.UpdateTrack(ctx, trackID, map[string]interface{}{"file_hash": workingSet.FileHash}); err != nil {
+			log.Printf("Warning: Failed to save file hash for track %s: %v", trackID, err)
+		}
 	}
 
 	// Validate it's a valid audio file
-	if err := p.audioProcessor.ValidateAudioFile(ctx, originalPath); err != nil {
+	if err := p.audioProcessor.ValidateAudioFile(ctx, workingSet.OriginalPath); err != nil {
 		return fmt.Errorf("invalid audio file: %v", err)
 	}
 
-	// Compress with specific options
-	if err := p.audioProcessor.CompressAudio(ctx, originalPath, compressedPath, option); err != nil {
-		return fmt.Errorf("compression failed: %v", err)
+	// Reuse the loudness measured during processing, analyzing now for tracks processed before
+	// it existed. Options that normalize loudness fail on their own if analysis fails.
+	loudness := track.Loudness
+	if loudness == nil && slices.ContainsFunc(options, func(option models.CompressionOption) bool { return option.Format != utils.HLSFormat }) {
+		if loudness, err = p.audioProcessor.AnalyzeLoudness(ctx, workingSet.OriginalPath); err != nil {
+			log.Printf("Warning: Could not analyze loudness for %s: %v", trackID, err)
+		} else if err := p.nostrTrackService.UpdateTrack(ctx, trackID, map[string]interface{}{"loudness": loudness}); err != nil {
+			log.Printf("Warning: Failed to save loudness for track %s: %v", trackID, err)
+		}
+	}
+
+	tags, cleanupTags := p.buildOutputTags(ctx, track)
+	defer cleanupTags()
+
+	var errs []error
+	for _, result := range p.createVersions(ctx, trackID, workingSet, options, utils.EncodeContext{Loudness: loudness, Tags: tags}) {
+		if result.err == nil {
+			// Add to track
+			if err := p.nostrTrackService.AddCompressionVersion(ctx, trackID, *result.version); err != nil {
+				result.err = fmt.Errorf("failed to save compression version: %v", err)
+			} else {
+				log.Printf("Successfully created compression version %s for track %s", result.version.ID, trackID)
+				continue
+			}
+		}
+		log.Printf("Warning: Failed to create compression version for track %s (option: %+v): %v", trackID, result.option, result.err)
+		errs = append(errs, result.err)
+	}
+
+	if len(options) == 1 && len(errs) == 1 {
+		return errs[0]
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to create %d of %d compression versions: %w", len(errs), len(options), errors.Join(errs...))
+	}
+	return nil
+}
+
+// versionResult is the outcome of generating one compression option
+type versionResult struct {
+	option  models.CompressionOption
+	version *models.CompressionVersion
+	err     error
+}
+
+// createVersions generates a compressed version of the working set's original for each
+// option, under new version IDs. Encoded formats share one ffmpeg run; if it fails they are
+// retried one at a time so a bad option only fails itself. Versions are private and not yet
+// saved to the track. Results are in option order.
+func (p *ProcessingService) createVersions(ctx context.Context, trackID string, workingSet *WorkingSet, options []models.CompressionOption, encode utils.EncodeContext) []versionResult {
+	results := make([]versionResult, len(options))
+	var (
+		targets    []utils.CompressTarget
+		versionIDs []string
+		indexes    []int
+	)
+	for i, option := range options {
+		results[i].option = option
+		versionID := uuid.New().String()
+		if option.Format == utils.HLSFormat {
+			results[i].version, results[i].err = p.packageHLS(ctx, trackID, versionID, workingSet, option)
+			continue
+		}
+		targets = append(targets, utils.CompressTarget{
+			OutputPath: filepath.Join(workingSet.Dir, fmt.Sprintf("%s_compressed.%s", versionID, utils.OutputExtension(option.Format))),
+			Options:    option,
+		})
+		versionIDs = append(versionIDs, versionID)
+		indexes = append(indexes, i)
+	}
+	if len(targets) == 0 {
+		return results
+	}
+	defer func() {
+		for _, target := range targets {
+			_ = os.Remove(target.OutputPath) // #nosec G104 -- Cleanup operation, errors not critical
+		}
+	}()
+
+	errs := make([]error, len(targets))
+	if err := p.audioProcessor.CompressAudioMulti(ctx, workingSet.OriginalPath, targets, encode); err != nil {
+		if len(targets) == 1 {
+			errs[0] = err
+		} else {
+			log.Printf("Warning: Combined compression for track %s failed, encoding versions one at a time: %v", trackID, err)
+			for j, target := range targets {
+				errs[j] = p.audioProcessor.CompressAudioWithContext(ctx, workingSet.OriginalPath, target.OutputPath, target.Options, encode)
+			}
+		}
+	}
+
+	for j, i := range indexes {
+		if errs[j] != nil {
+			results[i].err = fmt.Errorf("compression failed: %v", errs[j])
+			continue
+		}
+		results[i].version, results[i].err = p.uploadVersion(ctx, trackID, versionIDs[j], targets[j].OutputPath, targets[j].Options)
 	}
+	return results
+}
 
+// uploadVersion uploads a compressed file and returns its version record
+func (p *ProcessingService) uploadVersion(ctx context.Context, trackID, versionID, compressedPath string, option models.CompressionOption) (*models.CompressionVersion, error) {
 	// Get compressed file info
 	compressedInfo, err := os.Stat(compressedPath)
 	if err != nil {
-		return fmt.Errorf("failed to get compressed file info: %v", err)
+		return nil, fmt.Errorf("failed to get compressed file info: %v", err)
+	}
+	compressedHash, err := hashFile(compressedPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash compressed file: %v", err)
 	}
 
 	// Upload compressed file to GCS
-	compressedObjectName := p.pathConfig.GetCompressedVersionPath(trackID, versionID, option.Format)
+	compressedObjectName := p.pathConfig.GetCompressedVersionPath(trackID, versionID, utils.OutputExtension(option.Format))
 	compressedFile, err := os.Open(compressedPath) // #nosec G304 -- Opening controlled temp file for upload
 	if err != nil {
-		return fmt.Errorf("failed to open compressed file: %v", err)
+		return nil, fmt.Errorf("failed to open compressed file: %v", err)
 	}
 	defer compressedFile.Close()
 
 	contentType := getContentTypeForFormat(option.Format)
 	if err := p.storageService.UploadObject(ctx, compressedObjectName, compressedFile, contentType); err != nil {
-		return fmt.Errorf("failed to upload compressed file: %v", err)
+		return nil, fmt.Errorf("failed to upload compressed file: %v", err)
 	}
 
 	compressedURL := p.storageService.GetPublicURL(compressedObjectName)
 
 	// Get actual audio info from compressed file
-	actualInfo, err := p.audioProcessor.GetAudioInfo(ctx, compressedPath)
+	actualInfo, _ := p.audioProcessor.GetAudioInfo(ctx, compressedPath)
 	actualBitrate := option.Bitrate
 	actualSampleRate := option.SampleRate
 	if actualInfo != nil {
@@ -305,8 +816,7 @@ func (p *ProcessingService) ProcessCompression(ctx context.Context, trackID stri
 		actualSampleRate = actualInfo.SampleRate
 	}
 
-	// Create compression version record
-	version := models.CompressionVersion{
+	return &models.CompressionVersion{
 		ID:         versionID,
 		URL:        compressedURL,
 		Bitrate:    actualBitrate,
@@ -317,27 +827,83 @@ func (p *ProcessingService) ProcessCompression(ctx context.Context, trackID stri
 		IsPublic:   false, // Default to private, user can make public later
 		CreatedAt:  time.Now(),
 		Options:    option,
+		FileHash:   compressedHash,
+		ObjectKey:  compressedObjectName,
+	}, nil
+}
+
+// packageHLS packages the original as multi-bitrate AAC HLS, uploads the playlists and
+// segments under the track's compressed prefix, and returns the master playlist as a version
+func (p *ProcessingService) packageHLS(ctx context.Context, trackID, versionID string, workingSet *WorkingSet, option models.CompressionOption) (*models.CompressionVersion, error) {
+	hlsDir := filepath.Join(workingSet.Dir, versionID+"_hls")
+	defer func() {
+		_ = os.RemoveAll(hlsDir) // #nosec G104 -- Cleanup operation, errors not critical
+	}()
+
+	pkg, err := p.audioProcessor.PackageHLS(ctx, workingSet.OriginalPath, hlsDir, option.Renditions, option.SampleRate)
+	if err != nil {
+		return nil, fmt.Errorf("HLS packaging failed: %v", err)
 	}
 
-	// Add to track
-	if err := p.nostrTrackService.AddCompressionVersion(ctx, trackID, version); err != nil {
-		return fmt.Errorf("failed to save compression version: %v", err)
+	var totalSize int64
+	err = filepath.Walk(pkg.Dir, func(path string, info os.FileInfo, walkErr error) error {
+		if walkErr != nil || info.IsDir() {
+			return walkErr
+		}
+
+		relPath, err := filepath.Rel(pkg.Dir, path)
+		if err != nil {
+			return err
+		}
+
+		file, err := os.Open(path) // #nosec G304 -- Opening controlled temp file for upload
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", relPath, err)
+		}
+		defer file.Close()
+
+		objectName := p.pathConfig.GetHLSPath(trackID, versionID, filepath.ToSlash(relPath))
+		if err := p.storageService.UploadObject(ctx, objectName, file, utils.GetHLSContentType(path)); err != nil {
+			return fmt.Errorf("failed to upload %s: %w", relPath, err)
+		}
+
+		totalSize += info.Size()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload HLS package: %v", err)
 	}
 
-	log.Printf("Successfully created compression version %s for track %s", versionID, trackID)
-	return nil
+	renditions := make([]int, 0, len(pkg.Renditions))
+	for _, rendition := range pkg.Renditions {
+		renditions = append(renditions, rendition.Bitrate)
+	}
+
+	masterObjectName := p.pathConfig.GetHLSPath(trackID, versionID, utils.HLSMasterPlaylist)
+	version := &models.CompressionVersion{
+		ID:           versionID,
+		URL:          p.storageService.GetPublicURL(masterObjectName),
+		Bitrate:      renditions[len(renditions)-1], // Highest rendition
+		Format:       utils.HLSFormat,
+		Quality:      option.Quality,
+		SampleRate:   option.SampleRate,
+		Size:         totalSize,
+		IsPublic:     false, // Default to private, user can make public later
+		CreatedAt:    time.Now(),
+		Options:      option,
+		Renditions:   renditions,
+		ObjectKey:    masterObjectName,
+		ObjectPrefix: p.pathConfig.GetHLSPath(trackID, versionID, ""),
+	}
+
+	log.Printf("Successfully packaged HLS version %s for track %s (%d renditions)", versionID, trackID, len(renditions))
+	return version, nil
 }
 
 // getContentTypeForFormat returns the appropriate MIME type for audio formats
 func getContentTypeForFormat(format string) string {
-	switch format {
-	case "mp3":
-		return "audio/mpeg"
-	case "aac":
-		return "audio/aac"
-	case "ogg":
-		return "audio/ogg"
-	default:
-		return "audio/mpeg"
+	if format == utils.HLSFormat {
+		return "application/vnd.apple.mpegurl"
 	}
+	return utils.ContentTypeForFormat(format)
 }
\ No newline at end of file
@@ -0,0 +1,70 @@
+package services_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/wavlake/monorepo/internal/config"
+	"github.com/wavlake/monorepo/internal/models"
+	"github.com/wavlake/monorepo/internal/services"
+)
+
+var _ = Describe("Processing", func() {
+	Describe("FindDuplicate", func() {
+		now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+		var upload *models.NostrTrack
+
+		BeforeEach(func() {
+			upload = &models.NostrTrack{ID: "new-track", Pubkey: "pubkey-a", CreatedAt: now}
+		})
+
+		It("should ignore the upload itself and re-uploads by the same pubkey", func() {
+			matches := []*models.NostrTrack{
+				upload,
+				{ID: "earlier-upload", Pubkey: "pubkey-a", CreatedAt: now.Add(-time.Hour)},
+			}
+
+			for _, policy := range []string{config.DuplicatePolicyFlag, config.DuplicatePolicyReject} {
+				duplicate, reject := services.FindDuplicate(upload, matches, policy)
+				Expect(duplicate).To(BeNil())
+				Expect(reject).To(BeFalse())
+			}
+		})
+
+		It("should flag a match by another pubkey without rejecting it", func() {
+			other := &models.NostrTrack{ID: "other-track", Pubkey: "pubkey-b", CreatedAt: now.Add(-time.Hour)}
+
+			duplicate, reject := services.FindDuplicate(upload, []*models.NostrTrack{upload, other}, config.DuplicatePolicyFlag)
+			Expect(duplicate).To(Equal(other))
+			Expect(reject).To(BeFalse())
+		})
+
+		It("should reject a match by another pubkey under the reject policy", func() {
+			other := &models.NostrTrack{ID: "other-track", Pubkey: "pubkey-b", CreatedAt: now.Add(-time.Hour)}
+
+			duplicate, reject := services.FindDuplicate(upload, []*models.NostrTrack{upload, other}, config.DuplicatePolicyReject)
+			Expect(duplicate).To(Equal(other))
+			Expect(reject).To(BeTrue())
+		})
+
+		It("should choose the oldest match", func() {
+			matches := []*models.NostrTrack{
+				{ID: "newer", Pubkey: "pubkey-b", CreatedAt: now.Add(-time.Hour)},
+				{ID: "oldest", Pubkey: "pubkey-c", CreatedAt: now.Add(-48 * time.Hour)},
+				{ID: "own-older", Pubkey: "pubkey-a", CreatedAt: now.Add(-72 * time.Hour)},
+				{ID: "older", Pubkey: "pubkey-b", CreatedAt: now.Add(-24 * time.Hour)},
+			}
+
+			duplicate, _ := services.FindDuplicate(upload, matches, config.DuplicatePolicyFlag)
+			Expect(duplicate.ID).To(Equal("oldest"))
+		})
+
+		It("should find nothing without matches", func() {
+			duplicate, reject := services.FindDuplicate(upload, nil, config.DuplicatePolicyReject)
+			Expect(duplicate).To(BeNil())
+			Expect(reject).To(BeFalse())
+		})
+	})
+})
@@ -3,15 +3,21 @@ package services
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"net/url"
 	"os"
 	"time"
 
 	"cloud.google.com/go/storage"
 	"google.golang.org/api/iamcredentials/v1"
+	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 	"github.com/wavlake/monorepo/internal/config"
+	"github.com/wavlake/monorepo/internal/models"
+	"github.com/wavlake/monorepo/internal/utils"
 )
 
 type StorageService struct {
@@ -101,15 +107,34 @@ func (s *StorageService) CopyObject(ctx context.Context, srcObject, dstObject st
 	return nil
 }
 
-// DeleteObject deletes an object from storage
+// DeleteObject deletes an object from storage. Deleting an object that does not exist
+// succeeds, so deletes can be retried.
 func (s *StorageService) DeleteObject(ctx context.Context, objectName string) error {
 	obj := s.client.Bucket(s.bucketName).Object(objectName)
-	if err := obj.Delete(ctx); err != nil {
+	if err := obj.Delete(ctx); err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
 		return fmt.Errorf("failed to delete object: %w", err)
 	}
 	return nil
 }
 
+// ListObjects returns the names of every object whose name starts with prefix
+func (s *StorageService) ListObjects(ctx context.Context, prefix string) ([]string, error) {
+	it := s.client.Bucket(s.bucketName).Objects(ctx, &storage.Query{Prefix: prefix})
+
+	var names []string
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list objects: %w", err)
+		}
+		names = append(names, attrs.Name)
+	}
+	return names, nil
+}
+
 // UploadObject uploads data to storage
 func (s *StorageService) UploadObject(ctx context.Context, objectName string, data io.Reader, contentType string) error {
 	obj := s.client.Bucket(s.bucketName).Object(objectName)
@@ -148,6 +173,90 @@ func (s *StorageService) GetObjectReader(ctx context.Context, objectName string)
 	return reader, nil
 }
 
+// GetObjectRangeReader returns a reader for length bytes of an object starting at offset.
+// A negative length reads to the end of the object.
+func (s *StorageService) GetObjectRangeReader(ctx context.Context, objectName string, offset, length int64) (io.ReadCloser, error) {
+	obj := s.client.Bucket(s.bucketName).Object(objectName)
+	reader, err := obj.NewRangeReader(ctx, offset, length)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create object range reader: %w", err)
+	}
+	return reader, nil
+}
+
+// GetObjectInfo returns the size, content type, ETag and timestamps of an object
+func (s *StorageService) GetObjectInfo(ctx context.Context, objectName string) (*models.FileMetadata, error) {
+	attrs, err := s.client.Bucket(s.bucketName).Object(objectName).Attrs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object info: %w", err)
+	}
+
+	return &models.FileMetadata{
+		Name:        attrs.Name,
+		Size:        attrs.Size,
+		ContentType: attrs.ContentType,
+		Bucket:      attrs.Bucket,
+		URL:         s.GetPublicURL(attrs.Name),
+		ETag:        attrs.Etag,
+		Metadata:    attrs.Metadata,
+		CreatedAt:   attrs.Created,
+		UpdatedAt:   attrs.Updated,
+	}, nil
+}
+
+// ContentLengthRangeHeader is the GCS header that limits the size of a signed upload
+const ContentLengthRangeHeader = "x-goog-content-length-range"
+
+// ContentLengthRangeValue returns the ContentLengthRangeHeader value accepting up to maxBytes
+func ContentLengthRangeValue(maxBytes int64) string {
+	return fmt.Sprintf("0,%d", maxBytes)
+}
+
+// GenerateSignedURL creates a short-lived signed URL for an object. The method defaults to
+// GET; a ContentDisposition override is signed into the URL as response-content-disposition.
+// PUT URLs with a MaxContentLength require ContentLengthRangeHeader on the upload. Resumable
+// URLs are POSTed with utils.StartResumableUpload to open a session.
+func (s *StorageService) GenerateSignedURL(ctx context.Context, objectName string, opts models.SignedURLOptions) (string, error) {
+	serviceAccountEmail := s.serviceAccountEmail
+
+	method := opts.Method
+	if opts.Resumable {
+		method = http.MethodPost
+	} else if method == "" {
+		method = http.MethodGet
+	}
+
+	signOpts := &storage.SignedURLOptions{
+		Scheme:         storage.SigningSchemeV4,
+		Method:         method,
+		Expires:        time.Now().Add(opts.Expiration),
+		GoogleAccessID: serviceAccountEmail,
+		SignBytes: func(b []byte) ([]byte, error) {
+			return signBytes(ctx, serviceAccountEmail, b)
+		},
+	}
+	if opts.ContentDisposition != "" {
+		signOpts.QueryParameters = url.Values{"response-content-disposition": {opts.ContentDisposition}}
+	}
+	if method == http.MethodPut || opts.Resumable {
+		signOpts.Headers = []string{"Content-Type"}
+		if opts.Resumable {
+			signOpts.Headers = append(signOpts.Headers, utils.ResumableHeader+":"+utils.ResumableStart)
+		}
+		if opts.MaxContentLength > 0 {
+			// GCS rejects uploads outside the range; the client must send the header as signed
+			signOpts.Headers = append(signOpts.Headers, ContentLengthRangeHeader+":"+ContentLengthRangeValue(opts.MaxContentLength))
+		}
+	}
+
+	signedURL, err := s.client.Bucket(s.bucketName).SignedURL(objectName, signOpts)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate signed URL: %w", err)
+	}
+
+	return signedURL, nil
+}
+
 // signBytes uses the Service Account Credentials API to sign bytes with the service account
 func signBytes(ctx context.Context, serviceAccountEmail string, bytesToSign []byte) ([]byte, error) {
 	// Create IAM Credentials service client
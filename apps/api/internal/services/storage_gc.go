@@ -0,0 +1,234 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"cloud.google.com/go/storage"
+	"github.com/wavlake/monorepo/internal/models"
+	"github.com/wavlake/monorepo/internal/utils"
+	"google.golang.org/api/iterator"
+)
+
+// Outcomes reported for orphans and abandoned tracks in models.StorageGCReport
+const (
+	GCStatusDeleted       = "deleted"
+	GCStatusWouldDelete   = "would_delete"
+	GCStatusInGracePeriod = "in_grace_period"
+	GCStatusFailed        = "failed"
+)
+
+// DefaultGCGracePeriod is how old an orphan or abandoned track must be before it is deleted.
+// It outlives resumable upload sessions, so uploads still in progress are never collected.
+const DefaultGCGracePeriod = ResumableUploadTTL + 24*time.Hour
+
+// StorageGCService finds and deletes track files no Firestore record references, and track
+// records whose original was never uploaded
+type StorageGCService struct {
+	firestoreClient *firestore.Client
+	storageService  StorageServiceInterface
+	pathConfig      *utils.StoragePathConfig
+}
+
+func NewStorageGCService(firestoreClient *firestore.Client, storageService StorageServiceInterface) *StorageGCService {
+	return &StorageGCService{
+		firestoreClient: firestoreClient,
+		storageService:  storageService,
+		pathConfig:      utils.GetStoragePathConfig(),
+	}
+}
+
+// CollectGarbage lists the original and compressed track prefixes and reports every object
+// whose track does not exist, and every track whose upload was abandoned. Both are deleted once
+// older than gracePeriod; younger ones are only reported. Tracks are loaded before objects are
+// listed, so a file uploaded for a track created mid-run is at most as old as the run and stays
+// within the grace period. With dryRun set nothing is deleted.
+func (s *StorageGCService) CollectGarbage(ctx context.Context, gracePeriod time.Duration, dryRun bool) (*models.StorageGCReport, error) {
+	now := time.Now()
+	report := &models.StorageGCReport{
+		Cutoff:          now.Add(-gracePeriod),
+		DryRun:          dryRun,
+		Orphans:         []models.StorageOrphan{},
+		AbandonedTracks: []models.AbandonedTrack{},
+	}
+
+	trackIDs, candidates, err := s.loadTracks(ctx, report.Cutoff, now)
+	if err != nil {
+		return nil, err
+	}
+	report.TracksScanned = len(trackIDs)
+
+	for _, doc := range candidates {
+		result, ok := s.collectAbandonedTrack(ctx, doc, dryRun)
+		if !ok {
+			continue
+		}
+		countGCOutcome(report, result.Status)
+		report.AbandonedTracks = append(report.AbandonedTracks, result)
+	}
+
+	for _, prefix := range []string{s.pathConfig.OriginalPrefix + "/", s.pathConfig.CompressedPrefix + "/"} {
+		objectNames, err := s.storageService.ListObjects(ctx, prefix)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", prefix, err)
+		}
+		report.ObjectsScanned += len(objectNames)
+
+		for _, objectName := range OrphanedObjects(s.pathConfig, objectNames, trackIDs) {
+			result := s.collectOrphan(ctx, objectName, report.Cutoff, dryRun)
+			countGCOutcome(report, result.Status)
+			report.Orphans = append(report.Orphans, result)
+		}
+	}
+
+	log.Printf("Storage GC (dry run: %t): %d tracks and %d objects scanned, %d orphans, %d abandoned tracks, %d deleted, %d failed",
+		dryRun, report.TracksScanned, report.ObjectsScanned, len(report.Orphans), len(report.AbandonedTracks), report.Deleted, report.Failed)
+	return report, nil
+}
+
+// OrphanedObjects returns the objects whose track ID, as parsed by GetTrackIDFromPath, is not
+// in trackIDs
+func OrphanedObjects(pathConfig *utils.StoragePathConfig, objectNames []string, trackIDs map[string]bool) []string {
+	var orphans []string
+	for _, objectName := range objectNames {
+		if !trackIDs[pathConfig.GetTrackIDFromPath(objectName)] {
+			orphans = append(orphans, objectName)
+		}
+	}
+	return orphans
+}
+
+// IsAbandonedUpload reports whether a track was created before cutoff and is still waiting
+// for its original, or was marked UploadStateExpired by the upload reaper, with no resumable
+// session left open at now. The caller must still confirm the original is missing from storage.
+func IsAbandonedUpload(track *models.NostrTrack, cutoff, now time.Time) bool {
+	if track.Deleted || track.UploadState == models.UploadStateComplete {
+		return false
+	}
+	if !track.IsProcessing && track.UploadState != models.UploadStateExpired {
+		return false
+	}
+	if !track.CreatedAt.Before(cutoff) {
+		return false
+	}
+	return track.UploadSession == nil || track.UploadSession.ExpiresAt.Before(now)
+}
+
+// loadTracks returns the ID of every track, including soft-deleted ones, and the documents of
+// tracks that look abandoned
+func (s *StorageGCService) loadTracks(ctx context.Context, cutoff, now time.Time) (map[string]bool, []*firestore.DocumentSnapshot, error) {
+	iter := s.firestoreClient.Collection("nostr_tracks").Documents(ctx)
+	defer iter.Stop()
+
+	trackIDs := make(map[string]bool)
+	var candidates []*firestore.DocumentSnapshot
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to iterate tracks: %w", err)
+		}
+		trackIDs[doc.Ref.ID] = true
+
+		var track models.NostrTrack
+		if err := doc.DataTo(&track); err != nil {
+			log.Printf("Failed to decode track %s, keeping its files: %v", doc.Ref.ID, err)
+			continue
+		}
+		if IsAbandonedUpload(&track, cutoff, now) {
+			candidates = append(candidates, doc)
+		}
+	}
+	return trackIDs, candidates, nil
+}
+
+// collectAbandonedTrack deletes the record of a track whose original is missing from storage.
+// ok is false when the original turns out to exist. The delete is skipped if the record changed
+// since it was read, so an upload finishing mid-run keeps its track.
+func (s *StorageGCService) collectAbandonedTrack(ctx context.Context, doc *firestore.DocumentSnapshot, dryRun bool) (result models.AbandonedTrack, ok bool) {
+	var track models.NostrTrack
+	if err := doc.DataTo(&track); err != nil {
+		return result, false
+	}
+	result = models.AbandonedTrack{
+		TrackID:     doc.Ref.ID,
+		Pubkey:      track.Pubkey,
+		UploadState: track.UploadState,
+		CreatedAt:   track.CreatedAt,
+	}
+
+	if objectName := OriginalObjectKey(s.storageService, &track); objectName != "" {
+		_, err := s.storageService.GetObjectInfo(ctx, objectName)
+		if err == nil {
+			return result, false
+		}
+		if !errors.Is(err, storage.ErrObjectNotExist) {
+			result.Status = GCStatusFailed
+			result.Message = fmt.Sprintf("failed to check original: %v", err)
+			return result, true
+		}
+	}
+
+	if dryRun {
+		result.Status = GCStatusWouldDelete
+		return result, true
+	}
+
+	if _, err := doc.Ref.Delete(ctx, firestore.LastUpdateTime(doc.UpdateTime)); err != nil {
+		result.Status = GCStatusFailed
+		result.Message = fmt.Sprintf("failed to delete track: %v", err)
+		return result, true
+	}
+
+	log.Printf("Deleted abandoned track %s", result.TrackID)
+	result.Status = GCStatusDeleted
+	return result, true
+}
+
+// collectOrphan deletes an orphaned object once it is older than cutoff
+func (s *StorageGCService) collectOrphan(ctx context.Context, objectName string, cutoff time.Time, dryRun bool) models.StorageOrphan {
+	result := models.StorageOrphan{
+		ObjectName: objectName,
+		TrackID:    s.pathConfig.GetTrackIDFromPath(objectName),
+	}
+
+	info, err := s.storageService.GetObjectInfo(ctx, objectName)
+	if err != nil {
+		result.Status = GCStatusFailed
+		result.Message = err.Error()
+		return result
+	}
+	result.Size = info.Size
+	result.CreatedAt = info.CreatedAt
+
+	switch {
+	case !info.CreatedAt.Before(cutoff):
+		result.Status = GCStatusInGracePeriod
+	case dryRun:
+		result.Status = GCStatusWouldDelete
+	default:
+		if err := s.storageService.DeleteObject(ctx, objectName); err != nil {
+			result.Status = GCStatusFailed
+			result.Message = err.Error()
+		} else {
+			result.Status = GCStatusDeleted
+		}
+	}
+	return result
+}
+
+// countGCOutcome adds an outcome to the report totals
+func countGCOutcome(report *models.StorageGCReport, status string) {
+	switch status {
+	case GCStatusDeleted, GCStatusWouldDelete:
+		report.Deleted++
+	case GCStatusFailed:
+		report.Failed++
+	}
+}
@@ -0,0 +1,94 @@
+package services_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/wavlake/monorepo/internal/models"
+	"github.com/wavlake/monorepo/internal/services"
+	"github.com/wavlake/monorepo/internal/utils"
+)
+
+var _ = Describe("Storage GC", func() {
+	Describe("OrphanedObjects", func() {
+		It("should keep every file of an existing track", func() {
+			objectNames := []string{
+				"tracks/original/track-1.wav",
+				"tracks/compressed/track-1.mp3",
+				"tracks/compressed/track-1_v1.mp3",
+				"tracks/compressed/track-1_v2/master.m3u8",
+				"tracks/compressed/track-1_v2/128k/segment_000.ts",
+				"tracks/compressed/track-1_waveform_256.json",
+				"tracks/original/track-2.flac",
+				"tracks/compressed/track-2_v1/master.m3u8",
+			}
+			trackIDs := map[string]bool{"track-1": true}
+
+			Expect(services.OrphanedObjects(utils.GetStoragePathConfig(), objectNames, trackIDs)).To(Equal([]string{
+				"tracks/original/track-2.flac",
+				"tracks/compressed/track-2_v1/master.m3u8",
+			}))
+		})
+	})
+
+	Describe("IsAbandonedUpload", func() {
+		now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+		cutoff := now.Add(-services.DefaultGCGracePeriod)
+
+		pendingTrack := func() *models.NostrTrack {
+			return &models.NostrTrack{
+				ID:           "track-1",
+				IsProcessing: true,
+				UploadState:  models.UploadStatePending,
+				CreatedAt:    cutoff.Add(-time.Hour),
+			}
+		}
+
+		It("should flag old tracks still waiting for their original", func() {
+			Expect(services.IsAbandonedUpload(pendingTrack(), cutoff, now)).To(BeTrue())
+
+			track := pendingTrack()
+			track.UploadState = ""
+			Expect(services.IsAbandonedUpload(track, cutoff, now)).To(BeTrue())
+		})
+
+		It("should flag old tracks the upload reaper marked expired", func() {
+			track := pendingTrack()
+			track.UploadState = models.UploadStateExpired
+			track.IsProcessing = false
+			Expect(services.IsAbandonedUpload(track, cutoff, now)).To(BeTrue())
+		})
+
+		It("should keep tracks inside the grace period", func() {
+			track := pendingTrack()
+			track.CreatedAt = cutoff.Add(time.Hour)
+			Expect(services.IsAbandonedUpload(track, cutoff, now)).To(BeFalse())
+		})
+
+		It("should keep tracks with an open resumable session", func() {
+			track := pendingTrack()
+			track.UploadState = models.UploadStateUploading
+			track.UploadSession = &models.UploadSession{ExpiresAt: now.Add(time.Hour)}
+			Expect(services.IsAbandonedUpload(track, cutoff, now)).To(BeFalse())
+
+			track.UploadSession.ExpiresAt = now.Add(-time.Hour)
+			Expect(services.IsAbandonedUpload(track, cutoff, now)).To(BeTrue())
+		})
+
+		It("should keep uploaded, processed and deleted tracks", func() {
+			track := pendingTrack()
+			track.UploadState = models.UploadStateComplete
+			Expect(services.IsAbandonedUpload(track, cutoff, now)).To(BeFalse())
+
+			track = pendingTrack()
+			track.IsProcessing = false
+			Expect(services.IsAbandonedUpload(track, cutoff, now)).To(BeFalse())
+
+			track = pendingTrack()
+			track.Deleted = true
+			Expect(services.IsAbandonedUpload(track, cutoff, now)).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/wavlake/monorepo/internal/models"
+	"google.golang.org/api/iterator"
+)
+
+// Per-track purge outcomes reported in models.TrackPurgeResult
+const (
+	PurgeStatusPurged     = "purged"
+	PurgeStatusWouldPurge = "would_purge"
+	PurgeStatusFailed     = "failed"
+)
+
+// DefaultPurgeRetention is how long soft-deleted tracks are kept before they are purged
+const DefaultPurgeRetention = 30 * 24 * time.Hour
+
+// PurgeDeletedTracks hard deletes every track soft deleted more than retention ago. Tracks
+// deleted before deleted_at was recorded use their last update instead. A track whose files
+// cannot all be deleted is reported as failed and kept, so the next purge retries it. With
+// dryRun set nothing is deleted.
+func (s *NostrTrackService) PurgeDeletedTracks(ctx context.Context, retention time.Duration, dryRun bool) (*models.TrackPurgeReport, error) {
+	cutoff := time.Now().Add(-retention)
+
+	iter := s.firestoreClient.Collection("nostr_tracks").
+		Where("deleted", "==", true).
+		Documents(ctx)
+	defer iter.Stop()
+
+	report := &models.TrackPurgeReport{
+		Cutoff:  cutoff,
+		DryRun:  dryRun,
+		Results: []models.TrackPurgeResult{},
+	}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate deleted tracks: %w", err)
+		}
+
+		var track models.NostrTrack
+		if err := doc.DataTo(&track); err != nil {
+			report.Failed++
+			report.Results = append(report.Results, models.TrackPurgeResult{
+				TrackID: doc.Ref.ID,
+				Status:  PurgeStatusFailed,
+				Message: fmt.Sprintf("failed to decode track: %v", err),
+			})
+			continue
+		}
+		track.ID = doc.Ref.ID
+
+		deletedAt := track.UpdatedAt
+		if track.DeletedAt != nil {
+			deletedAt = *track.DeletedAt
+		}
+		if !deletedAt.Before(cutoff) {
+			continue
+		}
+
+		result := models.TrackPurgeResult{TrackID: track.ID, DeletedAt: deletedAt}
+		if dryRun {
+			result.Status = PurgeStatusWouldPurge
+			report.Purged++
+			report.Results = append(report.Results, result)
+			continue
+		}
+
+		failed, err := s.hardDelete(ctx, &track)
+		switch {
+		case err != nil:
+			result.Status = PurgeStatusFailed
+			result.Message = err.Error()
+		case len(failed) > 0:
+			result.Status = PurgeStatusFailed
+			result.FailedFiles = failed
+			result.Message = ErrIncompleteDelete.Error()
+		default:
+			result.Status = PurgeStatusPurged
+		}
+
+		if result.Status == PurgeStatusFailed {
+			report.Failed++
+		} else {
+			report.Purged++
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	log.Printf("Track purge before %s (dry run: %t): %d purged, %d failed",
+		cutoff.Format(time.RFC3339), dryRun, report.Purged, report.Failed)
+	return report, nil
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"cloud.google.com/go/storage"
+	"github.com/wavlake/monorepo/internal/models"
+	"google.golang.org/api/iterator"
+)
+
+// UploadExpired reports whether the upload URL or resumable session of a track still waiting
+// for its original had expired at now. Tracks created before upload expiry was recorded fall
+// back to UploadURLExpiry after creation. Tracks created before upload tracking have no upload
+// state and count as waiting while they are still processing.
+func UploadExpired(track *models.NostrTrack, now time.Time) bool {
+	switch track.UploadState {
+	case models.UploadStatePending, models.UploadStateUploading:
+	case "":
+		if !track.IsProcessing {
+			return false
+		}
+	default:
+		return false
+	}
+
+	switch {
+	case track.UploadSession != nil:
+		return track.UploadSession.ExpiresAt.Before(now)
+	case track.UploadExpiresAt != nil:
+		return track.UploadExpiresAt.Before(now)
+	default:
+		return track.CreatedAt.Add(UploadURLExpiry).Before(now)
+	}
+}
+
+// ReapExpiredUploads marks tracks whose upload expired without the original arriving as
+// UploadStateExpired and no longer processing, and returns how many were marked. Tracks
+// created before upload tracking are found among those still processing. A track is skipped
+// when its original exists or its record changed since it was read, so an upload finishing
+// mid-run keeps its state.
+func (s *NostrTrackService) ReapExpiredUploads(ctx context.Context) (int, error) {
+	tracks := s.firestoreClient.Collection("nostr_tracks")
+	queries := []firestore.Query{
+		tracks.Where("upload_state", "in", []string{models.UploadStatePending, models.UploadStateUploading}),
+		tracks.Where("is_processing", "==", true),
+	}
+
+	now := time.Now()
+	reaped := 0
+	for _, query := range queries {
+		n, err := s.reapExpiredUploads(ctx, query, now)
+		reaped += n
+		if err != nil {
+			return reaped, err
+		}
+	}
+
+	if reaped > 0 {
+		log.Printf("Marked %d abandoned uploads as expired", reaped)
+	}
+	return reaped, nil
+}
+
+// reapExpiredUploads marks the expired uploads among the tracks a query returns
+func (s *NostrTrackService) reapExpiredUploads(ctx context.Context, query firestore.Query, now time.Time) (int, error) {
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	reaped := 0
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return reaped, fmt.Errorf("failed to iterate pending uploads: %w", err)
+		}
+
+		var track models.NostrTrack
+		if err := doc.DataTo(&track); err != nil {
+			log.Printf("Failed to decode track %s: %v", doc.Ref.ID, err)
+			continue
+		}
+		if track.Deleted || !UploadExpired(&track, now) {
+			continue
+		}
+
+		if objectName := OriginalObjectKey(s.storageService, &track); objectName != "" {
+			_, err := s.storageService.GetObjectInfo(ctx, objectName)
+			if err == nil {
+				continue
+			}
+			if !errors.Is(err, storage.ErrObjectNotExist) {
+				log.Printf("Failed to check original of track %s: %v", doc.Ref.ID, err)
+				continue
+			}
+		}
+
+		if _, err := doc.Ref.Update(ctx, []firestore.Update{
+			{Path: "upload_state", Value: models.UploadStateExpired},
+			{Path: "is_processing", Value: false},
+			{Path: "updated_at", Value: now},
+		}, firestore.LastUpdateTime(doc.UpdateTime)); err != nil {
+			log.Printf("Failed to mark upload of track %s expired: %v", doc.Ref.ID, err)
+			continue
+		}
+		reaped++
+	}
+	return reaped, nil
+}
+
+// RunUploadReaper calls ReapExpiredUploads every interval until ctx is done
+func (s *NostrTrackService) RunUploadReaper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.ReapExpiredUploads(ctx); err != nil {
+				log.Printf("Upload reaper failed: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package services_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/wavlake/monorepo/internal/models"
+	"github.com/wavlake/monorepo/internal/services"
+)
+
+var _ = Describe("Upload reaper", func() {
+	Describe("UploadExpired", func() {
+		now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+		past := now.Add(-time.Minute)
+		future := now.Add(time.Minute)
+
+		It("should use the one-shot URL expiry", func() {
+			track := &models.NostrTrack{UploadState: models.UploadStatePending, CreatedAt: past, UploadExpiresAt: &past}
+			Expect(services.UploadExpired(track, now)).To(BeTrue())
+
+			track.UploadExpiresAt = &future
+			Expect(services.UploadExpired(track, now)).To(BeFalse())
+		})
+
+		It("should use the resumable session expiry", func() {
+			track := &models.NostrTrack{
+				UploadState:   models.UploadStateUploading,
+				CreatedAt:     now.Add(-services.ResumableUploadTTL),
+				UploadSession: &models.UploadSession{ExpiresAt: future},
+			}
+			Expect(services.UploadExpired(track, now)).To(BeFalse())
+
+			track.UploadSession.ExpiresAt = past
+			Expect(services.UploadExpired(track, now)).To(BeTrue())
+		})
+
+		It("should fall back to the creation time", func() {
+			track := &models.NostrTrack{UploadState: models.UploadStatePending, CreatedAt: now.Add(-services.UploadURLExpiry - time.Minute)}
+			Expect(services.UploadExpired(track, now)).To(BeTrue())
+
+			track.CreatedAt = now.Add(-services.UploadURLExpiry + time.Minute)
+			Expect(services.UploadExpired(track, now)).To(BeFalse())
+		})
+
+		It("should treat tracks without an upload state as waiting while processing", func() {
+			track := &models.NostrTrack{IsProcessing: true, CreatedAt: now.Add(-services.UploadURLExpiry - time.Minute)}
+			Expect(services.UploadExpired(track, now)).To(BeTrue())
+
+			track.CreatedAt = now.Add(-services.UploadURLExpiry + time.Minute)
+			Expect(services.UploadExpired(track, now)).To(BeFalse())
+
+			track.CreatedAt = now.Add(-24 * time.Hour)
+			track.IsProcessing = false
+			Expect(services.UploadExpired(track, now)).To(BeFalse())
+		})
+
+		It("should ignore completed and already expired uploads", func() {
+			for _, state := range []string{models.UploadStateComplete, models.UploadStateExpired} {
+				track := &models.NostrTrack{UploadState: state, CreatedAt: now.Add(-24 * time.Hour)}
+				Expect(services.UploadExpired(track, now)).To(BeFalse())
+			}
+		})
+	})
+})
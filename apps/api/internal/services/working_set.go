@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// WorkingSet is a track's downloaded original, shared by the jobs processing it. Jobs write
+// their outputs into Dir under names of their own, such as the version ID.
+type WorkingSet struct {
+	TrackID      string
+	Dir          string // Private directory removed when the last job releases the set
+	OriginalPath string
+	FileHash     string // Hex SHA-256 of the original
+}
+
+// FetchOriginal downloads a track's original to path and returns its hex SHA-256
+type FetchOriginal func(ctx context.Context, path string) (string, error)
+
+// WorkingSetCache downloads each track's original once and shares it between concurrent
+// jobs on the track, removing it once the last one is done
+type WorkingSetCache struct {
+	dir     string
+	mu      sync.Mutex
+	entries map[string]*workingSetEntry
+}
+
+type workingSetEntry struct {
+	ready chan struct{} // Closed once the download finishes
+	set   *WorkingSet
+	err   error
+	refs  int
+}
+
+// NewWorkingSetCache creates a cache that keeps working sets under dir
+func NewWorkingSetCache(dir string) *WorkingSetCache {
+	return &WorkingSetCache{
+		dir:     dir,
+		entries: make(map[string]*workingSetEntry),
+	}
+}
+
+// Acquire returns the working set for a track, calling fetch to download the original if no
+// other job holds it. Every successful Acquire must be paired with a call to release. A
+// failed download is not cached, so the next Acquire tries again.
+func (c *WorkingSetCache) Acquire(ctx context.Context, trackID, extension string, fetch FetchOriginal) (*WorkingSet, func(), error) {
+	c.mu.Lock()
+	entry, ok := c.entries[trackID]
+	if ok {
+		entry.refs++
+		c.mu.Unlock()
+
+		select {
+		case <-entry.ready:
+		case <-ctx.Done():
+			c.release(trackID, entry)
+			return nil, nil, ctx.Err()
+		}
+		if entry.err != nil {
+			c.release(trackID, entry)
+			return nil, nil, entry.err
+		}
+		return entry.set, c.releaser(trackID, entry), nil
+	}
+
+	entry = &workingSetEntry{ready: make(chan struct{}), refs: 1}
+	c.entries[trackID] = entry
+	c.mu.Unlock()
+
+	entry.set, entry.err = c.download(ctx, trackID, extension, fetch)
+	if entry.err != nil {
+		// Drop the entry now so later jobs retry instead of sharing the failure
+		c.mu.Lock()
+		if c.entries[trackID] == entry {
+			delete(c.entries, trackID)
+		}
+		c.mu.Unlock()
+	}
+	close(entry.ready)
+
+	if entry.err != nil {
+		c.release(trackID, entry)
+		return nil, nil, entry.err
+	}
+	return entry.set, c.releaser(trackID, entry), nil
+}
+
+// download fetches a track's original into a new private directory
+func (c *WorkingSetCache) download(ctx context.Context, trackID, extension string, fetch FetchOriginal) (*WorkingSet, error) {
+	dir, err := os.MkdirTemp(c.dir, trackID+"_*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create working directory: %w", err)
+	}
+
+	set := &WorkingSet{
+		TrackID:      trackID,
+		Dir:          dir,
+		OriginalPath: filepath.Join(dir, "original."+extension),
+	}
+	if set.FileHash, err = fetch(ctx, set.OriginalPath); err != nil {
+		_ = os.RemoveAll(dir) // #nosec G104 -- Cleanup operation, errors not critical
+		return nil, err
+	}
+	return set, nil
+}
+
+// releaser returns a release func that only takes effect once
+func (c *WorkingSetCache) releaser(trackID string, entry *workingSetEntry) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() { c.release(trackID, entry) })
+	}
+}
+
+// release drops a reference, removing the working set with the last one
+func (c *WorkingSetCache) release(trackID string, entry *workingSetEntry) {
+	c.mu.Lock()
+	entry.refs--
+	last := entry.refs == 0
+	if last && c.entries[trackID] == entry {
+		delete(c.entries, trackID)
+	}
+	c.mu.Unlock()
+
+	if last && entry.set != nil {
+		_ = os.RemoveAll(entry.set.Dir) // #nosec G104 -- Cleanup operation, errors not critical
+	}
+}
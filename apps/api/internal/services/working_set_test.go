@@ -0,0 +1,96 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/wavlake/monorepo/internal/services"
+)
+
+var _ = Describe("WorkingSetCache", func() {
+	var (
+		cache     *services.WorkingSetCache
+		dir       string
+		ctx       context.Context
+		downloads atomic.Int32
+		fetch     services.FetchOriginal
+	)
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		cache = services.NewWorkingSetCache(dir)
+		ctx = context.Background()
+		downloads.Store(0)
+		fetch = func(ctx context.Context, path string) (string, error) {
+			downloads.Add(1)
+			return "hash", os.WriteFile(path, []byte("audio"), 0644)
+		}
+	})
+
+	It("should download once for concurrent jobs and remove the set after the last release", func() {
+		var wg sync.WaitGroup
+		sets := make([]*services.WorkingSet, 5)
+		releases := make([]func(), 5)
+		for i := range sets {
+			wg.Add(1)
+			go func(i int) {
+				defer GinkgoRecover()
+				defer wg.Done()
+				set, release, err := cache.Acquire(ctx, "track-1", "wav", fetch)
+				Expect(err).NotTo(HaveOccurred())
+				sets[i], releases[i] = set, release
+			}(i)
+		}
+		wg.Wait()
+
+		Expect(downloads.Load()).To(Equal(int32(1)))
+		for _, set := range sets {
+			Expect(set).To(BeIdenticalTo(sets[0]))
+		}
+		Expect(sets[0].FileHash).To(Equal("hash"))
+		Expect(sets[0].OriginalPath).To(BeAnExistingFile())
+
+		for _, release := range releases[1:] {
+			release()
+		}
+		releases[1]() // Releasing twice has no effect
+		Expect(sets[0].OriginalPath).To(BeAnExistingFile())
+
+		releases[0]()
+		Expect(sets[0].Dir).NotTo(BeADirectory())
+	})
+
+	It("should give each track its own directory", func() {
+		first, releaseFirst, err := cache.Acquire(ctx, "track-1", "wav", fetch)
+		Expect(err).NotTo(HaveOccurred())
+		defer releaseFirst()
+		second, releaseSecond, err := cache.Acquire(ctx, "track-2", "wav", fetch)
+		Expect(err).NotTo(HaveOccurred())
+		defer releaseSecond()
+
+		Expect(first.Dir).NotTo(Equal(second.Dir))
+		Expect(downloads.Load()).To(Equal(int32(2)))
+	})
+
+	It("should download again after a failed download", func() {
+		_, _, err := cache.Acquire(ctx, "track-1", "wav", func(ctx context.Context, path string) (string, error) {
+			return "", errors.New("storage unavailable")
+		})
+		Expect(err).To(MatchError("storage unavailable"))
+
+		set, release, err := cache.Acquire(ctx, "track-1", "wav", fetch)
+		Expect(err).NotTo(HaveOccurred())
+		defer release()
+		Expect(set.OriginalPath).To(BeAnExistingFile())
+
+		entries, err := os.ReadDir(dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entries).To(HaveLen(1))
+	})
+})
@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -15,13 +16,19 @@ import (
 
 // AudioProcessor handles audio file processing and compression
 type AudioProcessor struct {
-	tempDir string
+	tempDir  string
+	executor *Executor
 }
 
-// NewAudioProcessor creates a new audio processor
-func NewAudioProcessor(tempDir string) *AudioProcessor {
+// NewAudioProcessor creates a new audio processor. A nil executor runs tools with the
+// default sandbox limits.
+func NewAudioProcessor(tempDir string, executor *Executor) *AudioProcessor {
+	if executor == nil {
+		executor = NewExecutor(tempDir, DefaultExecLimits())
+	}
 	return &AudioProcessor{
-		tempDir: tempDir,
+		tempDir:  tempDir,
+		executor: executor,
 	}
 }
 
@@ -36,70 +43,12 @@ type AudioInfo struct {
 
 // GetAudioInfo extracts metadata from an audio file using ffprobe
 func (ap *AudioProcessor) GetAudioInfo(ctx context.Context, inputPath string) (*AudioInfo, error) {
-	cmd := exec.CommandContext(ctx, "ffprobe",
-		"-v", "quiet",
-		"-show_entries", "format=duration,size,bit_rate",
-		"-show_entries", "stream=sample_rate,channels",
-		"-of", "csv=p=0",
-		inputPath)
-
-	output, err := cmd.Output()
+	probe, err := ap.Probe(ctx, inputPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get audio info: %w", err)
 	}
 
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	if len(lines) < 2 {
-		return nil, fmt.Errorf("unexpected ffprobe output format")
-	}
-
-	// Parse format info (duration, size, bit_rate)
-	formatParts := strings.Split(lines[0], ",")
-	if len(formatParts) < 3 {
-		return nil, fmt.Errorf("unexpected format info format")
-	}
-
-	duration, err := strconv.ParseFloat(formatParts[0], 64)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse duration: %w", err)
-	}
-
-	size, err := strconv.ParseInt(formatParts[1], 10, 64)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse size: %w", err)
-	}
-
-	bitrate, err := strconv.ParseInt(formatParts[2], 10, 64)
-	if err != nil {
-		// Bitrate might be N/A, calculate from size and duration
-		if duration > 0 && size > 0 {
-			bitrate = int64((float64(size) * 8) / (duration * 1000))
-		}
-	}
-
-	// Parse stream info (sample_rate, channels)
-	streamParts := strings.Split(lines[1], ",")
-	if len(streamParts) < 2 {
-		return nil, fmt.Errorf("unexpected stream info format")
-	}
-
-	sampleRate, err := strconv.Atoi(streamParts[0])
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse sample rate: %w", err)
-	}
-
-	channels, err := strconv.Atoi(streamParts[1])
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse channels: %w", err)
-	}
-
-	return &AudioInfo{
-		Duration:   int(duration),
-		Size:       size,
-		Bitrate:    int(bitrate),
-		SampleRate: sampleRate,
-		Channels:   channels,
-	}, nil
+	return probe.AudioInfo()
 }
 
 // CompressAudio compresses audio with user-specified options (interface method)
@@ -117,7 +66,7 @@ func (ap *AudioProcessor) CompressAudioDefault(ctx context.Context, inputPath, o
 	}
 
 	// Use ffmpeg to compress the audio
-	cmd := exec.CommandContext(ctx, "ffmpeg",
+	result, err := ap.executor.Run(ctx, "ffmpeg",
 		"-i", inputPath,
 		"-codec:a", "libmp3lame", // Use LAME MP3 encoder
 		"-b:a", "128k", // 128 kbps bitrate
@@ -126,10 +75,8 @@ func (ap *AudioProcessor) CompressAudioDefault(ctx context.Context, inputPath, o
 		"-f", "mp3", // Output format
 		"-y", // Overwrite output file
 		outputPath)
-
-	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("failed to compress audio: %w, output: %s", err, string(output))
+		return fmt.Errorf("failed to compress audio: %w, output: %s", err, result.Stderr)
 	}
 
 	log.Printf("Successfully compressed audio: %s -> %s", inputPath, outputPath)
@@ -172,19 +119,12 @@ func (ap *AudioProcessor) DownloadAndCompress(ctx context.Context, sourceURL, ou
 
 // ValidateAudioFile checks if a file is a valid audio file
 func (ap *AudioProcessor) ValidateAudioFile(ctx context.Context, filePath string) error {
-	cmd := exec.CommandContext(ctx, "ffprobe",
-		"-v", "error",
-		"-select_streams", "a:0",
-		"-show_entries", "stream=codec_type",
-		"-of", "csv=p=0",
-		filePath)
-
-	output, err := cmd.Output()
+	probe, err := ap.Probe(ctx, filePath)
 	if err != nil {
 		return fmt.Errorf("file is not a valid audio file: %w", err)
 	}
 
-	if strings.TrimSpace(string(output)) != "audio" {
+	if probe.AudioStream() == nil {
 		return fmt.Errorf("file does not contain audio stream")
 	}
 
@@ -211,134 +151,218 @@ func (ap *AudioProcessor) IsFormatSupported(extension string) bool {
 
 // CompressAudioWithOptions compresses audio with specific user-defined options
 func (ap *AudioProcessor) CompressAudioWithOptions(ctx context.Context, inputPath, outputPath string, options models.CompressionOption) error {
-	log.Printf("Compressing audio with options: %+v", options)
+	return ap.CompressAudioWithContext(ctx, inputPath, outputPath, options, EncodeContext{})
+}
 
-	// Build ffmpeg command based on format and options
-	args := []string{
-		"-i", inputPath,
-		"-y", // Overwrite output file
-	}
+// CompressAudioWithContext compresses audio using per-track data. encode.Loudness drives the
+// second loudnorm pass when options.Loudnorm is set and the ReplayGain tags written to the
+// output; when nil and loudnorm is requested the input is analyzed first. encode.Tags replaces
+// the original's tags with the track record's metadata and cover art.
+func (ap *AudioProcessor) CompressAudioWithContext(ctx context.Context, inputPath, outputPath string, options models.CompressionOption, encode EncodeContext) error {
+	return ap.CompressAudioMulti(ctx, inputPath, []CompressTarget{{OutputPath: outputPath, Options: options}}, encode)
+}
 
-	// Add format-specific encoding options
-	switch options.Format {
-	case "mp3":
-		args = append(args, "-c:a", "libmp3lame")
-		args = append(args, "-b:a", fmt.Sprintf("%dk", options.Bitrate))
-	case "aac":
-		args = append(args, "-c:a", "aac")
-		args = append(args, "-b:a", fmt.Sprintf("%dk", options.Bitrate))
-	case "ogg":
-		args = append(args, "-c:a", "libvorbis")
-		args = append(args, "-b:a", fmt.Sprintf("%dk", options.Bitrate))
-	default:
-		return fmt.Errorf("unsupported format: %s", options.Format)
+// CompressTarget is one output of a multi-output compression
+type CompressTarget struct {
+	OutputPath string
+	Options    models.CompressionOption
+}
+
+// CompressAudioMulti encodes every target in a single ffmpeg run, so the input is read and
+// decoded once. Each target is encoded as CompressAudioWithContext would. If the run fails no
+// output is usable.
+func (ap *AudioProcessor) CompressAudioMulti(ctx context.Context, inputPath string, targets []CompressTarget, encode EncodeContext) error {
+	if len(targets) == 0 {
+		return nil
+	}
+	for _, target := range targets {
+		log.Printf("Compressing audio with options: %+v", target.Options)
+		if err := ValidateCompressionOption(target.Options); err != nil {
+			return fmt.Errorf("invalid compression options: %w", err)
+		}
 	}
 
-	// Add sample rate if specified
-	if options.SampleRate > 0 {
-		args = append(args, "-ar", fmt.Sprintf("%d", options.SampleRate))
+	// Analyze once for every target that normalizes loudness
+	measured := encode.Loudness
+	if measured == nil {
+		for _, target := range targets {
+			if target.Options.Loudnorm == nil {
+				continue
+			}
+			var err error
+			if measured, err = ap.AnalyzeLoudness(ctx, inputPath); err != nil {
+				return fmt.Errorf("loudness analysis failed: %w", err)
+			}
+			break
+		}
 	}
 
-	// Add quality settings based on quality level
-	switch options.Quality {
-	case "low":
-		args = append(args, "-q:a", "9") // Lower quality, smaller file
-	case "medium":
-		args = append(args, "-q:a", "5") // Balanced
-	case "high":
-		args = append(args, "-q:a", "1") // Higher quality, larger file
+	args := []string{
+		"-i", inputPath,
+		"-y", // Overwrite output files
 	}
+	var outputs []string
+	nextInput := 1
+	inputRate := -1 // Probed on first use
+	for _, target := range targets {
+		spec, ok := LookupFormat(target.Options.Format)
+		if !ok {
+			return fmt.Errorf("unsupported format: %s", target.Options.Format)
+		}
+		options := target.Options
+
+		var loudnessTarget *LoudnormTarget
+		if options.Loudnorm != nil {
+			resolved, err := ResolveLoudnessTarget(*options.Loudnorm)
+			if err != nil {
+				return fmt.Errorf("invalid loudnorm target: %w", err)
+			}
+			loudnessTarget = &resolved
+		}
+
+		outputArgs := []string{"-map", "0:a"}
+
+		// Raw ADTS has nowhere to store tags
+		if encode.Tags != nil && spec.Container != "adts" {
+			tagInputs, tagOptions, metadataPath, err := tagArgs(encode.Tags, options.Format, target.OutputPath, nextInput)
+			if err != nil {
+				return fmt.Errorf("failed to prepare tags: %w", err)
+			}
+			defer func() {
+				_ = os.Remove(metadataPath) // #nosec G104 -- Cleanup operation, errors not critical
+			}()
+			args = append(args, tagInputs...)
+			outputArgs = append(outputArgs, tagOptions...)
+			for _, arg := range tagInputs {
+				if arg == "-i" {
+					nextInput++
+				}
+			}
+		}
 
-	// Add output path
-	args = append(args, outputPath)
+		// Add format-specific encoding options
+		outputArgs = append(outputArgs, "-c:a", spec.Codec)
+		if !spec.Lossless {
+			outputArgs = append(outputArgs, "-b:a", fmt.Sprintf("%dk", options.Bitrate))
+		}
+		outputArgs = append(outputArgs, "-f", ffmpegMuxer(spec.Container))
+
+		// Add sample rate if specified
+		sampleRate := options.SampleRate
+		if sampleRate == 0 && loudnessTarget != nil {
+			// loudnorm resamples to 192kHz internally, so pin the output to the source rate
+			if inputRate < 0 {
+				inputRate = 0
+				if info, err := ap.GetAudioInfo(ctx, inputPath); err == nil {
+					inputRate = info.SampleRate
+				}
+			}
+			sampleRate = 48000
+			if slices.Contains(spec.SampleRates, inputRate) {
+				sampleRate = inputRate
+			}
+		}
+		if sampleRate > 0 {
+			outputArgs = append(outputArgs, "-ar", fmt.Sprintf("%d", sampleRate))
+		}
+
+		if loudnessTarget != nil {
+			outputArgs = append(outputArgs, "-af", BuildLoudnormFilter(*loudnessTarget, measured))
+		}
+
+		if measured != nil {
+			tags := ReplayGainTags(measured, loudnessTarget)
+			outputArgs = append(outputArgs,
+				"-metadata", "REPLAYGAIN_TRACK_GAIN="+tags["REPLAYGAIN_TRACK_GAIN"],
+				"-metadata", "REPLAYGAIN_TRACK_PEAK="+tags["REPLAYGAIN_TRACK_PEAK"],
+			)
+		}
+
+		// Add quality settings based on quality level. Opus and FLAC have no -q:a scale.
+		switch qualityLevel(options.Format, options.Quality) {
+		case "low":
+			outputArgs = append(outputArgs, "-q:a", "9") // Lower quality, smaller file
+		case "medium":
+			outputArgs = append(outputArgs, "-q:a", "5") // Balanced
+		case "high":
+			outputArgs = append(outputArgs, "-q:a", "1") // Higher quality, larger file
+		}
+
+		// Add output path
+		outputs = append(outputs, append(outputArgs, target.OutputPath)...)
+	}
+	// Every input comes before the first output's options
+	args = append(args, outputs...)
 
 	// Execute ffmpeg
-	cmd := exec.CommandContext(ctx, "ffmpeg", args...) // #nosec G204 -- FFmpeg execution with controlled args for audio processing
-	output, err := cmd.CombinedOutput()
+	result, err := ap.executor.Run(ctx, "ffmpeg", args...)
 	if err != nil {
-		return fmt.Errorf("failed to compress audio with options %+v: %w, output: %s", options, err, string(output))
+		if len(targets) == 1 {
+			return fmt.Errorf("failed to compress audio with options %+v: %w, output: %s", targets[0].Options, err, result.Stderr)
+		}
+		return fmt.Errorf("failed to compress audio to %d outputs: %w, output: %s", len(targets), err, result.Stderr)
 	}
 
-	log.Printf("Successfully compressed audio with options: %s -> %s", inputPath, outputPath)
+	for _, target := range targets {
+		log.Printf("Successfully compressed audio with options: %s -> %s", inputPath, target.OutputPath)
+	}
 	return nil
 }
 
+// qualityLevel returns the quality to apply for a format, empty when the encoder has no
+// quality scale
+func qualityLevel(format, quality string) string {
+	if spec, ok := LookupFormat(format); ok && (spec.Lossless || spec.Codec == "libopus") {
+		return ""
+	}
+	return quality
+}
+
 // ExtractMetadata extracts comprehensive metadata from an audio file
 func (ap *AudioProcessor) ExtractMetadata(ctx context.Context, filePath string) (*models.AudioMetadata, error) {
-	// Get basic audio info
-	audioInfo, err := ap.GetAudioInfo(ctx, filePath)
+	probe, err := ap.Probe(ctx, filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get audio info: %w", err)
 	}
 
-	// Get format info
-	cmd := exec.CommandContext(ctx, "ffprobe",
-		"-v", "quiet",
-		"-show_entries", "format=format_name",
-		"-show_entries", "format_tags=title,artist,album,genre,date,track",
-		"-of", "csv=p=0",
-		filePath)
-
-	output, err := cmd.Output()
+	audioInfo, err := probe.AudioInfo()
 	if err != nil {
-		// If metadata extraction fails, return basic info
-		return &models.AudioMetadata{
-			Duration:   audioInfo.Duration,
-			Bitrate:    audioInfo.Bitrate,
-			SampleRate: audioInfo.SampleRate,
-			Channels:   audioInfo.Channels,
-			Format:     "unknown",
-		}, nil
+		return nil, fmt.Errorf("failed to get audio info: %w", err)
 	}
 
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	
 	metadata := &models.AudioMetadata{
 		Duration:   audioInfo.Duration,
 		Bitrate:    audioInfo.Bitrate,
 		SampleRate: audioInfo.SampleRate,
 		Channels:   audioInfo.Channels,
+		Format:     strings.Split(probe.Format.Name, ",")[0],
+		Title:      probe.Tag("title"),
+		Artist:     probe.Tag("artist"),
+		Album:      probe.Tag("album"),
+		Genre:      probe.Tag("genre"),
 		Tags:       make(map[string]string),
 	}
 
-	// Parse format and tags
-	if len(lines) > 0 {
-		formatParts := strings.Split(lines[0], ",")
-		if len(formatParts) > 0 {
-			metadata.Format = formatParts[0]
+	// Dates may be full ISO dates and track numbers may be "3/12"
+	if date := probe.Tag("date"); len(date) >= 4 {
+		if year, err := strconv.Atoi(date[:4]); err == nil {
+			metadata.Year = year
 		}
-		
-		// Parse metadata tags if available
-		if len(formatParts) > 1 {
-			tags := formatParts[1:]
-			tagNames := []string{"title", "artist", "album", "genre", "date", "track"}
-			
-			for i, tag := range tags {
-				if i < len(tagNames) && tag != "" {
-					switch tagNames[i] {
-					case "title":
-						metadata.Title = tag
-					case "artist":
-						metadata.Artist = tag
-					case "album":
-						metadata.Album = tag
-					case "genre":
-						metadata.Genre = tag
-					case "date":
-						if year, err := strconv.Atoi(tag); err == nil {
-							metadata.Year = year
-						}
-					case "track":
-						if track, err := strconv.Atoi(tag); err == nil {
-							metadata.TrackNumber = track
-						}
-					}
-					metadata.Tags[tagNames[i]] = tag
-				}
-			}
+	}
+	if track, _, _ := strings.Cut(probe.Tag("track"), "/"); track != "" {
+		if number, err := strconv.Atoi(strings.TrimSpace(track)); err == nil {
+			metadata.TrackNumber = number
 		}
 	}
 
+	if stream := probe.AudioStream(); stream != nil {
+		for key, value := range stream.Tags {
+			metadata.Tags[key] = value
+		}
+	}
+	for key, value := range probe.Format.Tags {
+		metadata.Tags[key] = value
+	}
+
 	return metadata, nil
 }
-
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -34,7 +35,7 @@ var _ = Describe("AudioProcessor", func() {
 		tempDir, err = os.MkdirTemp("", "audio_processor_test")
 		Expect(err).ToNot(HaveOccurred())
 		
-		processor = utils.NewAudioProcessor(tempDir)
+		processor = utils.NewAudioProcessor(tempDir, nil)
 		testAudioFile = filepath.Join(tempDir, "test_audio.wav")
 		ctx = context.Background()
 		
@@ -219,7 +220,7 @@ var _ = Describe("AudioProcessor", func() {
 				Entry("valid MP3 320kbps", models.CompressionOption{Bitrate: 320, Format: "mp3", Quality: "high"}, true),
 				Entry("valid AAC 256kbps", models.CompressionOption{Bitrate: 256, Format: "aac", Quality: "high"}, true),
 				Entry("valid OGG 192kbps", models.CompressionOption{Bitrate: 192, Format: "ogg", Quality: "medium"}, true),
-				Entry("invalid bitrate too low", models.CompressionOption{Bitrate: 64, Format: "mp3", Quality: "low"}, false),
+				Entry("invalid bitrate too low", models.CompressionOption{Bitrate: 4, Format: "mp3", Quality: "low"}, false),
 				Entry("invalid bitrate too high", models.CompressionOption{Bitrate: 500, Format: "mp3", Quality: "high"}, false),
 				Entry("invalid format", models.CompressionOption{Bitrate: 256, Format: "wav", Quality: "high"}, false),
 				Entry("invalid quality", models.CompressionOption{Bitrate: 256, Format: "mp3", Quality: "ultra"}, false),
@@ -261,6 +262,57 @@ var _ = Describe("AudioProcessor", func() {
 
 	// NOTE: ValidateCompressionOptions method doesn't exist - tests removed
 
+	Describe("CompressAudioMulti", func() {
+		It("should encode every target in one ffmpeg run", func() {
+			// Stand-in ffmpeg that records its arguments, one per line
+			binDir := GinkgoT().TempDir()
+			argsFile := filepath.Join(binDir, "args")
+			script := fmt.Sprintf("#!/bin/sh\nprintf '%%s\\n' \"$@\" >> %s\n", argsFile)
+			Expect(os.WriteFile(filepath.Join(binDir, "ffmpeg"), []byte(script), 0755)).To(Succeed())
+			GinkgoT().Setenv("PATH", binDir+":"+os.Getenv("PATH"))
+
+			inputPath := filepath.Join(tempDir, "input.wav")
+			Expect(os.WriteFile(inputPath, []byte("RIFF"), 0644)).To(Succeed())
+			processor = utils.NewAudioProcessor(tempDir, nil)
+
+			targets := []utils.CompressTarget{
+				{OutputPath: filepath.Join(tempDir, "low.mp3"), Options: models.CompressionOption{Format: "mp3", Bitrate: 128, SampleRate: 44100}},
+				{OutputPath: filepath.Join(tempDir, "lossless.flac"), Options: models.CompressionOption{Format: "flac"}},
+			}
+			encode := utils.EncodeContext{Tags: &utils.OutputTags{Title: "Song"}}
+			Expect(processor.CompressAudioMulti(ctx, inputPath, targets, encode)).To(Succeed())
+
+			data, err := os.ReadFile(argsFile)
+			Expect(err).NotTo(HaveOccurred())
+			args := strings.Split(strings.TrimSpace(string(data)), "\n")
+
+			// Inputs: the original, then one metadata file per output
+			var inputs []string
+			for i, arg := range args {
+				if arg == "-i" {
+					inputs = append(inputs, args[i+1])
+				}
+			}
+			Expect(inputs).To(HaveLen(3))
+			Expect(inputs[0]).To(Equal(inputPath))
+			Expect(args).To(ContainElements("-map_metadata", "1", "2"))
+
+			lowIndex := slices.Index(args, targets[0].OutputPath)
+			losslessIndex := slices.Index(args, targets[1].OutputPath)
+			Expect(lowIndex).To(BeNumerically(">", slices.Index(args, inputs[2])))
+			Expect(losslessIndex).To(BeNumerically(">", lowIndex))
+			Expect(args[lowIndex+1 : losslessIndex]).To(ContainElements("-map_metadata", "2", "flac"))
+		})
+
+		It("should reject invalid targets before running ffmpeg", func() {
+			targets := []utils.CompressTarget{
+				{OutputPath: filepath.Join(tempDir, "bad.mp3"), Options: models.CompressionOption{Format: "mp3", Bitrate: 4}},
+			}
+			err := processor.CompressAudioMulti(ctx, testAudioFile, targets, utils.EncodeContext{})
+			Expect(err).To(MatchError(ContainSubstring("invalid compression options")))
+		})
+	})
+
 	Describe("GetSupportedFormats", func() {
 		It("should return list of supported audio formats", func() {
 			formats := processor.GetSupportedFormats()
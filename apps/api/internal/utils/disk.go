@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInsufficientDiskSpace is returned when a job would not fit in the free space of its
+// working directory
+var ErrInsufficientDiskSpace = errors.New("insufficient disk space")
+
+// CheckDiskSpace returns ErrInsufficientDiskSpace when fewer than need bytes are free at
+// path. Where free space cannot be measured the check passes.
+func CheckDiskSpace(path string, need int64) error {
+	free, err := availableDiskBytes(path)
+	if err != nil {
+		return nil
+	}
+	if need > 0 && uint64(need) > free {
+		return fmt.Errorf("%w: need %d bytes, %d free in %s", ErrInsufficientDiskSpace, need, free, path)
+	}
+	return nil
+}
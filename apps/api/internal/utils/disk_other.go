@@ -0,0 +1,10 @@
+//go:build !unix
+
+package utils
+
+import "errors"
+
+// availableDiskBytes cannot measure free space on this platform
+func availableDiskBytes(path string) (uint64, error) {
+	return 0, errors.New("disk space is not available on this platform")
+}
@@ -0,0 +1,14 @@
+//go:build unix
+
+package utils
+
+import "syscall"
+
+// availableDiskBytes returns the space available to unprivileged users on path's filesystem
+func availableDiskBytes(path string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, err
+	}
+	return stat.Bavail * uint64(stat.Bsize), nil // #nosec G115 -- Block size is never negative
+}
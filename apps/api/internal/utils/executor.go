@@ -0,0 +1,235 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// Sandbox defaults for media tools run on untrusted uploads
+const (
+	DefaultExecCPUSeconds     = 600                    // 10 minutes of CPU time
+	DefaultExecMemoryBytes    = 4 * 1024 * 1024 * 1024 // 4 GiB of address space
+	DefaultExecFileSizeBytes  = 4 * 1024 * 1024 * 1024 // 4 GiB per written file
+	DefaultExecOpenFiles      = 256                    // Open file descriptors
+	DefaultExecTimeout        = 15 * time.Minute       // Wall clock per job
+	DefaultExecMaxStderrBytes = 64 * 1024              // Tail of stderr kept per job
+	DefaultExecMaxOutputBytes = 16 * 1024 * 1024       // Buffered stdout per job
+	execWaitDelay             = 5 * time.Second        // Grace for pipes to close after a kill
+)
+
+// ExecLimits bounds the resources of one tool run. Zero values disable a limit.
+type ExecLimits struct {
+	CPUSeconds     uint64        // RLIMIT_CPU
+	MemoryBytes    uint64        // RLIMIT_AS
+	FileSizeBytes  uint64        // RLIMIT_FSIZE, the largest file the tool may write
+	OpenFiles      uint64        // RLIMIT_NOFILE
+	Timeout        time.Duration // Wall clock, on top of the caller's context
+	MaxStderrBytes int           // Stderr beyond this keeps only the tail
+	MaxOutputBytes int           // Buffered stdout beyond this fails the run
+}
+
+// DefaultExecLimits returns the default sandbox limits
+func DefaultExecLimits() ExecLimits {
+	return ExecLimits{
+		CPUSeconds:     DefaultExecCPUSeconds,
+		MemoryBytes:    DefaultExecMemoryBytes,
+		FileSizeBytes:  DefaultExecFileSizeBytes,
+		OpenFiles:      DefaultExecOpenFiles,
+		Timeout:        DefaultExecTimeout,
+		MaxStderrBytes: DefaultExecMaxStderrBytes,
+		MaxOutputBytes: DefaultExecMaxOutputBytes,
+	}
+}
+
+// Executor runs ffmpeg, ffprobe and fpcalc on untrusted files. Each job gets rlimits (through
+// prlimit), its own empty working directory, a minimal environment and bounded output capture.
+// ffmpeg and ffprobe inputs may only be read through the file protocol. Paths passed to a job
+// must be absolute, since it does not run in the caller's working directory.
+type Executor struct {
+	limits  ExecLimits
+	workDir string
+	prlimit string // Path of prlimit, empty when rlimits cannot be applied
+}
+
+// NewExecutor creates an executor whose job directories live under workDir
+func NewExecutor(workDir string, limits ExecLimits) *Executor {
+	if abs, err := filepath.Abs(workDir); err == nil {
+		workDir = abs
+	}
+
+	prlimit, err := exec.LookPath("prlimit")
+	if err != nil {
+		log.Printf("Warning: prlimit not found, media tools will run without rlimits")
+		prlimit = ""
+	}
+
+	return &Executor{
+		limits:  limits,
+		workDir: workDir,
+		prlimit: prlimit,
+	}
+}
+
+// Limits returns the executor's limits
+func (e *Executor) Limits() ExecLimits {
+	return e.limits
+}
+
+// ExecResult is what a finished job printed
+type ExecResult struct {
+	Stdout []byte // Empty when stdout was streamed
+	Stderr string // At most MaxStderrBytes, from the end of the output
+}
+
+// Run executes a tool and buffers its stdout. The result is returned even on failure so
+// callers can report the tool's stderr.
+func (e *Executor) Run(ctx context.Context, tool string, args ...string) (*ExecResult, error) {
+	stdout := &limitedBuffer{limit: e.limits.MaxOutputBytes}
+	result, err := e.Stream(ctx, stdout, tool, args...)
+	result.Stdout = stdout.buf.Bytes()
+	if err == nil && stdout.exceeded {
+		err = fmt.Errorf("%s output exceeded %d bytes", tool, e.limits.MaxOutputBytes)
+	}
+	return result, err
+}
+
+// Stream executes a tool, copying its stdout to w as it is produced
+func (e *Executor) Stream(ctx context.Context, w io.Writer, tool string, args ...string) (*ExecResult, error) {
+	result := &ExecResult{}
+
+	// Resolve the tool here, since prlimit would only report a missing tool as an exit status
+	if _, err := exec.LookPath(tool); err != nil {
+		return result, fmt.Errorf("%s failed: %w", tool, err)
+	}
+
+	jobDir, err := os.MkdirTemp(e.workDir, "job_*")
+	if err != nil {
+		return result, fmt.Errorf("failed to create job directory: %w", err)
+	}
+	defer os.RemoveAll(jobDir) // #nosec G104 -- Cleanup operation, errors not critical
+
+	if e.limits.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.limits.Timeout)
+		defer cancel()
+	}
+
+	name, argv := e.command(tool, args)
+	cmd := exec.CommandContext(ctx, name, argv...) // #nosec G204 -- Tools and args are built by the audio and image processors
+	cmd.Dir = jobDir
+	cmd.Env = []string{"PATH=" + os.Getenv("PATH"), "HOME=" + jobDir, "TMPDIR=" + jobDir}
+	cmd.WaitDelay = execWaitDelay
+	killProcessGroupOnCancel(cmd)
+
+	stderr := &tailBuffer{limit: e.limits.MaxStderrBytes}
+	cmd.Stdout = w
+	cmd.Stderr = stderr
+
+	err = cmd.Run()
+	result.Stderr = stderr.String()
+	if err != nil {
+		if ctx.Err() != nil {
+			return result, fmt.Errorf("%s stopped: %w", tool, ctx.Err())
+		}
+		return result, fmt.Errorf("%s failed: %w", tool, err)
+	}
+	return result, nil
+}
+
+// command wraps a tool invocation in prlimit and restricts ffmpeg and ffprobe to local files
+func (e *Executor) command(tool string, args []string) (string, []string) {
+	switch tool {
+	case "ffmpeg":
+		args = restrictInputProtocols(args)
+	case "ffprobe":
+		args = append([]string{"-protocol_whitelist", "file"}, args...)
+	}
+
+	if e.prlimit == "" {
+		return tool, args
+	}
+
+	var limits []string
+	for _, limit := range []struct {
+		flag  string
+		value uint64
+	}{
+		{"--cpu", e.limits.CPUSeconds},
+		{"--as", e.limits.MemoryBytes},
+		{"--fsize", e.limits.FileSizeBytes},
+		{"--nofile", e.limits.OpenFiles},
+	} {
+		if limit.value > 0 {
+			limits = append(limits, limit.flag+"="+strconv.FormatUint(limit.value, 10))
+		}
+	}
+	if len(limits) == 0 {
+		return tool, args
+	}
+
+	return e.prlimit, append(append(limits, "--", tool), args...)
+}
+
+// restrictInputProtocols adds "-protocol_whitelist file" before every ffmpeg input, so a
+// crafted playlist or reference file cannot reach the network or other protocols. Outputs
+// such as "-" for stdout are unaffected.
+func restrictInputProtocols(args []string) []string {
+	restricted := make([]string, 0, len(args)+4)
+	for _, arg := range args {
+		if arg == "-i" {
+			restricted = append(restricted, "-protocol_whitelist", "file")
+		}
+		restricted = append(restricted, arg)
+	}
+	return restricted
+}
+
+// tailBuffer keeps the last limit bytes written to it
+type tailBuffer struct {
+	buf       []byte
+	limit     int
+	truncated bool
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	b.buf = append(b.buf, p...)
+	if b.limit > 0 && len(b.buf) > b.limit {
+		b.buf = append(b.buf[:0], b.buf[len(b.buf)-b.limit:]...)
+		b.truncated = true
+	}
+	return n, nil
+}
+
+func (b *tailBuffer) String() string {
+	if b.truncated {
+		return "[truncated] " + string(b.buf)
+	}
+	return string(b.buf)
+}
+
+// limitedBuffer buffers up to limit bytes and discards the rest, so a runaway tool cannot
+// exhaust memory
+type limitedBuffer struct {
+	buf      bytes.Buffer
+	limit    int
+	exceeded bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	if b.limit > 0 && b.buf.Len()+len(p) > b.limit {
+		b.exceeded = true
+		p = p[:max(b.limit-b.buf.Len(), 0)]
+	}
+	b.buf.Write(p)
+	return n, nil
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package utils
+
+import "os/exec"
+
+// killProcessGroupOnCancel is a no-op where process groups are unavailable; cancellation
+// kills only the tool itself
+func killProcessGroupOnCancel(cmd *exec.Cmd) {}
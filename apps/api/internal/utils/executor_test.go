@@ -0,0 +1,95 @@
+package utils_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/wavlake/monorepo/internal/utils"
+)
+
+var _ = Describe("Executor", func() {
+	var (
+		workDir string
+		limits  utils.ExecLimits
+		ctx     context.Context
+	)
+
+	BeforeEach(func() {
+		workDir = GinkgoT().TempDir()
+		limits = utils.DefaultExecLimits()
+		ctx = context.Background()
+	})
+
+	It("should run each job in its own working directory and remove it afterwards", func() {
+		executor := utils.NewExecutor(workDir, limits)
+
+		first, err := executor.Run(ctx, "sh", "-c", "pwd")
+		Expect(err).NotTo(HaveOccurred())
+		second, err := executor.Run(ctx, "sh", "-c", "pwd")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(strings.TrimSpace(string(first.Stdout))).To(HavePrefix(workDir))
+		Expect(first.Stdout).NotTo(Equal(second.Stdout))
+
+		entries, err := os.ReadDir(workDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entries).To(BeEmpty())
+	})
+
+	It("should keep only the tail of stderr", func() {
+		limits.MaxStderrBytes = 16
+		executor := utils.NewExecutor(workDir, limits)
+
+		result, err := executor.Run(ctx, "sh", "-c", "printf 'aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaerror at end' >&2; exit 1")
+		Expect(err).To(HaveOccurred())
+		Expect(result.Stderr).To(Equal("[truncated] aaaaerror at end"))
+	})
+
+	It("should fail when buffered output exceeds the limit", func() {
+		limits.MaxOutputBytes = 8
+		executor := utils.NewExecutor(workDir, limits)
+
+		result, err := executor.Run(ctx, "sh", "-c", "printf 0123456789abcdef")
+		Expect(err).To(MatchError(ContainSubstring("output exceeded 8 bytes")))
+		Expect(string(result.Stdout)).To(Equal("01234567"))
+	})
+
+	It("should stream stdout without buffering it", func() {
+		limits.MaxOutputBytes = 8
+		executor := utils.NewExecutor(workDir, limits)
+
+		var out bytes.Buffer
+		_, err := executor.Stream(ctx, &out, "sh", "-c", "printf 0123456789abcdef")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out.String()).To(Equal("0123456789abcdef"))
+	})
+
+	It("should stop jobs that run past the timeout", func() {
+		limits.Timeout = 100 * time.Millisecond
+		executor := utils.NewExecutor(workDir, limits)
+
+		start := time.Now()
+		_, err := executor.Run(ctx, "sh", "-c", "sleep 5")
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+		Expect(time.Since(start)).To(BeNumerically("<", 5*time.Second))
+	})
+
+	It("should apply rlimits", func() {
+		if _, err := exec.LookPath("prlimit"); err != nil {
+			Skip("prlimit is not installed")
+		}
+		limits.OpenFiles = 64
+		executor := utils.NewExecutor(workDir, limits)
+
+		result, err := executor.Run(ctx, "sh", "-c", "ulimit -n")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(strings.TrimSpace(string(result.Stdout))).To(Equal("64"))
+	})
+})
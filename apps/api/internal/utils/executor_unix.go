@@ -0,0 +1,17 @@
+//go:build unix
+
+package utils
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// killProcessGroupOnCancel runs the job in its own process group and kills the whole group
+// on cancellation, so children of the tool cannot outlive the job
+func killProcessGroupOnCancel(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/bits"
+	"sort"
+)
+
+const (
+	// FingerprintLength is the number of seconds of audio fingerprinted
+	FingerprintLength = 120
+	// FingerprintIndexKeyCount caps the index keys stored per track. Firestore's
+	// array-contains-any accepts at most 30 values, so one query covers a whole fingerprint.
+	FingerprintIndexKeyCount = 30
+	// fingerprintIndexShift drops the low bits of each frame so small encoder differences
+	// still land on the same index key
+	fingerprintIndexShift = 12
+	// FingerprintMaxOffset is how many frames either way two fingerprints are aligned over
+	FingerprintMaxOffset = 80
+)
+
+// Fingerprint is a Chromaprint raw fingerprint: one 32-bit sub-fingerprint per frame
+// (about 8 per second)
+type Fingerprint struct {
+	Duration float64  `json:"duration"`
+	Values   []uint32 `json:"fingerprint"`
+}
+
+// GenerateFingerprint computes a Chromaprint fingerprint of the first FingerprintLength
+// seconds with fpcalc. Nothing is sent to an external lookup service.
+func (ap *AudioProcessor) GenerateFingerprint(ctx context.Context, inputPath string) (*Fingerprint, error) {
+	result, err := ap.executor.Run(ctx, "fpcalc",
+		"-raw",
+		"-json",
+		"-length", fmt.Sprintf("%d", FingerprintLength),
+		inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fingerprint audio: %w", err)
+	}
+
+	var fingerprint Fingerprint
+	if err := json.Unmarshal(result.Stdout, &fingerprint); err != nil {
+		return nil, fmt.Errorf("failed to parse fingerprint: %w", err)
+	}
+	if len(fingerprint.Values) == 0 {
+		return nil, fmt.Errorf("fingerprint is empty")
+	}
+
+	return &fingerprint, nil
+}
+
+// FingerprintIndexKeys returns up to FingerprintIndexKeyCount coarse keys for finding candidate
+// matches. Keys are the high bits of the most common frames, which survive re-encoding.
+func FingerprintIndexKeys(values []uint32) []int64 {
+	counts := make(map[int64]int)
+	for _, value := range values {
+		counts[int64(value>>fingerprintIndexShift)]++
+	}
+
+	keys := make([]int64, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+
+	if len(keys) > FingerprintIndexKeyCount {
+		keys = keys[:FingerprintIndexKeyCount]
+	}
+	return keys
+}
+
+// CompareFingerprints returns the similarity of two fingerprints between 0 and 1: one minus
+// the bit error rate at the best alignment within FingerprintMaxOffset frames
+func CompareFingerprints(a, b []uint32) float64 {
+	best := 0.0
+	for offset := -FingerprintMaxOffset; offset <= FingerprintMaxOffset; offset++ {
+		compared, differing := 0, 0
+		for i := range a {
+			j := i + offset
+			if j < 0 || j >= len(b) {
+				continue
+			}
+			compared++
+			differing += bits.OnesCount32(a[i] ^ b[j])
+		}
+
+		// Require a meaningful overlap so a few aligned frames cannot score highly
+		if compared == 0 || compared < min(len(a), len(b))/2 {
+			continue
+		}
+
+		similarity := 1 - float64(differing)/float64(compared*32)
+		if similarity > best {
+			best = similarity
+		}
+	}
+	return best
+}
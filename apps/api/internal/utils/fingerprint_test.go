@@ -0,0 +1,58 @@
+package utils_test
+
+import (
+	"math/rand"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/wavlake/monorepo/internal/utils"
+)
+
+var _ = Describe("Fingerprint", func() {
+	randomFingerprint := func(seed int64, length int) []uint32 {
+		rng := rand.New(rand.NewSource(seed)) // #nosec G404 -- Deterministic test data
+		values := make([]uint32, length)
+		for i := range values {
+			values[i] = rng.Uint32()
+		}
+		return values
+	}
+
+	Describe("CompareFingerprints", func() {
+		It("should score identical fingerprints as 1", func() {
+			values := randomFingerprint(1, 400)
+			Expect(utils.CompareFingerprints(values, values)).To(Equal(1.0))
+		})
+
+		It("should find a match that is shifted and slightly altered", func() {
+			original := randomFingerprint(1, 400)
+			reencoded := make([]uint32, 0, len(original))
+			reencoded = append(reencoded, randomFingerprint(2, 10)...)
+			for i, value := range original {
+				if i%4 == 0 {
+					value ^= 0x11 // Flip two bits every fourth frame
+				}
+				reencoded = append(reencoded, value)
+			}
+
+			Expect(utils.CompareFingerprints(original, reencoded)).To(BeNumerically(">", 0.95))
+		})
+
+		It("should score unrelated fingerprints near chance", func() {
+			similarity := utils.CompareFingerprints(randomFingerprint(1, 400), randomFingerprint(2, 400))
+			Expect(similarity).To(BeNumerically("<", 0.6))
+		})
+	})
+
+	Describe("FingerprintIndexKeys", func() {
+		It("should return the most common coarse keys, capped for one Firestore query", func() {
+			values := randomFingerprint(1, 400)
+			values = append(values, values[0], values[0]|0xFFF)
+
+			keys := utils.FingerprintIndexKeys(values)
+			Expect(keys).To(HaveLen(utils.FingerprintIndexKeyCount))
+			Expect(keys[0]).To(Equal(int64(values[0] >> 12)))
+		})
+	})
+})
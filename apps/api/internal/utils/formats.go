@@ -0,0 +1,237 @@
+package utils
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/wavlake/monorepo/internal/models"
+)
+
+// FormatSpec describes how a compressed format is encoded and which settings it accepts
+type FormatSpec struct {
+	Codec       string // ffmpeg encoder
+	Container   string // Container the output is written in
+	Extension   string // File extension of the output
+	ContentType string // MIME type served for the output
+	Lossless    bool   // Lossless formats take no bitrate
+	MinBitrate  int    // Lowest accepted bitrate in kbps
+	MaxBitrate  int    // Highest accepted bitrate in kbps
+	SampleRates []int  // Accepted output sample rates in Hz
+}
+
+var (
+	mpegSampleRates  = []int{8000, 11025, 12000, 16000, 22050, 24000, 32000, 44100, 48000}
+	aacSampleRates   = []int{8000, 11025, 12000, 16000, 22050, 24000, 32000, 44100, 48000, 64000, 88200, 96000}
+	opusSampleRates  = []int{8000, 12000, 16000, 24000, 48000}
+	hiResSampleRates = []int{8000, 11025, 12000, 16000, 22050, 24000, 32000, 44100, 48000, 88200, 96000, 176400, 192000}
+)
+
+// formatSpecs are the compressed formats a CompressionOption can request, keyed by format
+var formatSpecs = map[string]FormatSpec{
+	"mp3": {
+		Codec: "libmp3lame", Container: "mp3", Extension: "mp3", ContentType: "audio/mpeg",
+		MinBitrate: 8, MaxBitrate: 320, SampleRates: mpegSampleRates,
+	},
+	"aac": {
+		// Raw ADTS, which cannot carry tags or cover art
+		Codec: "aac", Container: "adts", Extension: "aac", ContentType: "audio/aac",
+		MinBitrate: 32, MaxBitrate: 512, SampleRates: aacSampleRates,
+	},
+	"m4a": {
+		// AAC in MP4, with tags and cover art
+		Codec: "aac", Container: "mp4", Extension: "m4a", ContentType: "audio/mp4",
+		MinBitrate: 32, MaxBitrate: 512, SampleRates: aacSampleRates,
+	},
+	"ogg": {
+		Codec: "libvorbis", Container: "ogg", Extension: "ogg", ContentType: "audio/ogg",
+		MinBitrate: 45, MaxBitrate: 500, SampleRates: hiResSampleRates,
+	},
+	"opus": {
+		Codec: "libopus", Container: "ogg", Extension: "opus", ContentType: "audio/ogg",
+		MinBitrate: 6, MaxBitrate: 510, SampleRates: opusSampleRates,
+	},
+	"webm": {
+		// Opus in WebM, for browsers that play WebM but not Ogg
+		Codec: "libopus", Container: "webm", Extension: "webm", ContentType: "audio/webm",
+		MinBitrate: 6, MaxBitrate: 510, SampleRates: opusSampleRates,
+	},
+	"flac": {
+		Codec: "flac", Container: "flac", Extension: "flac", ContentType: "audio/flac",
+		Lossless: true, SampleRates: hiResSampleRates,
+	},
+}
+
+// LookupFormat returns the spec of a compressed format
+func LookupFormat(format string) (FormatSpec, bool) {
+	spec, ok := formatSpecs[format]
+	return spec, ok
+}
+
+// ValidateCompressionOption checks the format is supported and the bitrate, sample rate and
+// quality are ones its encoder accepts. HLS options are checked when packaging.
+func ValidateCompressionOption(option models.CompressionOption) error {
+	if option.Format == HLSFormat {
+		return nil
+	}
+
+	spec, ok := LookupFormat(option.Format)
+	if !ok {
+		return fmt.Errorf("unsupported format: %s", option.Format)
+	}
+
+	if spec.Lossless {
+		if option.Bitrate != 0 {
+			return fmt.Errorf("%s is lossless and does not take a bitrate", option.Format)
+		}
+	} else if option.Bitrate < spec.MinBitrate || option.Bitrate > spec.MaxBitrate {
+		return fmt.Errorf("%s bitrate must be between %d and %d kbps", option.Format, spec.MinBitrate, spec.MaxBitrate)
+	}
+
+	if option.SampleRate != 0 && !slices.Contains(spec.SampleRates, option.SampleRate) {
+		return fmt.Errorf("%s does not support a sample rate of %d Hz", option.Format, option.SampleRate)
+	}
+	// MPEG-2 and 2.5 layer III, used below 32kHz, top out at 160kbps
+	if option.Format == "mp3" && option.SampleRate != 0 && option.SampleRate < 32000 && option.Bitrate > 160 {
+		return fmt.Errorf("mp3 bitrate must be at most 160 kbps below 32000 Hz")
+	}
+
+	switch option.Quality {
+	case "", "low", "medium", "high":
+	default:
+		return fmt.Errorf("unsupported quality: %s", option.Quality)
+	}
+
+	return nil
+}
+
+// ladderBitrateTolerance allows presets slightly above the measured source bitrate, which
+// varies with tags and cover art, so a 320k upload still gets a 320k version
+const ladderBitrateTolerance = 1.05
+
+// PlanCompressionLadder returns the presets worth generating from a source: presets that would
+// raise its bitrate or sample rate are skipped, HLS presets lose renditions above the source
+// bitrate, and lossless presets need a lossless source. When every preset is skipped, the
+// first lossy one is kept, capped at the source's bitrate and sample rate, so the track always
+// gets a playable version. source may be nil when the original could not be probed.
+func PlanCompressionLadder(presets []models.CompressionOption, source *AudioInfo, losslessSource bool) []models.CompressionOption {
+	var planned []models.CompressionOption
+	for _, preset := range presets {
+		if fitted, ok := fitToSource(preset, source, losslessSource); ok {
+			planned = append(planned, fitted)
+		}
+	}
+	if len(planned) > 0 || source == nil {
+		return planned
+	}
+
+	for _, preset := range presets {
+		spec, ok := LookupFormat(preset.Format)
+		if !ok || spec.Lossless {
+			continue
+		}
+		if source.Bitrate > 0 && preset.Bitrate > source.Bitrate {
+			preset.Bitrate = max(source.Bitrate, spec.MinBitrate)
+		}
+		if source.SampleRate > 0 && preset.SampleRate > source.SampleRate {
+			preset.SampleRate = highestSampleRate(spec.SampleRates, source.SampleRate)
+		}
+		return []models.CompressionOption{preset}
+	}
+	return nil
+}
+
+// fitToSource returns the preset as it should be generated from the source, or false when it
+// would only raise the source's quality ceiling
+func fitToSource(preset models.CompressionOption, source *AudioInfo, losslessSource bool) (models.CompressionOption, bool) {
+	spec, ok := LookupFormat(preset.Format)
+	lossless := ok && spec.Lossless
+	if lossless && !losslessSource {
+		return preset, false
+	}
+	if source == nil {
+		return preset, true
+	}
+
+	if source.SampleRate > 0 && preset.SampleRate > source.SampleRate {
+		return preset, false
+	}
+	if lossless || source.Bitrate <= 0 {
+		return preset, true
+	}
+
+	if preset.Format == HLSFormat {
+		var renditions []int
+		for _, bitrate := range NormalizeHLSRenditions(preset.Renditions) {
+			if !exceedsBitrate(bitrate, source.Bitrate) {
+				renditions = append(renditions, bitrate)
+			}
+		}
+		preset.Renditions = renditions
+		return preset, len(renditions) > 0
+	}
+
+	return preset, !exceedsBitrate(preset.Bitrate, source.Bitrate)
+}
+
+// exceedsBitrate reports whether a target bitrate is above the source's, allowing for
+// ladderBitrateTolerance
+func exceedsBitrate(target, source int) bool {
+	return float64(target) > float64(source)*ladderBitrateTolerance
+}
+
+// highestSampleRate returns the highest rate in rates not above limit, or 0 to leave the
+// encoder's default
+func highestSampleRate(rates []int, limit int) int {
+	best := 0
+	for _, rate := range rates {
+		if rate <= limit && rate > best {
+			best = rate
+		}
+	}
+	return best
+}
+
+// IsLosslessExtension reports whether an original's file extension is a lossless format
+func IsLosslessExtension(extension string) bool {
+	switch strings.ToLower(strings.TrimPrefix(extension, ".")) {
+	case "wav", "flac", "aiff", "aif":
+		return true
+	default:
+		return false
+	}
+}
+
+// ContainerForFormat returns the container a compressed format is written in
+func ContainerForFormat(format string) string {
+	if spec, ok := LookupFormat(format); ok {
+		return spec.Container
+	}
+	return format
+}
+
+// OutputExtension returns the file extension used for a compressed format
+func OutputExtension(format string) string {
+	if spec, ok := LookupFormat(format); ok {
+		return spec.Extension
+	}
+	return format
+}
+
+// ContentTypeForFormat returns the MIME type of a compressed format
+func ContentTypeForFormat(format string) string {
+	if spec, ok := LookupFormat(format); ok {
+		return spec.ContentType
+	}
+	return "application/octet-stream"
+}
+
+// ffmpegMuxer returns the ffmpeg muxer for a container
+func ffmpegMuxer(container string) string {
+	switch container {
+	case "mp4":
+		return "ipod" // MP4 flavour with iTunes metadata atoms
+	default:
+		return container
+	}
+}
@@ -0,0 +1,111 @@
+package utils_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/wavlake/monorepo/internal/models"
+	"github.com/wavlake/monorepo/internal/utils"
+)
+
+var _ = Describe("Formats", func() {
+	DescribeTable("container, extension and content type",
+		func(format, container, extension, contentType string) {
+			Expect(utils.ContainerForFormat(format)).To(Equal(container))
+			Expect(utils.OutputExtension(format)).To(Equal(extension))
+			Expect(utils.ContentTypeForFormat(format)).To(Equal(contentType))
+		},
+		Entry("mp3", "mp3", "mp3", "mp3", "audio/mpeg"),
+		Entry("raw aac", "aac", "adts", "aac", "audio/aac"),
+		Entry("aac in MP4", "m4a", "mp4", "m4a", "audio/mp4"),
+		Entry("vorbis", "ogg", "ogg", "ogg", "audio/ogg"),
+		Entry("opus in Ogg", "opus", "ogg", "opus", "audio/ogg"),
+		Entry("opus in WebM", "webm", "webm", "webm", "audio/webm"),
+		Entry("flac", "flac", "flac", "flac", "audio/flac"),
+	)
+
+	It("should not guess a content type for unknown formats", func() {
+		Expect(utils.ContentTypeForFormat("wma")).To(Equal("application/octet-stream"))
+	})
+
+	DescribeTable("ValidateCompressionOption",
+		func(option models.CompressionOption, valid bool) {
+			err := utils.ValidateCompressionOption(option)
+			if valid {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(HaveOccurred())
+			}
+		},
+		Entry("mp3 320kbps", models.CompressionOption{Format: "mp3", Bitrate: 320, SampleRate: 44100}, true),
+		Entry("mp3 64kbps", models.CompressionOption{Format: "mp3", Bitrate: 64}, true),
+		Entry("mp3 below 8kbps", models.CompressionOption{Format: "mp3", Bitrate: 4}, false),
+		Entry("mp3 above 160kbps below 32kHz", models.CompressionOption{Format: "mp3", Bitrate: 192, SampleRate: 24000}, false),
+		Entry("mp3 at 96kHz", models.CompressionOption{Format: "mp3", Bitrate: 128, SampleRate: 96000}, false),
+		Entry("opus 96kbps", models.CompressionOption{Format: "opus", Bitrate: 96}, true),
+		Entry("opus at 44.1kHz", models.CompressionOption{Format: "opus", Bitrate: 96, SampleRate: 44100}, false),
+		Entry("opus above 510kbps", models.CompressionOption{Format: "webm", Bitrate: 512}, false),
+		Entry("flac at 96kHz", models.CompressionOption{Format: "flac", SampleRate: 96000}, true),
+		Entry("flac with a bitrate", models.CompressionOption{Format: "flac", Bitrate: 320}, false),
+		Entry("lossy without a bitrate", models.CompressionOption{Format: "aac"}, false),
+		Entry("unknown quality", models.CompressionOption{Format: "ogg", Bitrate: 192, Quality: "ultra"}, false),
+		Entry("unknown format", models.CompressionOption{Format: "wav", Bitrate: 256}, false),
+		Entry("hls", models.CompressionOption{Format: utils.HLSFormat}, true),
+	)
+
+	Describe("PlanCompressionLadder", func() {
+		low := models.CompressionOption{Preset: "stream-low", Format: "mp3", Bitrate: 128, SampleRate: 44100}
+		high := models.CompressionOption{Preset: "stream-high", Format: "mp3", Bitrate: 320, SampleRate: 44100}
+		lossless := models.CompressionOption{Preset: "lossless", Format: "flac"}
+		ladder := []models.CompressionOption{low, high, lossless}
+
+		presetNames := func(options []models.CompressionOption) []string {
+			var names []string
+			for _, option := range options {
+				names = append(names, option.Preset)
+			}
+			return names
+		}
+
+		It("should keep every preset for a lossless original", func() {
+			source := &utils.AudioInfo{Bitrate: 1411, SampleRate: 44100}
+			Expect(presetNames(utils.PlanCompressionLadder(ladder, source, true))).To(Equal([]string{"stream-low", "stream-high", "lossless"}))
+		})
+
+		It("should skip presets above a lossy original's bitrate", func() {
+			source := &utils.AudioInfo{Bitrate: 192, SampleRate: 44100}
+			Expect(presetNames(utils.PlanCompressionLadder(ladder, source, false))).To(Equal([]string{"stream-low"}))
+		})
+
+		It("should allow for measured bitrates just under the preset", func() {
+			source := &utils.AudioInfo{Bitrate: 317, SampleRate: 44100}
+			Expect(presetNames(utils.PlanCompressionLadder(ladder, source, false))).To(Equal([]string{"stream-low", "stream-high"}))
+		})
+
+		It("should skip presets above the original's sample rate", func() {
+			source := &utils.AudioInfo{Bitrate: 705, SampleRate: 22050}
+			planned := utils.PlanCompressionLadder(ladder, source, true)
+			Expect(presetNames(planned)).To(Equal([]string{"lossless"}))
+		})
+
+		It("should cap the first lossy preset when every preset is skipped", func() {
+			source := &utils.AudioInfo{Bitrate: 96, SampleRate: 32000}
+			planned := utils.PlanCompressionLadder(ladder, source, false)
+			Expect(planned).To(HaveLen(1))
+			Expect(planned[0].Preset).To(Equal("stream-low"))
+			Expect(planned[0].Bitrate).To(Equal(96))
+			Expect(planned[0].SampleRate).To(Equal(32000))
+		})
+
+		It("should drop HLS renditions above the original's bitrate", func() {
+			hls := models.CompressionOption{Format: utils.HLSFormat, Renditions: []int{64, 128, 256}}
+			planned := utils.PlanCompressionLadder([]models.CompressionOption{hls}, &utils.AudioInfo{Bitrate: 160}, false)
+			Expect(planned).To(HaveLen(1))
+			Expect(planned[0].Renditions).To(Equal([]int{64, 128}))
+		})
+
+		It("should keep lossy presets when the original could not be probed", func() {
+			Expect(presetNames(utils.PlanCompressionLadder(ladder, nil, false))).To(Equal([]string{"stream-low", "stream-high"}))
+		})
+	})
+})
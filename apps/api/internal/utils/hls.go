@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	// HLSFormat is the CompressionOption format that requests adaptive HLS packaging
+	HLSFormat = "hls"
+	// HLSMasterPlaylist is the file name of the master playlist in an HLS package
+	HLSMasterPlaylist = "master.m3u8"
+	// HLSVariantPlaylist is the file name of each rendition's media playlist
+	HLSVariantPlaylist = "index.m3u8"
+	// HLSSegmentDuration is the target segment length in seconds
+	HLSSegmentDuration = 6
+)
+
+// DefaultHLSRenditions are the AAC bitrates (kbps) packaged when none are requested
+var DefaultHLSRenditions = []int{64, 128, 256}
+
+// HLSRendition is one AAC bitrate of an HLS package
+type HLSRendition struct {
+	Bitrate  int    // Target bitrate in kbps
+	Playlist string // Media playlist path relative to the master playlist
+}
+
+// HLSPackage is the result of packaging a track for HLS
+type HLSPackage struct {
+	Dir        string         // Local directory holding the master playlist, variant playlists and segments
+	Renditions []HLSRendition // Renditions ordered by ascending bitrate
+}
+
+// NormalizeHLSRenditions returns the requested rendition bitrates sorted and de-duplicated,
+// falling back to DefaultHLSRenditions when none are valid
+func NormalizeHLSRenditions(bitrates []int) []int {
+	seen := make(map[int]bool)
+	var renditions []int
+	for _, bitrate := range bitrates {
+		if bitrate <= 0 || seen[bitrate] {
+			continue
+		}
+		seen[bitrate] = true
+		renditions = append(renditions, bitrate)
+	}
+
+	if len(renditions) == 0 {
+		renditions = append(renditions, DefaultHLSRenditions...)
+	}
+
+	sort.Ints(renditions)
+	return renditions
+}
+
+// BuildHLSMasterPlaylist renders a master playlist referencing each rendition's media playlist
+func BuildHLSMasterPlaylist(renditions []HLSRendition) string {
+	var b strings.Builder
+	b.WriteString("#EXTM3U\n")
+	b.WriteString("#EXT-X-VERSION:3\n")
+	for _, rendition := range renditions {
+		fmt.Fprintf(&b, "#EXT-X-STREAM-INF:BANDWIDTH=%d,CODECS=\"mp4a.40.2\"\n", rendition.Bitrate*1000)
+		b.WriteString(rendition.Playlist + "\n")
+	}
+	return b.String()
+}
+
+// GetHLSContentType returns the MIME type for a file in an HLS package
+func GetHLSContentType(fileName string) string {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".m3u8":
+		return "application/vnd.apple.mpegurl"
+	case ".ts":
+		return "video/mp2t"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// PackageHLS segments the input into AAC renditions at the given bitrates and writes a
+// master playlist. Each rendition lives in its own "<bitrate>k" subdirectory of outputDir.
+func (ap *AudioProcessor) PackageHLS(ctx context.Context, inputPath, outputDir string, bitrates []int, sampleRate int) (*HLSPackage, error) {
+	pkg := &HLSPackage{Dir: outputDir}
+
+	for _, bitrate := range NormalizeHLSRenditions(bitrates) {
+		renditionName := fmt.Sprintf("%dk", bitrate)
+		renditionDir := filepath.Join(outputDir, renditionName)
+		// #nosec G301
+		if err := os.MkdirAll(renditionDir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create rendition directory: %w", err)
+		}
+
+		args := []string{
+			"-i", inputPath,
+			"-y",
+			"-vn",
+			"-c:a", "aac",
+			"-b:a", fmt.Sprintf("%dk", bitrate),
+		}
+		if sampleRate > 0 {
+			args = append(args, "-ar", fmt.Sprintf("%d", sampleRate))
+		}
+		args = append(args,
+			"-f", "hls",
+			"-hls_time", fmt.Sprintf("%d", HLSSegmentDuration),
+			"-hls_playlist_type", "vod",
+			"-hls_segment_filename", filepath.Join(renditionDir, "segment_%03d.ts"),
+			filepath.Join(renditionDir, HLSVariantPlaylist),
+		)
+
+		if result, err := ap.executor.Run(ctx, "ffmpeg", args...); err != nil {
+			return nil, fmt.Errorf("failed to package %s HLS rendition: %w, output: %s", renditionName, err, result.Stderr)
+		}
+
+		pkg.Renditions = append(pkg.Renditions, HLSRendition{
+			Bitrate:  bitrate,
+			Playlist: renditionName + "/" + HLSVariantPlaylist,
+		})
+	}
+
+	masterPath := filepath.Join(outputDir, HLSMasterPlaylist)
+	// #nosec G306 -- Playlists are public media files
+	if err := os.WriteFile(masterPath, []byte(BuildHLSMasterPlaylist(pkg.Renditions)), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write master playlist: %w", err)
+	}
+
+	log.Printf("Successfully packaged HLS with %d renditions: %s -> %s", len(pkg.Renditions), inputPath, outputDir)
+	return pkg, nil
+}
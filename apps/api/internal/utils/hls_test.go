@@ -0,0 +1,51 @@
+package utils_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/wavlake/monorepo/internal/utils"
+)
+
+var _ = Describe("HLS", func() {
+	Describe("NormalizeHLSRenditions", func() {
+		It("should sort and de-duplicate requested bitrates", func() {
+			Expect(utils.NormalizeHLSRenditions([]int{256, 64, 0, 64, 128})).To(Equal([]int{64, 128, 256}))
+		})
+
+		It("should fall back to the default ladder", func() {
+			Expect(utils.NormalizeHLSRenditions(nil)).To(Equal(utils.DefaultHLSRenditions))
+		})
+	})
+
+	Describe("BuildHLSMasterPlaylist", func() {
+		It("should reference every rendition with its bandwidth", func() {
+			playlist := utils.BuildHLSMasterPlaylist([]utils.HLSRendition{
+				{Bitrate: 64, Playlist: "64k/index.m3u8"},
+				{Bitrate: 128, Playlist: "128k/index.m3u8"},
+			})
+
+			Expect(playlist).To(HavePrefix("#EXTM3U\n"))
+			Expect(playlist).To(ContainSubstring("#EXT-X-STREAM-INF:BANDWIDTH=64000,CODECS=\"mp4a.40.2\"\n64k/index.m3u8\n"))
+			Expect(playlist).To(ContainSubstring("#EXT-X-STREAM-INF:BANDWIDTH=128000,CODECS=\"mp4a.40.2\"\n128k/index.m3u8\n"))
+		})
+	})
+
+	Describe("GetHLSContentType", func() {
+		It("should return playlist and segment MIME types", func() {
+			Expect(utils.GetHLSContentType("master.m3u8")).To(Equal("application/vnd.apple.mpegurl"))
+			Expect(utils.GetHLSContentType("128k/segment_000.ts")).To(Equal("video/mp2t"))
+		})
+	})
+
+	Describe("GetHLSPath", func() {
+		It("should place packages under the compressed prefix so the track ID can be recovered", func() {
+			config := utils.GetStoragePathConfig()
+			path := config.GetHLSPath("track-123", "version-456", "128k/index.m3u8")
+
+			Expect(path).To(Equal("tracks/compressed/track-123_version-456/128k/index.m3u8"))
+			Expect(config.IsCompressedPath(path)).To(BeTrue())
+			Expect(config.GetTrackIDFromPath(path)).To(Equal("track-123"))
+		})
+	})
+})
@@ -0,0 +1,194 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MinArtworkDimension is the smallest width/height accepted for uploaded artwork
+	MinArtworkDimension = 300
+	// MaxArtworkFileSize is the largest artwork upload accepted, in bytes
+	MaxArtworkFileSize = 20 * 1024 * 1024
+)
+
+// ArtworkVariantSizes are the square edge lengths generated for every artwork upload
+var ArtworkVariantSizes = []int{100, 300, 600, 1400}
+
+// ArtworkVariantFormats are the output formats generated for every variant size
+var ArtworkVariantFormats = []string{"jpg", "webp"}
+
+// ImageProcessor handles artwork validation and resizing
+type ImageProcessor struct {
+	tempDir  string
+	executor *Executor
+}
+
+// NewImageProcessor creates a new image processor. A nil executor runs tools with the
+// default sandbox limits.
+func NewImageProcessor(tempDir string, executor *Executor) *ImageProcessor {
+	if executor == nil {
+		executor = NewExecutor(tempDir, DefaultExecLimits())
+	}
+	return &ImageProcessor{
+		tempDir:  tempDir,
+		executor: executor,
+	}
+}
+
+// ImageInfo contains metadata about an image file
+type ImageInfo struct {
+	Codec  string // ffprobe codec name, e.g. "mjpeg", "png", "webp"
+	Width  int
+	Height int
+	Size   int64 // File size in bytes
+}
+
+// GetSupportedImageFormats returns a list of supported artwork upload formats
+func (ip *ImageProcessor) GetSupportedImageFormats() []string {
+	return []string{"jpg", "jpeg", "png", "webp"}
+}
+
+// IsImageFormatSupported checks if an artwork file extension is supported
+func (ip *ImageProcessor) IsImageFormatSupported(extension string) bool {
+	extension = strings.ToLower(strings.TrimPrefix(extension, "."))
+	for _, format := range ip.GetSupportedImageFormats() {
+		if format == extension {
+			return true
+		}
+	}
+	return false
+}
+
+// GetImageInfo extracts dimensions and codec from an image file using ffprobe
+func (ip *ImageProcessor) GetImageInfo(ctx context.Context, inputPath string) (*ImageInfo, error) {
+	result, err := ip.executor.Run(ctx, "ffprobe",
+		"-v", "error",
+		"-select_streams", "v:0",
+		"-show_entries", "stream=codec_name,width,height",
+		"-of", "csv=p=0",
+		inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get image info: %w", err)
+	}
+
+	parts := strings.Split(strings.TrimSpace(string(result.Stdout)), ",")
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("unexpected ffprobe output format")
+	}
+
+	width, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse width: %w", err)
+	}
+
+	height, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse height: %w", err)
+	}
+
+	fileInfo, err := os.Stat(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat image: %w", err)
+	}
+
+	return &ImageInfo{
+		Codec:  parts[0],
+		Width:  width,
+		Height: height,
+		Size:   fileInfo.Size(),
+	}, nil
+}
+
+// ValidateImageFile checks that a file is a supported still image that is large enough to use as artwork
+func (ip *ImageProcessor) ValidateImageFile(ctx context.Context, filePath string) (*ImageInfo, error) {
+	info, err := ip.GetImageInfo(ctx, filePath)
+	if err != nil {
+		return nil, fmt.Errorf("file is not a valid image: %w", err)
+	}
+
+	switch info.Codec {
+	case "mjpeg", "png", "webp":
+	default:
+		return nil, fmt.Errorf("unsupported image codec: %s", info.Codec)
+	}
+
+	if info.Size > MaxArtworkFileSize {
+		return nil, fmt.Errorf("image is too large: %d bytes (max %d)", info.Size, MaxArtworkFileSize)
+	}
+
+	if info.Width < MinArtworkDimension || info.Height < MinArtworkDimension {
+		return nil, fmt.Errorf("image must be at least %dx%d pixels, got %dx%d", MinArtworkDimension, MinArtworkDimension, info.Width, info.Height)
+	}
+
+	return info, nil
+}
+
+// ResizeSquare center-crops an image to a square and scales it to size x size.
+// All metadata (including EXIF) is dropped from the output.
+func (ip *ImageProcessor) ResizeSquare(ctx context.Context, inputPath, outputPath string, size int, format string) error {
+	// #nosec G301
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	args := []string{
+		"-i", inputPath,
+		"-vf", fmt.Sprintf("crop='min(iw,ih)':'min(iw,ih)',scale=%d:%d:flags=lanczos", size, size),
+		"-map_metadata", "-1", // Strip EXIF and any other metadata
+		"-frames:v", "1",
+	}
+
+	switch format {
+	case "jpg":
+		args = append(args, "-c:v", "mjpeg", "-q:v", "2", "-pix_fmt", "yuvj420p")
+	case "webp":
+		args = append(args, "-c:v", "libwebp", "-quality", "85")
+	default:
+		return fmt.Errorf("unsupported artwork format: %s", format)
+	}
+
+	args = append(args, "-y", outputPath)
+
+	result, err := ip.executor.Run(ctx, "ffmpeg", args...)
+	if err != nil {
+		return fmt.Errorf("failed to resize image: %w, output: %s", err, result.Stderr)
+	}
+
+	log.Printf("Generated %dx%d %s artwork: %s", size, size, format, outputPath)
+	return nil
+}
+
+// GetVariantSizes returns the variant sizes that can be generated from a source
+// image without upscaling. The smallest size is always included.
+func (ip *ImageProcessor) GetVariantSizes(width, height int) []int {
+	edge := width
+	if height < edge {
+		edge = height
+	}
+
+	var sizes []int
+	for i, size := range ArtworkVariantSizes {
+		if size <= edge || i == 0 {
+			sizes = append(sizes, size)
+		}
+	}
+	return sizes
+}
+
+// GetImageContentType returns the MIME type for an artwork format
+func GetImageContentType(format string) string {
+	switch strings.ToLower(strings.TrimPrefix(format, ".")) {
+	case "png":
+		return "image/png"
+	case "webp":
+		return "image/webp"
+	default:
+		return "image/jpeg"
+	}
+}
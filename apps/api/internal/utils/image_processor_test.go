@@ -0,0 +1,52 @@
+package utils_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/wavlake/monorepo/internal/utils"
+)
+
+var _ = Describe("ImageProcessor", func() {
+	var processor *utils.ImageProcessor
+
+	BeforeEach(func() {
+		processor = utils.NewImageProcessor(GinkgoT().TempDir(), nil)
+	})
+
+	Describe("IsImageFormatSupported", func() {
+		It("should accept common artwork formats with or without a dot", func() {
+			for _, ext := range []string{"jpg", ".jpeg", "PNG", "webp"} {
+				Expect(processor.IsImageFormatSupported(ext)).To(BeTrue(), "format %s should be supported", ext)
+			}
+		})
+
+		It("should reject other formats", func() {
+			for _, ext := range []string{"gif", "bmp", "mp3", ""} {
+				Expect(processor.IsImageFormatSupported(ext)).To(BeFalse(), "format %s should not be supported", ext)
+			}
+		})
+	})
+
+	Describe("GetVariantSizes", func() {
+		It("should generate every size for large images", func() {
+			Expect(processor.GetVariantSizes(3000, 3000)).To(Equal([]int{100, 300, 600, 1400}))
+		})
+
+		It("should not upscale past the shorter edge", func() {
+			Expect(processor.GetVariantSizes(800, 650)).To(Equal([]int{100, 300, 600}))
+		})
+
+		It("should always include the smallest size", func() {
+			Expect(processor.GetVariantSizes(80, 80)).To(Equal([]int{100}))
+		})
+	})
+
+	Describe("GetImageContentType", func() {
+		It("should map artwork formats to MIME types", func() {
+			Expect(utils.GetImageContentType("jpg")).To(Equal("image/jpeg"))
+			Expect(utils.GetImageContentType("webp")).To(Equal("image/webp"))
+			Expect(utils.GetImageContentType(".png")).To(Equal("image/png"))
+		})
+	})
+})
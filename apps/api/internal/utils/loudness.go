@@ -0,0 +1,144 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/wavlake/monorepo/internal/models"
+)
+
+// ReplayGainReference is the ReplayGain 2.0 reference loudness in LUFS
+const ReplayGainReference = -18.0
+
+// LoudnormTarget is a loudness target with every value resolved
+type LoudnormTarget struct {
+	IntegratedLUFS float64 // Target integrated loudness in LUFS
+	TruePeak       float64 // Maximum true peak in dBTP
+	LRA            float64 // Target loudness range in LU
+}
+
+// DefaultLoudnessTarget is the platform normalization target used when a CompressionOption
+// enables loudnorm without specifying values
+var DefaultLoudnessTarget = LoudnormTarget{
+	IntegratedLUFS: -14,
+	TruePeak:       -1,
+	LRA:            11,
+}
+
+// loudnormStats mirrors the JSON block printed by ffmpeg's loudnorm filter
+type loudnormStats struct {
+	InputI       string `json:"input_i"`
+	InputTP      string `json:"input_tp"`
+	InputLRA     string `json:"input_lra"`
+	InputThresh  string `json:"input_thresh"`
+	TargetOffset string `json:"target_offset"`
+}
+
+// AnalyzeLoudness measures integrated loudness, true peak and loudness range of a file
+// with a first loudnorm pass
+func (ap *AudioProcessor) AnalyzeLoudness(ctx context.Context, inputPath string) (*models.LoudnessInfo, error) {
+	target := DefaultLoudnessTarget
+	result, err := ap.executor.Run(ctx, "ffmpeg",
+		"-hide_banner",
+		"-nostats",
+		"-i", inputPath,
+		"-vn",
+		"-af", fmt.Sprintf("loudnorm=I=%g:TP=%g:LRA=%g:print_format=json", target.IntegratedLUFS, target.TruePeak, target.LRA),
+		"-f", "null",
+		"-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze loudness: %w, output: %s", err, result.Stderr)
+	}
+
+	// loudnorm prints its measurements at the end of stderr, which the executor keeps
+	return ParseLoudnormOutput(result.Stderr)
+}
+
+// ParseLoudnormOutput extracts the measurements from loudnorm's print_format=json output
+func ParseLoudnormOutput(output string) (*models.LoudnessInfo, error) {
+	start := strings.LastIndex(output, "{")
+	end := strings.LastIndex(output, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("no loudnorm measurements in output")
+	}
+
+	var stats loudnormStats
+	if err := json.Unmarshal([]byte(output[start:end+1]), &stats); err != nil {
+		return nil, fmt.Errorf("failed to parse loudnorm measurements: %w", err)
+	}
+
+	values := make([]float64, 4)
+	for i, raw := range []string{stats.InputI, stats.InputTP, stats.InputLRA, stats.InputThresh} {
+		value, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse loudnorm value %q: %w", raw, err)
+		}
+		// Digital silence measures as -inf; clamp so the result can be stored
+		if math.IsInf(value, -1) {
+			value = -99
+		}
+		values[i] = value
+	}
+
+	return &models.LoudnessInfo{
+		IntegratedLUFS: values[0],
+		TruePeak:       values[1],
+		LRA:            values[2],
+		Threshold:      values[3],
+	}, nil
+}
+
+// ResolveLoudnessTarget fills unset fields of a requested target from DefaultLoudnessTarget
+// and checks the values are within the ranges loudnorm accepts
+func ResolveLoudnessTarget(requested models.LoudnessTarget) (LoudnormTarget, error) {
+	target := DefaultLoudnessTarget
+	if requested.IntegratedLUFS != nil {
+		target.IntegratedLUFS = *requested.IntegratedLUFS
+	}
+	if requested.TruePeak != nil {
+		target.TruePeak = *requested.TruePeak
+	}
+	if requested.LRA != nil {
+		target.LRA = *requested.LRA
+	}
+
+	if target.IntegratedLUFS < -70 || target.IntegratedLUFS > -5 {
+		return target, fmt.Errorf("integrated loudness target must be between -70 and -5 LUFS")
+	}
+	if target.TruePeak < -9 || target.TruePeak > 0 {
+		return target, fmt.Errorf("true peak target must be between -9 and 0 dBTP")
+	}
+	if target.LRA < 1 || target.LRA > 50 {
+		return target, fmt.Errorf("loudness range target must be between 1 and 50 LU")
+	}
+
+	return target, nil
+}
+
+// BuildLoudnormFilter returns the second-pass loudnorm filter that normalizes to target using
+// the first-pass measurements. Linear mode keeps dynamics intact when the target allows it.
+func BuildLoudnormFilter(target LoudnormTarget, measured *models.LoudnessInfo) string {
+	return fmt.Sprintf("loudnorm=I=%g:TP=%g:LRA=%g:measured_I=%.2f:measured_TP=%.2f:measured_LRA=%.2f:measured_thresh=%.2f:linear=true",
+		target.IntegratedLUFS, target.TruePeak, target.LRA,
+		measured.IntegratedLUFS, measured.TruePeak, measured.LRA, measured.Threshold)
+}
+
+// ReplayGainTags returns REPLAYGAIN_TRACK_GAIN/PEAK tags for an output with the given
+// measurements. When target is set, the output loudness is the normalized one.
+func ReplayGainTags(measured *models.LoudnessInfo, target *LoudnormTarget) map[string]string {
+	loudness := measured.IntegratedLUFS
+	peak := measured.TruePeak
+	if target != nil {
+		peak = math.Min(peak+target.IntegratedLUFS-loudness, target.TruePeak)
+		loudness = target.IntegratedLUFS
+	}
+
+	return map[string]string{
+		"REPLAYGAIN_TRACK_GAIN": fmt.Sprintf("%.2f dB", ReplayGainReference-loudness),
+		"REPLAYGAIN_TRACK_PEAK": fmt.Sprintf("%.6f", math.Pow(10, peak/20)),
+	}
+}
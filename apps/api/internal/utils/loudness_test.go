@@ -0,0 +1,98 @@
+package utils_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/wavlake/monorepo/internal/models"
+	"github.com/wavlake/monorepo/internal/utils"
+)
+
+var _ = Describe("Loudness", func() {
+	Describe("ParseLoudnormOutput", func() {
+		It("should parse the JSON block printed after ffmpeg's log output", func() {
+			output := `Input #0, wav, from 'in.wav':
+  Duration: 00:03:00.00, bitrate: 1411 kb/s
+[Parsed_loudnorm_0 @ 0x5581] 
+{
+	"input_i" : "-9.32",
+	"input_tp" : "0.41",
+	"input_lra" : "5.10",
+	"input_thresh" : "-19.54",
+	"output_i" : "-14.02",
+	"output_tp" : "-1.00",
+	"output_lra" : "4.60",
+	"output_thresh" : "-24.21",
+	"normalization_type" : "dynamic",
+	"target_offset" : "0.02"
+}
+`
+			info, err := utils.ParseLoudnormOutput(output)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info.IntegratedLUFS).To(BeNumerically("~", -9.32, 0.001))
+			Expect(info.TruePeak).To(BeNumerically("~", 0.41, 0.001))
+			Expect(info.LRA).To(BeNumerically("~", 5.10, 0.001))
+			Expect(info.Threshold).To(BeNumerically("~", -19.54, 0.001))
+		})
+
+		It("should clamp silence measured as -inf", func() {
+			output := `{"input_i" : "-inf", "input_tp" : "-inf", "input_lra" : "0.00", "input_thresh" : "-70.00"}`
+			info, err := utils.ParseLoudnormOutput(output)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info.IntegratedLUFS).To(Equal(-99.0))
+		})
+
+		It("should fail without measurements", func() {
+			_, err := utils.ParseLoudnormOutput("Conversion failed!")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("ResolveLoudnessTarget", func() {
+		value := func(v float64) *float64 { return &v }
+
+		It("should fill unset values from the platform default", func() {
+			target, err := utils.ResolveLoudnessTarget(models.LoudnessTarget{IntegratedLUFS: value(-16)})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(target).To(Equal(utils.LoudnormTarget{IntegratedLUFS: -16, TruePeak: -1, LRA: 11}))
+		})
+
+		It("should accept an explicit 0 dBTP ceiling", func() {
+			target, err := utils.ResolveLoudnessTarget(models.LoudnessTarget{TruePeak: value(0)})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(target).To(Equal(utils.LoudnormTarget{IntegratedLUFS: -14, TruePeak: 0, LRA: 11}))
+		})
+
+		It("should reject targets loudnorm does not accept", func() {
+			for _, target := range []models.LoudnessTarget{{IntegratedLUFS: value(-3)}, {IntegratedLUFS: value(0)}, {TruePeak: value(2)}, {LRA: value(60)}, {LRA: value(0)}} {
+				_, err := utils.ResolveLoudnessTarget(target)
+				Expect(err).To(HaveOccurred(), "target %+v should be rejected", target)
+			}
+		})
+	})
+
+	Describe("BuildLoudnormFilter", func() {
+		It("should pass first-pass measurements to the second pass", func() {
+			filter := utils.BuildLoudnormFilter(utils.DefaultLoudnessTarget, &models.LoudnessInfo{
+				IntegratedLUFS: -9.32, TruePeak: 0.41, LRA: 5.1, Threshold: -19.54,
+			})
+			Expect(filter).To(Equal("loudnorm=I=-14:TP=-1:LRA=11:measured_I=-9.32:measured_TP=0.41:measured_LRA=5.10:measured_thresh=-19.54:linear=true"))
+		})
+	})
+
+	Describe("ReplayGainTags", func() {
+		measured := &models.LoudnessInfo{IntegratedLUFS: -9, TruePeak: -0.5}
+
+		It("should describe the source loudness when not normalizing", func() {
+			tags := utils.ReplayGainTags(measured, nil)
+			Expect(tags["REPLAYGAIN_TRACK_GAIN"]).To(Equal("-9.00 dB"))
+			Expect(tags["REPLAYGAIN_TRACK_PEAK"]).To(Equal("0.944061"))
+		})
+
+		It("should describe the normalized loudness and limited peak", func() {
+			tags := utils.ReplayGainTags(measured, &utils.DefaultLoudnessTarget)
+			Expect(tags["REPLAYGAIN_TRACK_GAIN"]).To(Equal("-4.00 dB"))
+			Expect(tags["REPLAYGAIN_TRACK_PEAK"]).To(Equal("0.530884"))
+		})
+	})
+})
@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const (
+	// DefaultPageLimit is used when a list request does not specify a limit
+	DefaultPageLimit = 50
+	// MaxPageLimit caps the page size a client can request
+	MaxPageLimit = 200
+)
+
+// ErrCursorMismatch is returned for a cursor replayed with a sort field or order other than
+// the one it was issued for
+var ErrCursorMismatch = errors.New("cursor was issued for a different sort or order")
+
+// Cursor identifies the last item of a page: its sort key value and its ID as a tie-breaker,
+// along with the sort field and order of the listing it was issued for
+type Cursor struct {
+	Sort  string `json:"s"`
+	Order string `json:"o"`
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+// NormalizeSort applies the default sort field and order, created_at descending
+func NormalizeSort(sortBy, sortOrder string) (string, string) {
+	if sortBy == "" {
+		sortBy = "created_at"
+	}
+	if sortOrder != "asc" {
+		sortOrder = "desc"
+	}
+	return sortBy, sortOrder
+}
+
+// EncodeCursor builds an opaque, URL-safe cursor from a normalized sort field and order, a sort
+// key value and an item ID
+func EncodeCursor(sortBy, sortOrder, value, id string) string {
+	data, _ := json.Marshal(Cursor{Sort: sortBy, Order: sortOrder, Value: value, ID: id}) // #nosec G104 -- Marshalling strings cannot fail
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a cursor produced by EncodeCursor and checks it was issued for the
+// normalized sortBy and sortOrder, returning ErrCursorMismatch otherwise
+func DecodeCursor(cursor, sortBy, sortOrder string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor encoding: %w", err)
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+	if c.ID == "" {
+		return nil, fmt.Errorf("invalid cursor: missing id")
+	}
+	if c.Sort != sortBy || c.Order != sortOrder {
+		return nil, ErrCursorMismatch
+	}
+
+	return &c, nil
+}
+
+// NormalizePageLimit applies the default and maximum page size
+func NormalizePageLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultPageLimit
+	}
+	if limit > MaxPageLimit {
+		return MaxPageLimit
+	}
+	return limit
+}
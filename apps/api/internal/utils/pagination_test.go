@@ -0,0 +1,56 @@
+package utils_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/wavlake/monorepo/internal/utils"
+)
+
+var _ = Describe("Pagination", func() {
+	Describe("EncodeCursor and DecodeCursor", func() {
+		It("should round-trip the sort value and ID", func() {
+			cursor, err := utils.DecodeCursor(utils.EncodeCursor("created_at", "desc", "2024-01-02T03:04:05Z", "track-1"), "created_at", "desc")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cursor.Value).To(Equal("2024-01-02T03:04:05Z"))
+			Expect(cursor.ID).To(Equal("track-1"))
+		})
+
+		It("should reject malformed cursors", func() {
+			for _, cursor := range []string{"not base64!", "bm90LWpzb24", utils.EncodeCursor("created_at", "desc", "value", "")} {
+				_, err := utils.DecodeCursor(cursor, "created_at", "desc")
+				Expect(err).To(HaveOccurred(), "cursor %q should be rejected", cursor)
+			}
+		})
+
+		It("should reject cursors issued for another sort field or order", func() {
+			cursor := utils.EncodeCursor("created_at", "desc", "2024-01-02T03:04:05Z", "track-1")
+
+			_, err := utils.DecodeCursor(cursor, "updated_at", "desc")
+			Expect(err).To(MatchError(utils.ErrCursorMismatch))
+
+			_, err = utils.DecodeCursor(cursor, "created_at", "asc")
+			Expect(err).To(MatchError(utils.ErrCursorMismatch))
+		})
+	})
+
+	Describe("NormalizeSort", func() {
+		It("should default to created_at descending", func() {
+			sortBy, sortOrder := utils.NormalizeSort("", "")
+			Expect(sortBy).To(Equal("created_at"))
+			Expect(sortOrder).To(Equal("desc"))
+
+			sortBy, sortOrder = utils.NormalizeSort("updated_at", "asc")
+			Expect(sortBy).To(Equal("updated_at"))
+			Expect(sortOrder).To(Equal("asc"))
+		})
+	})
+
+	Describe("NormalizePageLimit", func() {
+		It("should apply the default and maximum limits", func() {
+			Expect(utils.NormalizePageLimit(0)).To(Equal(utils.DefaultPageLimit))
+			Expect(utils.NormalizePageLimit(10)).To(Equal(10))
+			Expect(utils.NormalizePageLimit(utils.MaxPageLimit + 1)).To(Equal(utils.MaxPageLimit))
+		})
+	})
+})
@@ -0,0 +1,277 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ProbeResult is what ffprobe reports about a media file
+type ProbeResult struct {
+	Format   ProbeFormat
+	Streams  []ProbeStream
+	Chapters []ProbeChapter
+}
+
+// ProbeFormat describes the container
+type ProbeFormat struct {
+	Name       string            // Short container names, e.g. "mp3" or "mov,mp4,m4a,3gp,3g2,mj2"
+	LongName   string            // Human readable container name
+	DurationMs int64             // Duration in milliseconds
+	Size       int64             // File size in bytes
+	Bitrate    int64             // Overall bitrate in bits per second, 0 when unknown
+	Tags       map[string]string // Container tags with lower-cased keys
+}
+
+// ProbeStream describes one stream of the file
+type ProbeStream struct {
+	Index         int
+	CodecType     string // "audio", "video", "subtitle", "data" or "attachment"
+	CodecName     string // e.g. "mp3", "flac", "aac", "mjpeg"
+	CodecLongName string
+	CodecTag      string // Container codec tag, e.g. "mp4a"; "drms" or "enca" for encrypted MP4 audio
+	Profile       string
+	SampleRate    int    // Hz, audio only
+	Channels      int    // Audio only
+	ChannelLayout string // e.g. "stereo", "5.1", audio only
+	BitDepth      int    // Bits per sample for PCM and lossless codecs, 0 for lossy ones
+	Bitrate       int64  // Bits per second, 0 when unknown
+	DurationMs    int64  // Duration in milliseconds, 0 when unknown
+	AttachedPic   bool   // Embedded cover art rather than a video track
+	Tags          map[string]string
+}
+
+// ProbeChapter is a chapter marker
+type ProbeChapter struct {
+	ID      int64
+	StartMs int64
+	EndMs   int64
+	Title   string
+}
+
+// ffprobeOutput mirrors ffprobe's -print_format json output. Numbers other than counts are
+// printed as strings.
+type ffprobeOutput struct {
+	Format struct {
+		FormatName     string            `json:"format_name"`
+		FormatLongName string            `json:"format_long_name"`
+		Duration       string            `json:"duration"`
+		Size           string            `json:"size"`
+		BitRate        string            `json:"bit_rate"`
+		Tags           map[string]string `json:"tags"`
+	} `json:"format"`
+	Streams []struct {
+		Index            int               `json:"index"`
+		CodecType        string            `json:"codec_type"`
+		CodecName        string            `json:"codec_name"`
+		CodecLongName    string            `json:"codec_long_name"`
+		CodecTagString   string            `json:"codec_tag_string"`
+		Profile          string            `json:"profile"`
+		SampleRate       string            `json:"sample_rate"`
+		Channels         int               `json:"channels"`
+		ChannelLayout    string            `json:"channel_layout"`
+		BitsPerSample    int               `json:"bits_per_sample"`
+		BitsPerRawSample string            `json:"bits_per_raw_sample"`
+		BitRate          string            `json:"bit_rate"`
+		Duration         string            `json:"duration"`
+		Disposition      map[string]int    `json:"disposition"`
+		Tags             map[string]string `json:"tags"`
+	} `json:"streams"`
+	Chapters []struct {
+		ID        int64             `json:"id"`
+		StartTime string            `json:"start_time"`
+		EndTime   string            `json:"end_time"`
+		Tags      map[string]string `json:"tags"`
+	} `json:"chapters"`
+}
+
+// Probe runs ffprobe on a file and returns its format, streams and chapters
+func (ap *AudioProcessor) Probe(ctx context.Context, filePath string) (*ProbeResult, error) {
+	result, err := ap.executor.Run(ctx, "ffprobe",
+		"-v", "error",
+		"-print_format", "json",
+		"-show_format",
+		"-show_streams",
+		"-show_chapters",
+		filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to probe file: %w", err)
+	}
+
+	return ParseProbeOutput(result.Stdout)
+}
+
+// ParseProbeOutput parses ffprobe's JSON output
+func ParseProbeOutput(data []byte) (*ProbeResult, error) {
+	var raw ffprobeOutput
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse ffprobe output: %w", err)
+	}
+
+	result := &ProbeResult{
+		Format: ProbeFormat{
+			Name:       raw.Format.FormatName,
+			LongName:   raw.Format.FormatLongName,
+			DurationMs: parseSecondsMs(raw.Format.Duration),
+			Size:       parseProbeInt(raw.Format.Size),
+			Bitrate:    parseProbeInt(raw.Format.BitRate),
+			Tags:       lowerKeys(raw.Format.Tags),
+		},
+	}
+
+	for _, stream := range raw.Streams {
+		bitDepth := stream.BitsPerSample
+		if bitDepth == 0 {
+			bitDepth = int(parseProbeInt(stream.BitsPerRawSample))
+		}
+		result.Streams = append(result.Streams, ProbeStream{
+			Index:         stream.Index,
+			CodecType:     stream.CodecType,
+			CodecName:     stream.CodecName,
+			CodecLongName: stream.CodecLongName,
+			CodecTag:      stream.CodecTagString,
+			Profile:       stream.Profile,
+			SampleRate:    int(parseProbeInt(stream.SampleRate)),
+			Channels:      stream.Channels,
+			ChannelLayout: stream.ChannelLayout,
+			BitDepth:      bitDepth,
+			Bitrate:       parseProbeInt(stream.BitRate),
+			DurationMs:    parseSecondsMs(stream.Duration),
+			AttachedPic:   stream.Disposition["attached_pic"] == 1,
+			Tags:          lowerKeys(stream.Tags),
+		})
+	}
+
+	for _, chapter := range raw.Chapters {
+		result.Chapters = append(result.Chapters, ProbeChapter{
+			ID:      chapter.ID,
+			StartMs: parseSecondsMs(chapter.StartTime),
+			EndMs:   parseSecondsMs(chapter.EndTime),
+			Title:   lowerKeys(chapter.Tags)["title"],
+		})
+	}
+
+	return result, nil
+}
+
+// AudioStream returns the first audio stream, or nil when the file has none
+func (r *ProbeResult) AudioStream() *ProbeStream {
+	for i := range r.Streams {
+		if r.Streams[i].CodecType == "audio" {
+			return &r.Streams[i]
+		}
+	}
+	return nil
+}
+
+// Tag returns a metadata tag by case-insensitive name. Container tags take precedence; Ogg
+// and FLAC keep Vorbis comments on the audio stream instead.
+func (r *ProbeResult) Tag(name string) string {
+	name = strings.ToLower(name)
+	if value := r.Format.Tags[name]; value != "" {
+		return value
+	}
+	if stream := r.AudioStream(); stream != nil {
+		return stream.Tags[name]
+	}
+	return ""
+}
+
+// AudioInfo summarizes the probe as an AudioInfo. The bitrate falls back to the audio stream's,
+// then to one derived from size and duration.
+func (r *ProbeResult) AudioInfo() (*AudioInfo, error) {
+	stream := r.AudioStream()
+	if stream == nil {
+		return nil, fmt.Errorf("file does not contain audio stream")
+	}
+
+	durationMs := r.Format.DurationMs
+	if durationMs == 0 {
+		durationMs = stream.DurationMs
+	}
+
+	bitrate := r.Format.Bitrate
+	if bitrate == 0 {
+		bitrate = stream.Bitrate
+	}
+	if bitrate == 0 && durationMs > 0 && r.Format.Size > 0 {
+		bitrate = r.Format.Size * 8 * 1000 / durationMs
+	}
+
+	return &AudioInfo{
+		Duration:   int(durationMs / 1000),
+		Size:       r.Format.Size,
+		Bitrate:    int(bitrate / 1000),
+		SampleRate: stream.SampleRate,
+		Channels:   stream.Channels,
+	}, nil
+}
+
+// DetectDRM returns why a probed file looks copy protected, or "" when it does not.
+// FairPlay and CENC encrypted MP4 audio carry a "drms" or "enca" codec tag and ffprobe cannot
+// name their codec; protected WMA is flagged in the ASF header tags.
+func DetectDRM(probe *ProbeResult) string {
+	stream := probe.AudioStream()
+	if stream != nil {
+		switch strings.ToLower(stream.CodecTag) {
+		case "drms", "drmi", "enca":
+			return "file is DRM protected"
+		}
+		if stream.CodecName == "" || stream.CodecName == "none" {
+			return "audio codec could not be identified; the file may be DRM protected"
+		}
+	}
+
+	for key, value := range probe.Format.Tags {
+		if (key == "wm/protected" || strings.Contains(key, "drm")) && value != "" && value != "0" && value != "false" {
+			return "file is DRM protected"
+		}
+	}
+
+	return ""
+}
+
+// CheckDecodes decodes the whole audio stream and fails on the first decoding error, catching
+// truncated and corrupted files that still probe cleanly
+func (ap *AudioProcessor) CheckDecodes(ctx context.Context, filePath string) error {
+	result, err := ap.executor.Run(ctx, "ffmpeg",
+		"-v", "error",
+		"-xerror",
+		"-i", filePath,
+		"-map", "0:a:0",
+		"-f", "null",
+		"-")
+	if err != nil {
+		return fmt.Errorf("file could not be decoded: %w, output: %s", err, strings.TrimSpace(result.Stderr))
+	}
+	return nil
+}
+
+// parseSecondsMs converts ffprobe's decimal seconds to milliseconds, 0 when absent or N/A
+func parseSecondsMs(value string) int64 {
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil || seconds < 0 || math.IsInf(seconds, 0) || math.IsNaN(seconds) {
+		return 0
+	}
+	return int64(math.Round(seconds * 1000))
+}
+
+// parseProbeInt parses one of ffprobe's integer strings, 0 when absent or N/A
+func parseProbeInt(value string) int64 {
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+func lowerKeys(tags map[string]string) map[string]string {
+	lowered := make(map[string]string, len(tags))
+	for key, value := range tags {
+		lowered[strings.ToLower(key)] = value
+	}
+	return lowered
+}
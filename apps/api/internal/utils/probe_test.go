@@ -0,0 +1,152 @@
+package utils_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/wavlake/monorepo/internal/utils"
+)
+
+var _ = Describe("Probe", func() {
+	Describe("ParseProbeOutput", func() {
+		It("should find the audio stream past embedded cover art", func() {
+			output := `{
+				"streams": [
+					{
+						"index": 0,
+						"codec_name": "mjpeg",
+						"codec_type": "video",
+						"disposition": {"default": 0, "attached_pic": 1}
+					},
+					{
+						"index": 1,
+						"codec_name": "mp3",
+						"codec_long_name": "MP3 (MPEG audio layer 3)",
+						"codec_type": "audio",
+						"sample_rate": "44100",
+						"channels": 2,
+						"channel_layout": "stereo",
+						"bits_per_sample": 0,
+						"bit_rate": "320000",
+						"duration": "183.040000",
+						"disposition": {"default": 0, "attached_pic": 0}
+					}
+				],
+				"format": {
+					"format_name": "mp3",
+					"format_long_name": "MP2/3 (MPEG audio layer 2/3)",
+					"duration": "183.040000",
+					"size": "7389184",
+					"bit_rate": "322956",
+					"tags": {
+						"TITLE": "Hello, World",
+						"artist": "Someone, Else",
+						"date": "2024-05-01",
+						"track": "3/12"
+					}
+				}
+			}`
+
+			probe, err := utils.ParseProbeOutput([]byte(output))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(probe.Streams).To(HaveLen(2))
+			Expect(probe.Streams[0].AttachedPic).To(BeTrue())
+
+			stream := probe.AudioStream()
+			Expect(stream).NotTo(BeNil())
+			Expect(stream.Index).To(Equal(1))
+			Expect(stream.CodecName).To(Equal("mp3"))
+			Expect(stream.ChannelLayout).To(Equal("stereo"))
+			Expect(stream.DurationMs).To(Equal(int64(183040)))
+
+			Expect(probe.Format.DurationMs).To(Equal(int64(183040)))
+			Expect(probe.Tag("title")).To(Equal("Hello, World"))
+			Expect(probe.Tag("Artist")).To(Equal("Someone, Else"))
+
+			info, err := probe.AudioInfo()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*info).To(Equal(utils.AudioInfo{
+				Duration:   183,
+				Size:       7389184,
+				Bitrate:    322,
+				SampleRate: 44100,
+				Channels:   2,
+			}))
+		})
+
+		It("should read bit depth, stream tags and chapters", func() {
+			output := `{
+				"streams": [
+					{
+						"index": 0,
+						"codec_name": "flac",
+						"codec_type": "audio",
+						"sample_rate": "96000",
+						"channels": 2,
+						"bits_per_sample": 0,
+						"bits_per_raw_sample": "24",
+						"tags": {"TITLE": "Vorbis title"}
+					}
+				],
+				"chapters": [
+					{"id": 0, "start_time": "0.000000", "end_time": "90.500000", "tags": {"title": "Intro"}},
+					{"id": 1, "start_time": "90.500000", "end_time": "180.000000", "tags": {"title": "Outro"}}
+				],
+				"format": {
+					"format_name": "flac",
+					"duration": "180.000000",
+					"size": "45000000",
+					"bit_rate": "N/A"
+				}
+			}`
+
+			probe, err := utils.ParseProbeOutput([]byte(output))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(probe.AudioStream().BitDepth).To(Equal(24))
+			Expect(probe.Tag("title")).To(Equal("Vorbis title"))
+			Expect(probe.Chapters).To(Equal([]utils.ProbeChapter{
+				{ID: 0, StartMs: 0, EndMs: 90500, Title: "Intro"},
+				{ID: 1, StartMs: 90500, EndMs: 180000, Title: "Outro"},
+			}))
+
+			// Bitrate is derived from size and duration when ffprobe reports N/A
+			info, err := probe.AudioInfo()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Bitrate).To(Equal(2000))
+		})
+
+		It("should report files without an audio stream", func() {
+			probe, err := utils.ParseProbeOutput([]byte(`{"streams": [{"index": 0, "codec_type": "video"}], "format": {}}`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(probe.AudioStream()).To(BeNil())
+
+			_, err = probe.AudioInfo()
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should reject output that is not JSON", func() {
+			_, err := utils.ParseProbeOutput([]byte("44100,2"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("DetectDRM", func() {
+		It("should flag protected WMA", func() {
+			probe, err := utils.ParseProbeOutput([]byte(`{
+				"streams": [{"index": 0, "codec_type": "audio", "codec_name": "wmav2"}],
+				"format": {"format_name": "asf", "tags": {"WM/Protected": "1"}}
+			}`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(utils.DetectDRM(probe)).To(Equal("file is DRM protected"))
+		})
+
+		It("should pass unprotected audio", func() {
+			probe, err := utils.ParseProbeOutput([]byte(`{
+				"streams": [{"index": 0, "codec_type": "audio", "codec_name": "aac", "codec_tag_string": "mp4a"}],
+				"format": {"format_name": "mov,mp4,m4a,3gp,3g2,mj2"}
+			}`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(utils.DetectDRM(probe)).To(BeEmpty())
+		})
+	})
+})
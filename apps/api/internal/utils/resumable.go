@@ -0,0 +1,160 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Resumable uploads follow the GCS XML API protocol, which cmd/fileserver also implements:
+// POST a signed URL with "x-goog-resumable: start" to open a session, then PUT chunks to the
+// session URL with Content-Range. A PUT of "Content-Range: bytes */*" with no body asks for
+// the committed offset, answered with 308 and a Range header.
+const (
+	ResumableHeader = "x-goog-resumable"
+	ResumableStart  = "start"
+
+	// StatusResumeIncomplete is the status of a session that is still accepting chunks
+	StatusResumeIncomplete = http.StatusPermanentRedirect
+)
+
+// ErrUploadSessionExpired is returned for sessions that storage no longer knows about
+var ErrUploadSessionExpired = errors.New("upload session expired")
+
+// StartResumableUpload opens a resumable upload session through a signed start URL and
+// returns the session URL. headers must include every header signed into the URL.
+func StartResumableUpload(ctx context.Context, client *http.Client, startURL string, headers map[string]string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, startURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create upload session request: %w", err)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	req.Header.Set(ResumableHeader, ResumableStart)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to start upload session: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024)) // #nosec G104 -- Body is only used in the error message
+		return "", fmt.Errorf("failed to start upload session: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	location := resp.Header.Get("Location")
+	if location == "" {
+		return "", fmt.Errorf("failed to start upload session: no session URL in response")
+	}
+	return location, nil
+}
+
+// QueryResumableUpload returns how many bytes a session has committed and whether the upload
+// has completed
+func QueryResumableUpload(ctx context.Context, client *http.Client, sessionURL string) (int64, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, sessionURL, nil)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to create upload status request: %w", err)
+	}
+	req.Header.Set("Content-Range", "bytes */*")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to query upload session: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated:
+		return 0, true, nil
+	case StatusResumeIncomplete:
+		offset, err := ParseCommittedRange(resp.Header.Get("Range"))
+		if err != nil {
+			return 0, false, err
+		}
+		return offset, false, nil
+	case http.StatusNotFound, http.StatusGone:
+		return 0, false, ErrUploadSessionExpired
+	default:
+		return 0, false, fmt.Errorf("failed to query upload session: status %d", resp.StatusCode)
+	}
+}
+
+// ParseCommittedRange converts a resumable upload's "bytes=0-N" Range header to the number
+// of committed bytes. An empty header means nothing has been committed.
+func ParseCommittedRange(header string) (int64, error) {
+	if header == "" {
+		return 0, nil
+	}
+	last, ok := strings.CutPrefix(header, "bytes=0-")
+	if !ok {
+		return 0, fmt.Errorf("invalid committed range: %q", header)
+	}
+	end, err := strconv.ParseInt(last, 10, 64)
+	if err != nil || end < 0 {
+		return 0, fmt.Errorf("invalid committed range: %q", header)
+	}
+	return end + 1, nil
+}
+
+// FormatCommittedRange is the Range header reporting committed bytes, empty for none
+func FormatCommittedRange(committed int64) string {
+	if committed <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("bytes=0-%d", committed-1)
+}
+
+// ContentRange is a parsed Content-Range header of a resumable upload chunk
+type ContentRange struct {
+	Start int64 // First byte of the chunk, -1 for a status query
+	End   int64 // Last byte of the chunk, -1 for a status query
+	Total int64 // Total upload size, -1 when not yet known
+}
+
+// ParseContentRange parses "bytes START-END/TOTAL", where TOTAL may be "*", and the status
+// query form "bytes */TOTAL"
+func ParseContentRange(header string) (ContentRange, error) {
+	spec, ok := strings.CutPrefix(header, "bytes ")
+	if !ok {
+		return ContentRange{}, fmt.Errorf("invalid content range: %q", header)
+	}
+	chunk, total, ok := strings.Cut(spec, "/")
+	if !ok {
+		return ContentRange{}, fmt.Errorf("invalid content range: %q", header)
+	}
+
+	r := ContentRange{Start: -1, End: -1, Total: -1}
+	if total != "*" {
+		n, err := strconv.ParseInt(total, 10, 64)
+		if err != nil || n < 0 {
+			return ContentRange{}, fmt.Errorf("invalid content range: %q", header)
+		}
+		r.Total = n
+	}
+	if chunk == "*" {
+		return r, nil
+	}
+
+	start, end, ok := strings.Cut(chunk, "-")
+	if !ok {
+		return ContentRange{}, fmt.Errorf("invalid content range: %q", header)
+	}
+	var err error
+	if r.Start, err = strconv.ParseInt(start, 10, 64); err != nil || r.Start < 0 {
+		return ContentRange{}, fmt.Errorf("invalid content range: %q", header)
+	}
+	if r.End, err = strconv.ParseInt(end, 10, 64); err != nil || r.End < r.Start {
+		return ContentRange{}, fmt.Errorf("invalid content range: %q", header)
+	}
+	if r.Total >= 0 && r.End >= r.Total {
+		return ContentRange{}, fmt.Errorf("invalid content range: %q", header)
+	}
+	return r, nil
+}
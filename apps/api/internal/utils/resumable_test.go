@@ -0,0 +1,77 @@
+package utils_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/wavlake/monorepo/internal/utils"
+)
+
+var _ = Describe("Resumable uploads", func() {
+	Describe("ParseContentRange", func() {
+		It("should parse chunks with known and unknown totals", func() {
+			Expect(utils.ParseContentRange("bytes 0-1023/*")).To(Equal(utils.ContentRange{Start: 0, End: 1023, Total: -1}))
+			Expect(utils.ParseContentRange("bytes 1024-2047/2048")).To(Equal(utils.ContentRange{Start: 1024, End: 2047, Total: 2048}))
+		})
+
+		It("should parse status queries", func() {
+			Expect(utils.ParseContentRange("bytes */*")).To(Equal(utils.ContentRange{Start: -1, End: -1, Total: -1}))
+			Expect(utils.ParseContentRange("bytes */2048")).To(Equal(utils.ContentRange{Start: -1, End: -1, Total: 2048}))
+		})
+
+		It("should reject malformed ranges", func() {
+			for _, header := range []string{"", "bytes 0-1023", "bytes 10-5/*", "bytes 0-2048/2048", "items 0-1/2"} {
+				_, err := utils.ParseContentRange(header)
+				Expect(err).To(HaveOccurred(), header)
+			}
+		})
+	})
+
+	Describe("ParseCommittedRange", func() {
+		It("should count committed bytes", func() {
+			Expect(utils.ParseCommittedRange("")).To(Equal(int64(0)))
+			Expect(utils.ParseCommittedRange("bytes=0-1023")).To(Equal(int64(1024)))
+			Expect(utils.FormatCommittedRange(1024)).To(Equal("bytes=0-1023"))
+			Expect(utils.FormatCommittedRange(0)).To(BeEmpty())
+
+			_, err := utils.ParseCommittedRange("bytes=5-10")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("StartResumableUpload and QueryResumableUpload", func() {
+		It("should open a session and read its committed offset", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodPost && r.Header.Get(utils.ResumableHeader) == utils.ResumableStart:
+					Expect(r.Header.Get("Content-Type")).To(Equal("audio/flac"))
+					w.Header().Set("Location", "http://"+r.Host+"/session")
+					w.WriteHeader(http.StatusCreated)
+				case r.Method == http.MethodPut && r.URL.Path == "/session":
+					Expect(r.Header.Get("Content-Range")).To(Equal("bytes */*"))
+					w.Header().Set("Range", "bytes=0-4095")
+					w.WriteHeader(utils.StatusResumeIncomplete)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			sessionURL, err := utils.StartResumableUpload(context.Background(), server.Client(), server.URL+"/start", map[string]string{"Content-Type": "audio/flac"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sessionURL).To(Equal(server.URL + "/session"))
+
+			offset, complete, err := utils.QueryResumableUpload(context.Background(), server.Client(), sessionURL)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(complete).To(BeFalse())
+			Expect(offset).To(Equal(int64(4096)))
+
+			_, _, err = utils.QueryResumableUpload(context.Background(), server.Client(), server.URL+"/gone")
+			Expect(err).To(MatchError(utils.ErrUploadSessionExpired))
+		})
+	})
+})
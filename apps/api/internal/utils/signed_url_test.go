@@ -0,0 +1,60 @@
+package utils_test
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/wavlake/monorepo/internal/models"
+	"github.com/wavlake/monorepo/internal/utils"
+)
+
+var _ = Describe("File server signed URLs", func() {
+	key := []byte("test-key")
+
+	parse := func(signedURL string) (string, url.Values) {
+		u, err := url.Parse(signedURL)
+		Expect(err).ToNot(HaveOccurred())
+		return strings.TrimPrefix(u.Path, utils.SignedURLPrefix), u.Query()
+	}
+
+	It("should verify a URL it built", func() {
+		signedURL := utils.BuildFileServerSignedURL("http://localhost:8081/", key, "tracks/original/a.mp3", time.Now().Add(time.Minute), models.SignedURLOptions{
+			ContentDisposition: `attachment; filename="a.mp3"`,
+		})
+		Expect(signedURL).To(HavePrefix("http://localhost:8081/signed/tracks/original/a.mp3?"))
+
+		path, query := parse(signedURL)
+		Expect(query.Get(utils.SignedURLDispositionParam)).To(Equal(`attachment; filename="a.mp3"`))
+		Expect(utils.VerifyFileServerSignature(key, http.MethodGet, path, query, time.Now())).To(Succeed())
+		Expect(utils.VerifyFileServerSignature(key, http.MethodHead, path, query, time.Now())).To(Succeed())
+	})
+
+	It("should reject tampered, expired and wrong-method requests", func() {
+		path, query := parse(utils.BuildFileServerSignedURL("http://localhost:8081", key, "a.mp3", time.Now().Add(time.Minute), models.SignedURLOptions{Method: http.MethodGet}))
+
+		Expect(utils.VerifyFileServerSignature(key, http.MethodGet, "b.mp3", query, time.Now())).ToNot(Succeed())
+		Expect(utils.VerifyFileServerSignature(key, http.MethodPut, path, query, time.Now())).ToNot(Succeed())
+		Expect(utils.VerifyFileServerSignature([]byte("other"), http.MethodGet, path, query, time.Now())).ToNot(Succeed())
+		Expect(utils.VerifyFileServerSignature(key, http.MethodGet, path, query, time.Now().Add(2*time.Minute))).ToNot(Succeed())
+
+		query.Set(utils.SignedURLDispositionParam, "attachment")
+		Expect(utils.VerifyFileServerSignature(key, http.MethodGet, path, query, time.Now())).ToNot(Succeed())
+	})
+
+	It("should sign the upload size limit of PUT URLs", func() {
+		path, query := parse(utils.BuildFileServerSignedURL("http://localhost:8081", key, "a.mp3", time.Now().Add(time.Minute), models.SignedURLOptions{
+			Method:           http.MethodPut,
+			MaxContentLength: 1024,
+		}))
+		Expect(query.Get(utils.SignedURLMaxLengthParam)).To(Equal("1024"))
+		Expect(utils.VerifyFileServerSignature(key, http.MethodPut, path, query, time.Now())).To(Succeed())
+
+		query.Set(utils.SignedURLMaxLengthParam, "4096")
+		Expect(utils.VerifyFileServerSignature(key, http.MethodPut, path, query, time.Now())).ToNot(Succeed())
+	})
+})
@@ -8,16 +8,18 @@ import (
 type StoragePathConfig struct {
 	OriginalPrefix   string
 	CompressedPrefix string
+	ArtworkPrefix    string
 	UseLegacyPaths   bool
 }
 
 // GetStoragePathConfig returns a fixed path configuration for GCS storage.
-// The paths are set to standard prefixes: 'tracks/original' and 'tracks/compressed'.
+// The paths are set to standard prefixes: 'tracks/original', 'tracks/compressed' and 'artwork'.
 
 func GetStoragePathConfig() *StoragePathConfig {
 	config := &StoragePathConfig{
 		OriginalPrefix:   "tracks/original",
 		CompressedPrefix: "tracks/compressed",
+		ArtworkPrefix:    "artwork",
 		UseLegacyPaths:   false,
 	}
 
@@ -39,6 +41,40 @@ func (c *StoragePathConfig) GetCompressedVersionPath(trackID, versionID, format
 	return fmt.Sprintf("%s/%s_%s.%s", c.CompressedPrefix, trackID, versionID, format)
 }
 
+// GetHLSPath returns the storage path for a file of an HLS package. Packages live in a
+// "<trackID>_<versionID>" directory under the compressed prefix, e.g. master.m3u8 or 128k/index.m3u8.
+func (c *StoragePathConfig) GetHLSPath(trackID, versionID, fileName string) string {
+	return fmt.Sprintf("%s/%s_%s/%s", c.CompressedPrefix, trackID, versionID, fileName)
+}
+
+// GetWaveformPath returns the storage path for a track's waveform peak data at one resolution.
+// Waveforms live under the compressed prefix so GetTrackIDFromPath resolves their track.
+func (c *StoragePathConfig) GetWaveformPath(trackID string, samplesPerPixel int) string {
+	return fmt.Sprintf("%s/%s_waveform_%d.json", c.CompressedPrefix, trackID, samplesPerPixel)
+}
+
+// GetDerivedFilesPrefix returns the prefix shared by every file generated from a track:
+// compressed versions, HLS packages and waveforms
+func (c *StoragePathConfig) GetDerivedFilesPrefix(trackID string) string {
+	return fmt.Sprintf("%s/%s_", c.CompressedPrefix, trackID)
+}
+
+// GetArtworkOriginalPath returns the storage path for an uploaded artwork image
+func (c *StoragePathConfig) GetArtworkOriginalPath(artworkID, extension string) string {
+	return fmt.Sprintf("%s/original/%s.%s", c.ArtworkPrefix, artworkID, extension)
+}
+
+// GetArtworkVariantPath returns the storage path for a resized artwork variant
+func (c *StoragePathConfig) GetArtworkVariantPath(artworkID string, size int, format string) string {
+	return fmt.Sprintf("%s/%s/%d.%s", c.ArtworkPrefix, artworkID, size, format)
+}
+
+// IsArtworkPath checks if a given path is in the artwork directory
+func (c *StoragePathConfig) IsArtworkPath(objectPath string) bool {
+	expectedPrefix := c.ArtworkPrefix + "/"
+	return len(objectPath) > len(expectedPrefix) && objectPath[:len(expectedPrefix)] == expectedPrefix
+}
+
 // IsOriginalPath checks if a given path is in the original files directory
 func (c *StoragePathConfig) IsOriginalPath(objectPath string) bool {
 	expectedPrefix := c.OriginalPrefix + "/"
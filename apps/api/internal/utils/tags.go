@@ -0,0 +1,170 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"image"
+	_ "image/jpeg" // Register decoders for cover art dimensions
+	_ "image/png"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/wavlake/monorepo/internal/models"
+)
+
+// OutputTags is the metadata written into compressed outputs, built from the track record
+type OutputTags struct {
+	Title        string
+	Artist       string
+	Album        string
+	AlbumArtist  string
+	Genre        string
+	TrackNumber  int
+	Year         int
+	URL          string // Public track page
+	CoverArtPath string // Local JPEG or PNG embedded as the front cover
+}
+
+// EncodeContext carries per-track data used when encoding a compressed version
+type EncodeContext struct {
+	Loudness *models.LoudnessInfo // Measurements of the input from AnalyzeLoudness
+	Tags     *OutputTags          // Metadata written into the output, nil to keep the source tags
+}
+
+// Fields returns the tags as ffmpeg metadata keys. ffmpeg maps these to ID3v2 frames for MP3
+// and iTunes atoms for MP4; for Ogg they are written as Vorbis comments as-is.
+func (t *OutputTags) Fields() map[string]string {
+	fields := make(map[string]string)
+	set := func(key, value string) {
+		if value = strings.TrimSpace(value); value != "" {
+			fields[key] = value
+		}
+	}
+
+	set("title", t.Title)
+	set("artist", t.Artist)
+	set("album", t.Album)
+	set("album_artist", t.AlbumArtist)
+	set("genre", t.Genre)
+	if t.TrackNumber > 0 {
+		set("track", strconv.Itoa(t.TrackNumber))
+	}
+	if t.Year > 0 {
+		set("date", strconv.Itoa(t.Year))
+	}
+	// MP4 only carries a fixed set of atoms, so the URL also goes in the comment
+	set("comment", t.URL)
+	set("url", t.URL)
+	return fields
+}
+
+// WriteFFMetadata writes fields to an ffmetadata file for use with -map_metadata. Values go
+// through a file rather than the command line so long values such as cover art fit.
+func WriteFFMetadata(path string, fields map[string]string) error {
+	var b strings.Builder
+	b.WriteString(";FFMETADATA1\n")
+	for key, value := range fields {
+		b.WriteString(escapeFFMetadata(key) + "=" + escapeFFMetadata(value) + "\n")
+	}
+
+	// #nosec G306 -- Temporary metadata file next to the output
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write metadata file: %w", err)
+	}
+	return nil
+}
+
+// escapeFFMetadata escapes the characters the ffmetadata format treats as special
+func escapeFFMetadata(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		switch r {
+		case '=', ';', '#', '\\', '\n':
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// VorbisPictureBlock encodes an image as a base64 FLAC picture block, the form Vorbis
+// comments use for embedded cover art (METADATA_BLOCK_PICTURE)
+func VorbisPictureBlock(imagePath string) (string, error) {
+	data, err := os.ReadFile(imagePath) // #nosec G304 -- Reading controlled temp file
+	if err != nil {
+		return "", fmt.Errorf("failed to read cover art: %w", err)
+	}
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode cover art: %w", err)
+	}
+	mimeType := "image/" + format
+
+	var block bytes.Buffer
+	write := func(value uint32) {
+		_ = binary.Write(&block, binary.BigEndian, value) // #nosec G104 -- Writes to a bytes.Buffer cannot fail
+	}
+	write(3) // Front cover
+	write(uint32(len(mimeType)))
+	block.WriteString(mimeType)
+	write(0)                     // No description
+	write(uint32(config.Width))  // #nosec G115 -- Image dimensions are positive
+	write(uint32(config.Height)) // #nosec G115 -- Image dimensions are positive
+	write(24)                    // Color depth
+	write(0)                     // Not an indexed-color image
+	write(uint32(len(data)))     // #nosec G115 -- Cover art is far below 4GB
+	block.Write(data)
+
+	return base64.StdEncoding.EncodeToString(block.Bytes()), nil
+}
+
+// tagArgs returns the extra ffmpeg inputs and output options that embed tags and cover art.
+// nextInput is the index the first extra input will get. The caller removes the metadata file.
+func tagArgs(tags *OutputTags, format, outputPath string, nextInput int) (inputs, options []string, metadataPath string, err error) {
+	fields := tags.Fields()
+
+	container := ContainerForFormat(format)
+	if tags.CoverArtPath != "" && container == "ogg" {
+		// Ogg cannot carry a picture stream; Vorbis comments hold it instead. FLAC takes it
+		// as an attached picture like MP3 and MP4.
+		picture, err := VorbisPictureBlock(tags.CoverArtPath)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		fields["METADATA_BLOCK_PICTURE"] = picture
+	}
+
+	metadataPath = strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + ".ffmetadata"
+	if err := WriteFFMetadata(metadataPath, fields); err != nil {
+		return nil, nil, "", err
+	}
+
+	inputs = []string{"-f", "ffmetadata", "-i", metadataPath}
+	// Drop tags from the original so only the track record's metadata is written
+	options = []string{"-map_metadata", strconv.Itoa(nextInput)}
+	nextInput++
+
+	// WebM only allows VP8/VP9/AV1 video, so it has no way to carry cover art
+	if tags.CoverArtPath != "" && container != "ogg" && container != "webm" {
+		inputs = append(inputs, "-i", tags.CoverArtPath)
+		options = append(options,
+			"-map", fmt.Sprintf("%d:v", nextInput),
+			"-c:v", "copy",
+			"-disposition:v", "attached_pic",
+			"-metadata:s:v", "title=Album cover",
+			"-metadata:s:v", "comment=Cover (front)",
+		)
+	}
+
+	if container == "mp3" {
+		// ID3v2.3 is the most widely supported version for embedded art
+		options = append(options, "-id3v2_version", "3")
+	}
+
+	return inputs, options, metadataPath, nil
+}
@@ -0,0 +1,98 @@
+package utils_test
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/wavlake/monorepo/internal/utils"
+)
+
+var _ = Describe("Output tags", func() {
+	Describe("Fields", func() {
+		It("should map the track metadata to ffmpeg keys", func() {
+			tags := &utils.OutputTags{
+				Title:       "Song",
+				Artist:      "Artist",
+				Album:       "Album",
+				AlbumArtist: "Artist",
+				Genre:       "Rock",
+				TrackNumber: 3,
+				Year:        2024,
+				URL:         "https://wavlake.com/track/abc",
+			}
+
+			fields := tags.Fields()
+			Expect(fields).To(Equal(map[string]string{
+				"title":        "Song",
+				"artist":       "Artist",
+				"album":        "Album",
+				"album_artist": "Artist",
+				"genre":        "Rock",
+				"track":        "3",
+				"date":         "2024",
+				"comment":      "https://wavlake.com/track/abc",
+				"url":          "https://wavlake.com/track/abc",
+			}))
+		})
+
+		It("should omit empty values", func() {
+			tags := &utils.OutputTags{Title: "Song", Artist: "  "}
+			Expect(tags.Fields()).To(Equal(map[string]string{"title": "Song"}))
+		})
+	})
+
+	Describe("WriteFFMetadata", func() {
+		It("should escape special characters", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "tags.ffmetadata")
+			Expect(utils.WriteFFMetadata(path, map[string]string{"title": "a=b;c#d\\e\nf"})).To(Succeed())
+
+			data, err := os.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(Equal(";FFMETADATA1\ntitle=a\\=b\\;c\\#d\\\\e\\\nf\n"))
+		})
+	})
+
+	Describe("VorbisPictureBlock", func() {
+		It("should encode a front cover picture block", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "cover.png")
+			file, err := os.Create(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(png.Encode(file, image.NewRGBA(image.Rect(0, 0, 4, 2)))).To(Succeed())
+			Expect(file.Close()).To(Succeed())
+
+			encoded, err := utils.VorbisPictureBlock(path)
+			Expect(err).NotTo(HaveOccurred())
+
+			block, err := base64.StdEncoding.DecodeString(encoded)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(binary.BigEndian.Uint32(block[0:4])).To(Equal(uint32(3)))
+			mimeLength := binary.BigEndian.Uint32(block[4:8])
+			Expect(string(block[8 : 8+mimeLength])).To(Equal("image/png"))
+
+			rest := block[8+mimeLength+4:] // Skip the empty description
+			Expect(binary.BigEndian.Uint32(rest[0:4])).To(Equal(uint32(4)))
+			Expect(binary.BigEndian.Uint32(rest[4:8])).To(Equal(uint32(2)))
+
+			image, err := os.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(binary.BigEndian.Uint32(rest[16:20])).To(Equal(uint32(len(image))))
+			Expect(rest[20:]).To(Equal(image))
+		})
+
+		It("should reject files that are not images", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "cover.png")
+			Expect(os.WriteFile(path, []byte(strings.Repeat("x", 16)), 0644)).To(Succeed())
+
+			_, err := utils.VorbisPictureBlock(path)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
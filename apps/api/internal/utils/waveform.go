@@ -0,0 +1,188 @@
+package utils
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+)
+
+const (
+	// WaveformSampleRate is the rate the original is decoded at for peak extraction
+	WaveformSampleRate = 22050
+	// WaveformVersion is the audiowaveform JSON format version produced
+	WaveformVersion = 2
+	// WaveformBits is the sample resolution of the peak data
+	WaveformBits = 8
+)
+
+// PrimaryWaveformResolution is the zoom level exposed as a track's waveform_url
+const PrimaryWaveformResolution = 1024
+
+// DefaultWaveformResolutions are the samples-per-pixel zoom levels generated for each track,
+// from detailed to overview. Each must be a multiple of the first.
+var DefaultWaveformResolutions = []int{256, 1024, 4096}
+
+// Waveform is peak data in the audiowaveform JSON format: Data holds a min/max pair per
+// pixel, mixed down to one channel
+type Waveform struct {
+	Version         int    `json:"version"`
+	Channels        int    `json:"channels"`
+	SampleRate      int    `json:"sample_rate"`
+	SamplesPerPixel int    `json:"samples_per_pixel"`
+	Bits            int    `json:"bits"`
+	Length          int    `json:"length"` // Number of min/max pairs
+	Data            []int8 `json:"data"`
+}
+
+// GenerateWaveforms decodes the input to mono PCM and computes peak data at each resolution
+func (ap *AudioProcessor) GenerateWaveforms(ctx context.Context, inputPath string, resolutions []int) ([]*Waveform, error) {
+	stdout, pcm := io.Pipe()
+	decodeErr := make(chan error, 1)
+	go func() {
+		result, err := ap.executor.Stream(ctx, pcm, "ffmpeg",
+			"-v", "error",
+			"-i", inputPath,
+			"-vn",
+			"-ac", "1",
+			"-ar", fmt.Sprintf("%d", WaveformSampleRate),
+			"-f", "s16le",
+			"-")
+		if err != nil {
+			err = fmt.Errorf("%w, output: %s", err, result.Stderr)
+		}
+		pcm.CloseWithError(err)
+		decodeErr <- err
+	}()
+
+	waveforms, peakErr := ComputeWaveforms(stdout, WaveformSampleRate, resolutions)
+	if peakErr != nil {
+		// Drain so ffmpeg is not blocked writing to a full pipe
+		_, _ = io.Copy(io.Discard, stdout) // #nosec G104 -- Output is discarded after a failure
+	}
+	if err := <-decodeErr; err != nil {
+		return nil, fmt.Errorf("failed to decode audio for waveform: %w", err)
+	}
+	if peakErr != nil {
+		return nil, peakErr
+	}
+
+	log.Printf("Successfully generated %d waveform resolutions for %s", len(waveforms), inputPath)
+	return waveforms, nil
+}
+
+// ComputeWaveforms reads mono signed 16-bit little-endian PCM and returns one waveform per
+// resolution, ordered from most to least detailed
+func ComputeWaveforms(pcm io.Reader, sampleRate int, resolutions []int) ([]*Waveform, error) {
+	resolutions = normalizeWaveformResolutions(resolutions)
+	base := resolutions[0]
+	for _, resolution := range resolutions[1:] {
+		if resolution%base != 0 {
+			return nil, fmt.Errorf("waveform resolution %d is not a multiple of %d", resolution, base)
+		}
+	}
+
+	// Compute the finest resolution from the PCM, then merge pairs for coarser ones
+	var data []int8
+	reader := bufio.NewReader(pcm)
+	var sample [2]byte
+	count := 0
+	var minPeak, maxPeak int8
+	for {
+		if _, err := io.ReadFull(reader, sample[:]); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				break
+			}
+			return nil, fmt.Errorf("failed to read PCM: %w", err)
+		}
+
+		value := int8(int16(binary.LittleEndian.Uint16(sample[:])) >> 8)
+		if count == 0 || value < minPeak {
+			minPeak = value
+		}
+		if count == 0 || value > maxPeak {
+			maxPeak = value
+		}
+
+		count++
+		if count == base {
+			data = append(data, minPeak, maxPeak)
+			count = 0
+		}
+	}
+	if count > 0 {
+		data = append(data, minPeak, maxPeak)
+	}
+
+	waveforms := make([]*Waveform, 0, len(resolutions))
+	for _, resolution := range resolutions {
+		waveforms = append(waveforms, newWaveform(sampleRate, resolution, mergePeaks(data, resolution/base)))
+	}
+	return waveforms, nil
+}
+
+// mergePeaks combines every factor min/max pairs into one
+func mergePeaks(data []int8, factor int) []int8 {
+	if factor == 1 {
+		return data
+	}
+
+	merged := make([]int8, 0, len(data)/factor+2)
+	for start := 0; start < len(data); start += 2 * factor {
+		end := start + 2*factor
+		if end > len(data) {
+			end = len(data)
+		}
+		minPeak, maxPeak := data[start], data[start+1]
+		for i := start + 2; i < end; i += 2 {
+			if data[i] < minPeak {
+				minPeak = data[i]
+			}
+			if data[i+1] > maxPeak {
+				maxPeak = data[i+1]
+			}
+		}
+		merged = append(merged, minPeak, maxPeak)
+	}
+	return merged
+}
+
+func newWaveform(sampleRate, samplesPerPixel int, data []int8) *Waveform {
+	if data == nil {
+		data = []int8{}
+	}
+	return &Waveform{
+		Version:         WaveformVersion,
+		Channels:        1,
+		SampleRate:      sampleRate,
+		SamplesPerPixel: samplesPerPixel,
+		Bits:            WaveformBits,
+		Length:          len(data) / 2,
+		Data:            data,
+	}
+}
+
+// normalizeWaveformResolutions returns the resolutions sorted and de-duplicated, falling back
+// to DefaultWaveformResolutions when none are valid
+func normalizeWaveformResolutions(resolutions []int) []int {
+	seen := make(map[int]bool)
+	var normalized []int
+	for _, resolution := range resolutions {
+		if resolution <= 0 || seen[resolution] {
+			continue
+		}
+		seen[resolution] = true
+		normalized = append(normalized, resolution)
+	}
+
+	if len(normalized) == 0 {
+		normalized = append(normalized, DefaultWaveformResolutions...)
+	}
+
+	sort.Ints(normalized)
+	return normalized
+}
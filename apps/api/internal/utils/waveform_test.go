@@ -0,0 +1,61 @@
+package utils_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/wavlake/monorepo/internal/utils"
+)
+
+var _ = Describe("Waveform", func() {
+	pcm := func(samples ...int16) *bytes.Reader {
+		var buf bytes.Buffer
+		Expect(binary.Write(&buf, binary.LittleEndian, samples)).To(Succeed())
+		return bytes.NewReader(buf.Bytes())
+	}
+
+	Describe("ComputeWaveforms", func() {
+		It("should compute min/max pairs per pixel at each resolution", func() {
+			waveforms, err := utils.ComputeWaveforms(pcm(256, -512, 32767, -32768, 1024, 0), 22050, []int{4, 2})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(waveforms).To(HaveLen(2))
+
+			Expect(waveforms[0].SamplesPerPixel).To(Equal(2))
+			Expect(waveforms[0].Length).To(Equal(3))
+			Expect(waveforms[0].Data).To(Equal([]int8{-2, 1, -128, 127, 0, 4}))
+
+			Expect(waveforms[1].SamplesPerPixel).To(Equal(4))
+			Expect(waveforms[1].Length).To(Equal(2))
+			Expect(waveforms[1].Data).To(Equal([]int8{-128, 127, 0, 4}))
+		})
+
+		It("should reject resolutions that are not multiples of the finest", func() {
+			_, err := utils.ComputeWaveforms(pcm(0), 22050, []int{256, 300})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should encode as audiowaveform JSON", func() {
+			waveforms, err := utils.ComputeWaveforms(pcm(256, -512), 22050, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(waveforms).To(HaveLen(len(utils.DefaultWaveformResolutions)))
+
+			data, err := json.Marshal(waveforms[0])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(data)).To(MatchJSON(`{"version":2,"channels":1,"sample_rate":22050,"samples_per_pixel":256,"bits":8,"length":1,"data":[-2,1]}`))
+		})
+	})
+
+	Describe("GetWaveformPath", func() {
+		It("should place waveforms under the compressed prefix so the track ID can be recovered", func() {
+			config := utils.GetStoragePathConfig()
+			path := config.GetWaveformPath("track-123", 1024)
+
+			Expect(path).To(Equal("tracks/compressed/track-123_waveform_1024.json"))
+			Expect(config.GetTrackIDFromPath(path)).To(Equal("track-123"))
+		})
+	})
+})
@@ -82,7 +82,7 @@ func (suite *APIIntegrationTestSuite) SetupSuite() {
 	if storageService != nil {
 		// In real tests, we'd use a test Firestore instance
 		// For now, we'll test what we can without external dependencies
-		audioProcessor = utils.NewAudioProcessor("/tmp")
+		audioProcessor = utils.NewAudioProcessor("/tmp", nil)
 	}
 	
 	// Initialize handlers
@@ -45,7 +45,7 @@ func (suite *AudioPipelineTestSuite) SetupSuite() {
 	suite.createTestAudioFile()
 	
 	// Initialize audio processor
-	suite.audioProcessor = utils.NewAudioProcessor(suite.tempDir)
+	suite.audioProcessor = utils.NewAudioProcessor(suite.tempDir, nil)
 	
 	// Initialize storage service (mock for testing)
 	storageService, err := services.NewStorageService(suite.ctx, "test-bucket")
@@ -65,6 +65,10 @@ func (suite *AudioPipelineTestSuite) SetupSuite() {
 			nostrTrackService,
 			suite.audioProcessor,
 			suite.tempDir,
+			config.NewProcessingConfig(),
+			nil,
+			nil,
+			nil,
 		)
 	}
 }
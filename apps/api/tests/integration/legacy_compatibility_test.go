@@ -75,7 +75,7 @@ func (suite *LegacyCompatibilityTestSuite) SetupSuite() {
 	var processingService *services.ProcessingService
 	
 	if storageService != nil {
-		audioProcessor = utils.NewAudioProcessor("/tmp")
+		audioProcessor = utils.NewAudioProcessor("/tmp", nil)
 	}
 	
 	// Initialize handlers
@@ -12,6 +12,7 @@ import (
 	firebase "firebase.google.com/go/v4"
 	"google.golang.org/api/option"
 
+	appconfig "github.com/wavlake/monorepo/internal/config"
 	"github.com/wavlake/monorepo/internal/models"
 	"github.com/wavlake/monorepo/internal/services"
 	"github.com/wavlake/monorepo/tests/testutil"
@@ -48,6 +49,22 @@ func (m *mockStorageService) GetObjectReader(ctx context.Context, objectName str
 	return nil, nil
 }
 
+func (m *mockStorageService) ListObjects(ctx context.Context, prefix string) ([]string, error) {
+	return nil, nil
+}
+
+func (m *mockStorageService) GetObjectRangeReader(ctx context.Context, objectName string, offset, length int64) (io.ReadCloser, error) {
+	return m.GetObjectReader(ctx, objectName)
+}
+
+func (m *mockStorageService) GetObjectInfo(ctx context.Context, objectName string) (*models.FileMetadata, error) {
+	return &models.FileMetadata{Name: objectName}, nil
+}
+
+func (m *mockStorageService) GenerateSignedURL(ctx context.Context, objectName string, opts models.SignedURLOptions) (string, error) {
+	return "https://mock-storage.example.com/signed/" + objectName, nil
+}
+
 func (m *mockStorageService) GetBucketName() string {
 	return "test-bucket"
 }
@@ -67,6 +84,10 @@ func (m *mockPathConfig) GetCompressedPath(trackID string) string {
 	return "compressed/" + trackID + ".mp3"
 }
 
+func (m *mockPathConfig) GetDerivedFilesPrefix(trackID string) string {
+	return "compressed/" + trackID + "_"
+}
+
 // TestNostrTrackServiceWithFirebaseEmulators tests the actual NostrTrackService implementation
 // with real Firebase emulator instances
 func TestNostrTrackServiceWithFirebaseEmulators(t *testing.T) {
@@ -100,7 +121,7 @@ func TestNostrTrackServiceWithFirebaseEmulators(t *testing.T) {
 	mockPaths := &mockPathConfig{}
 
 	// Create NostrTrackService with real Firestore client and mock dependencies
-	trackService := services.NewNostrTrackService(firestoreClient, mockStorage, mockPaths)
+	trackService := services.NewNostrTrackService(firestoreClient, mockStorage, mockPaths, nil)
 
 	// Set up test data
 	testFirebaseUID := testutil.TestFirebaseUID
@@ -425,4 +446,78 @@ func TestNostrTrackServiceWithFirebaseEmulators(t *testing.T) {
 			t.Error("Expected HasPendingCompression to be false after adding compression version")
 		}
 	})
+	t.Run("FindTracksByFileHash_RealImplementation", func(t *testing.T) {
+		const fileHash = "4f1d6e0c2b9a"
+		now := time.Now()
+
+		// Setup: tracks sharing one hash, by both pubkeys and at different ages
+		createWithHash := func(pubkey, hash string, age time.Duration) *models.NostrTrack {
+			track, err := trackService.CreateTrack(ctx, pubkey, testFirebaseUID, testExtension)
+			if err != nil {
+				t.Fatalf("Setup failed: %v", err)
+			}
+			track.FileHash = hash
+			track.CreatedAt = now.Add(-age)
+			if err := trackService.UpdateTrack(ctx, track.ID, map[string]interface{}{
+				"file_hash":  hash,
+				"created_at": track.CreatedAt,
+			}); err != nil {
+				t.Fatalf("Setup failed: %v", err)
+			}
+			return track
+		}
+
+		upload := createWithHash(testPubkey, fileHash, 0)
+		ownEarlier := createWithHash(testPubkey, fileHash, 3*time.Hour)
+		otherNewer := createWithHash(testutil.TestPubkey2, fileHash, time.Hour)
+		otherOldest := createWithHash(testutil.TestPubkey2, fileHash, 2*time.Hour)
+		deleted := createWithHash(testutil.TestPubkey2, fileHash, 4*time.Hour)
+		createWithHash(testutil.TestPubkey2, "different-hash", 5*time.Hour)
+		if err := trackService.DeleteTrack(ctx, deleted.ID); err != nil {
+			t.Fatalf("Setup failed: %v", err)
+		}
+
+		// Act
+		matches, err := trackService.FindTracksByFileHash(ctx, fileHash)
+		if err != nil {
+			t.Fatalf("FindTracksByFileHash failed: %v", err)
+		}
+
+		// Assert: every live track with the hash, but not deleted ones or other hashes
+		found := make(map[string]bool)
+		for _, match := range matches {
+			found[match.ID] = true
+		}
+		for _, track := range []*models.NostrTrack{upload, ownEarlier, otherNewer, otherOldest} {
+			if !found[track.ID] {
+				t.Errorf("Expected track %s in matches", track.ID)
+			}
+		}
+		if len(matches) != 4 {
+			t.Errorf("Expected 4 matches, got %d", len(matches))
+		}
+
+		// The same pubkey's re-upload is ignored and the oldest other match wins, under either policy
+		duplicate, reject := services.FindDuplicate(upload, matches, appconfig.DuplicatePolicyFlag)
+		if duplicate == nil || duplicate.ID != otherOldest.ID {
+			t.Errorf("Expected duplicate %s, got %v", otherOldest.ID, duplicate)
+		}
+		if reject {
+			t.Error("Expected the flag policy not to reject")
+		}
+
+		duplicate, reject = services.FindDuplicate(upload, matches, appconfig.DuplicatePolicyReject)
+		if duplicate == nil || duplicate.ID != otherOldest.ID {
+			t.Errorf("Expected duplicate %s, got %v", otherOldest.ID, duplicate)
+		}
+		if !reject {
+			t.Error("Expected the reject policy to reject")
+		}
+
+		// A pubkey with only its own uploads has no duplicate
+		duplicate, _ = services.FindDuplicate(otherNewer, []*models.NostrTrack{otherNewer, otherOldest}, appconfig.DuplicatePolicyReject)
+		if duplicate != nil {
+			t.Errorf("Expected no duplicate among one pubkey's uploads, got %s", duplicate.ID)
+		}
+	})
 }
\ No newline at end of file
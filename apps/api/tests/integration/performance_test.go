@@ -74,7 +74,7 @@ func (suite *PerformanceTestSuite) SetupSuite() {
 	var processingService *services.ProcessingService
 	
 	if storageService != nil {
-		audioProcessor = utils.NewAudioProcessor("/tmp")
+		audioProcessor = utils.NewAudioProcessor("/tmp", nil)
 	}
 	
 	// Initialize handlers
@@ -13,6 +13,7 @@ import (
 	firebase "firebase.google.com/go/v4"
 	"google.golang.org/api/option"
 
+	appconfig "github.com/wavlake/monorepo/internal/config"
 	"github.com/wavlake/monorepo/internal/models"
 	"github.com/wavlake/monorepo/internal/services"
 	"github.com/wavlake/monorepo/internal/utils"
@@ -58,6 +59,22 @@ func (m *mockStorageServiceForProcessing) GetObjectReader(ctx context.Context, o
 	return io.NopCloser(strings.NewReader(mockAudioData)), nil
 }
 
+func (m *mockStorageServiceForProcessing) ListObjects(ctx context.Context, prefix string) ([]string, error) {
+	return nil, nil
+}
+
+func (m *mockStorageServiceForProcessing) GetObjectRangeReader(ctx context.Context, objectName string, offset, length int64) (io.ReadCloser, error) {
+	return m.GetObjectReader(ctx, objectName)
+}
+
+func (m *mockStorageServiceForProcessing) GetObjectInfo(ctx context.Context, objectName string) (*models.FileMetadata, error) {
+	return &models.FileMetadata{Name: objectName}, nil
+}
+
+func (m *mockStorageServiceForProcessing) GenerateSignedURL(ctx context.Context, objectName string, opts models.SignedURLOptions) (string, error) {
+	return "https://mock-storage.example.com/signed/" + objectName, nil
+}
+
 func (m *mockStorageServiceForProcessing) GetBucketName() string {
 	return "test-bucket"
 }
@@ -110,10 +127,10 @@ func TestProcessingServiceWithFirebaseEmulators(t *testing.T) {
 	
 	// Use real AudioProcessor but override commands with mocked executables
 	// This will allow testing the workflow without requiring actual ffmpeg
-	realAudioProcessor := utils.NewAudioProcessor(tempDir)
+	realAudioProcessor := utils.NewAudioProcessor(tempDir, nil)
 
 	// Create NostrTrackService with real Firestore
-	nostrTrackService := services.NewNostrTrackService(firestoreClient, mockStorage, mockPaths)
+	nostrTrackService := services.NewNostrTrackService(firestoreClient, mockStorage, mockPaths, nil)
 
 	// Create ProcessingService with real audio processor but mocked storage
 	processingService := services.NewProcessingService(
@@ -121,6 +138,10 @@ func TestProcessingServiceWithFirebaseEmulators(t *testing.T) {
 		nostrTrackService,
 		realAudioProcessor,
 		tempDir,
+		appconfig.NewProcessingConfig(),
+		nil,
+		nil,
+		nil,
 	)
 
 	// Set up test data
@@ -223,6 +223,89 @@ func (mr *MockPostgresServiceInterfaceMockRecorder) GetUserTracks(ctx, firebaseU
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserTracks", reflect.TypeOf((*MockPostgresServiceInterface)(nil).GetUserTracks), ctx, firebaseUID)
 }
 
+// ListUserAlbums mocks base method.
+func (m *MockPostgresServiceInterface) ListUserAlbums(ctx context.Context, firebaseUID string, opts models.ListOptions) (*models.LegacyAlbumPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserAlbums", ctx, firebaseUID, opts)
+	ret0, _ := ret[0].(*models.LegacyAlbumPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserAlbums indicates an expected call of ListUserAlbums.
+func (mr *MockPostgresServiceInterfaceMockRecorder) ListUserAlbums(ctx, firebaseUID, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserAlbums", reflect.TypeOf((*MockPostgresServiceInterface)(nil).ListUserAlbums), ctx, firebaseUID, opts)
+}
+
+// ListUserArtists mocks base method.
+func (m *MockPostgresServiceInterface) ListUserArtists(ctx context.Context, firebaseUID string, opts models.ListOptions) (*models.LegacyArtistPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserArtists", ctx, firebaseUID, opts)
+	ret0, _ := ret[0].(*models.LegacyArtistPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserArtists indicates an expected call of ListUserArtists.
+func (mr *MockPostgresServiceInterfaceMockRecorder) ListUserArtists(ctx, firebaseUID, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserArtists", reflect.TypeOf((*MockPostgresServiceInterface)(nil).ListUserArtists), ctx, firebaseUID, opts)
+}
+
+// ListUserTracks mocks base method.
+func (m *MockPostgresServiceInterface) ListUserTracks(ctx context.Context, firebaseUID string, opts models.ListOptions, filter models.TrackFilter) (*models.LegacyTrackPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserTracks", ctx, firebaseUID, opts, filter)
+	ret0, _ := ret[0].(*models.LegacyTrackPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserTracks indicates an expected call of ListUserTracks.
+func (mr *MockPostgresServiceInterfaceMockRecorder) ListUserTracks(ctx, firebaseUID, opts, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserTracks", reflect.TypeOf((*MockPostgresServiceInterface)(nil).ListUserTracks), ctx, firebaseUID, opts, filter)
+}
+
+// MockLegacyMigrationServiceInterface is a mock of LegacyMigrationServiceInterface interface.
+type MockLegacyMigrationServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockLegacyMigrationServiceInterfaceMockRecorder
+}
+
+// MockLegacyMigrationServiceInterfaceMockRecorder is the mock recorder for MockLegacyMigrationServiceInterface.
+type MockLegacyMigrationServiceInterfaceMockRecorder struct {
+	mock *MockLegacyMigrationServiceInterface
+}
+
+// NewMockLegacyMigrationServiceInterface creates a new mock instance.
+func NewMockLegacyMigrationServiceInterface(ctrl *gomock.Controller) *MockLegacyMigrationServiceInterface {
+	mock := &MockLegacyMigrationServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockLegacyMigrationServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLegacyMigrationServiceInterface) EXPECT() *MockLegacyMigrationServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// MigrateUserTracks mocks base method.
+func (m *MockLegacyMigrationServiceInterface) MigrateUserTracks(ctx context.Context, firebaseUID, pubkey string, dryRun bool) (*models.LegacyMigrationReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MigrateUserTracks", ctx, firebaseUID, pubkey, dryRun)
+	ret0, _ := ret[0].(*models.LegacyMigrationReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MigrateUserTracks indicates an expected call of MigrateUserTracks.
+func (mr *MockLegacyMigrationServiceInterfaceMockRecorder) MigrateUserTracks(ctx, firebaseUID, pubkey, dryRun interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MigrateUserTracks", reflect.TypeOf((*MockLegacyMigrationServiceInterface)(nil).MigrateUserTracks), ctx, firebaseUID, pubkey, dryRun)
+}
+
 // MockStorageServiceInterface is a mock of StorageServiceInterface interface.
 type MockStorageServiceInterface struct {
 	ctrl     *gomock.Controller
@@ -303,6 +386,21 @@ func (mr *MockStorageServiceInterfaceMockRecorder) GeneratePresignedURL(ctx, obj
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GeneratePresignedURL", reflect.TypeOf((*MockStorageServiceInterface)(nil).GeneratePresignedURL), ctx, objectName, expiration)
 }
 
+// GenerateSignedURL mocks base method.
+func (m *MockStorageServiceInterface) GenerateSignedURL(ctx context.Context, objectName string, opts models.SignedURLOptions) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateSignedURL", ctx, objectName, opts)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateSignedURL indicates an expected call of GenerateSignedURL.
+func (mr *MockStorageServiceInterfaceMockRecorder) GenerateSignedURL(ctx, objectName, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateSignedURL", reflect.TypeOf((*MockStorageServiceInterface)(nil).GenerateSignedURL), ctx, objectName, opts)
+}
+
 // GetBucketName mocks base method.
 func (m *MockStorageServiceInterface) GetBucketName() string {
 	m.ctrl.T.Helper()
@@ -317,6 +415,21 @@ func (mr *MockStorageServiceInterfaceMockRecorder) GetBucketName() *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBucketName", reflect.TypeOf((*MockStorageServiceInterface)(nil).GetBucketName))
 }
 
+// GetObjectInfo mocks base method.
+func (m *MockStorageServiceInterface) GetObjectInfo(ctx context.Context, objectName string) (*models.FileMetadata, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetObjectInfo", ctx, objectName)
+	ret0, _ := ret[0].(*models.FileMetadata)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetObjectInfo indicates an expected call of GetObjectInfo.
+func (mr *MockStorageServiceInterfaceMockRecorder) GetObjectInfo(ctx, objectName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetObjectInfo", reflect.TypeOf((*MockStorageServiceInterface)(nil).GetObjectInfo), ctx, objectName)
+}
+
 // GetObjectMetadata mocks base method.
 func (m *MockStorageServiceInterface) GetObjectMetadata(ctx context.Context, objectName string) (interface{}, error) {
 	m.ctrl.T.Helper()
@@ -332,6 +445,21 @@ func (mr *MockStorageServiceInterfaceMockRecorder) GetObjectMetadata(ctx, object
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetObjectMetadata", reflect.TypeOf((*MockStorageServiceInterface)(nil).GetObjectMetadata), ctx, objectName)
 }
 
+// GetObjectRangeReader mocks base method.
+func (m *MockStorageServiceInterface) GetObjectRangeReader(ctx context.Context, objectName string, offset, length int64) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetObjectRangeReader", ctx, objectName, offset, length)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetObjectRangeReader indicates an expected call of GetObjectRangeReader.
+func (mr *MockStorageServiceInterfaceMockRecorder) GetObjectRangeReader(ctx, objectName, offset, length interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetObjectRangeReader", reflect.TypeOf((*MockStorageServiceInterface)(nil).GetObjectRangeReader), ctx, objectName, offset, length)
+}
+
 // GetObjectReader mocks base method.
 func (m *MockStorageServiceInterface) GetObjectReader(ctx context.Context, objectName string) (io.ReadCloser, error) {
 	m.ctrl.T.Helper()
@@ -361,6 +489,21 @@ func (mr *MockStorageServiceInterfaceMockRecorder) GetPublicURL(objectName inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPublicURL", reflect.TypeOf((*MockStorageServiceInterface)(nil).GetPublicURL), objectName)
 }
 
+// ListObjects mocks base method.
+func (m *MockStorageServiceInterface) ListObjects(ctx context.Context, prefix string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListObjects", ctx, prefix)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListObjects indicates an expected call of ListObjects.
+func (mr *MockStorageServiceInterfaceMockRecorder) ListObjects(ctx, prefix interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListObjects", reflect.TypeOf((*MockStorageServiceInterface)(nil).ListObjects), ctx, prefix)
+}
+
 // UploadObject mocks base method.
 func (m *MockStorageServiceInterface) UploadObject(ctx context.Context, objectName string, data io.Reader, contentType string) error {
 	m.ctrl.T.Helper()
@@ -412,6 +555,21 @@ func (mr *MockNostrTrackServiceInterfaceMockRecorder) AddCompressionVersion(ctx,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCompressionVersion", reflect.TypeOf((*MockNostrTrackServiceInterface)(nil).AddCompressionVersion), ctx, trackID, version)
 }
 
+// CreateResumableTrack mocks base method.
+func (m *MockNostrTrackServiceInterface) CreateResumableTrack(ctx context.Context, pubkey, firebaseUID, extension string, size int64) (*models.NostrTrack, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateResumableTrack", ctx, pubkey, firebaseUID, extension, size)
+	ret0, _ := ret[0].(*models.NostrTrack)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateResumableTrack indicates an expected call of CreateResumableTrack.
+func (mr *MockNostrTrackServiceInterfaceMockRecorder) CreateResumableTrack(ctx, pubkey, firebaseUID, extension, size interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateResumableTrack", reflect.TypeOf((*MockNostrTrackServiceInterface)(nil).CreateResumableTrack), ctx, pubkey, firebaseUID, extension, size)
+}
+
 // CreateTrack mocks base method.
 func (m *MockNostrTrackServiceInterface) CreateTrack(ctx context.Context, pubkey, firebaseUID, extension string) (*models.NostrTrack, error) {
 	m.ctrl.T.Helper()
@@ -427,6 +585,20 @@ func (mr *MockNostrTrackServiceInterfaceMockRecorder) CreateTrack(ctx, pubkey, f
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTrack", reflect.TypeOf((*MockNostrTrackServiceInterface)(nil).CreateTrack), ctx, pubkey, firebaseUID, extension)
 }
 
+// DeleteCompressionVersion mocks base method.
+func (m *MockNostrTrackServiceInterface) DeleteCompressionVersion(ctx context.Context, trackID, versionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCompressionVersion", ctx, trackID, versionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCompressionVersion indicates an expected call of DeleteCompressionVersion.
+func (mr *MockNostrTrackServiceInterfaceMockRecorder) DeleteCompressionVersion(ctx, trackID, versionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCompressionVersion", reflect.TypeOf((*MockNostrTrackServiceInterface)(nil).DeleteCompressionVersion), ctx, trackID, versionID)
+}
+
 // DeleteTrack mocks base method.
 func (m *MockNostrTrackServiceInterface) DeleteTrack(ctx context.Context, trackID string) error {
 	m.ctrl.T.Helper()
@@ -441,6 +613,21 @@ func (mr *MockNostrTrackServiceInterfaceMockRecorder) DeleteTrack(ctx, trackID i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTrack", reflect.TypeOf((*MockNostrTrackServiceInterface)(nil).DeleteTrack), ctx, trackID)
 }
 
+// FindTracksByFileHash mocks base method.
+func (m *MockNostrTrackServiceInterface) FindTracksByFileHash(ctx context.Context, fileHash string) ([]*models.NostrTrack, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTracksByFileHash", ctx, fileHash)
+	ret0, _ := ret[0].([]*models.NostrTrack)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTracksByFileHash indicates an expected call of FindTracksByFileHash.
+func (mr *MockNostrTrackServiceInterfaceMockRecorder) FindTracksByFileHash(ctx, fileHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTracksByFileHash", reflect.TypeOf((*MockNostrTrackServiceInterface)(nil).FindTracksByFileHash), ctx, fileHash)
+}
+
 // GetTrack mocks base method.
 func (m *MockNostrTrackServiceInterface) GetTrack(ctx context.Context, trackID string) (*models.NostrTrack, error) {
 	m.ctrl.T.Helper()
@@ -500,6 +687,21 @@ func (mr *MockNostrTrackServiceInterfaceMockRecorder) HardDeleteTrack(ctx, track
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HardDeleteTrack", reflect.TypeOf((*MockNostrTrackServiceInterface)(nil).HardDeleteTrack), ctx, trackID)
 }
 
+// ListTracksByPubkey mocks base method.
+func (m *MockNostrTrackServiceInterface) ListTracksByPubkey(ctx context.Context, pubkey string, opts models.ListOptions, filter models.TrackFilter) (*models.NostrTrackPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTracksByPubkey", ctx, pubkey, opts, filter)
+	ret0, _ := ret[0].(*models.NostrTrackPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTracksByPubkey indicates an expected call of ListTracksByPubkey.
+func (mr *MockNostrTrackServiceInterfaceMockRecorder) ListTracksByPubkey(ctx, pubkey, opts, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTracksByPubkey", reflect.TypeOf((*MockNostrTrackServiceInterface)(nil).ListTracksByPubkey), ctx, pubkey, opts, filter)
+}
+
 // MarkTrackAsCompressed mocks base method.
 func (m *MockNostrTrackServiceInterface) MarkTrackAsCompressed(ctx context.Context, trackID, compressedURL string) error {
 	m.ctrl.T.Helper()
@@ -528,6 +730,36 @@ func (mr *MockNostrTrackServiceInterfaceMockRecorder) MarkTrackAsProcessed(ctx,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkTrackAsProcessed", reflect.TypeOf((*MockNostrTrackServiceInterface)(nil).MarkTrackAsProcessed), ctx, trackID, size, duration)
 }
 
+// RefreshUploadStatus mocks base method.
+func (m *MockNostrTrackServiceInterface) RefreshUploadStatus(ctx context.Context, trackID string) (*models.NostrTrack, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshUploadStatus", ctx, trackID)
+	ret0, _ := ret[0].(*models.NostrTrack)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshUploadStatus indicates an expected call of RefreshUploadStatus.
+func (mr *MockNostrTrackServiceInterfaceMockRecorder) RefreshUploadStatus(ctx, trackID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshUploadStatus", reflect.TypeOf((*MockNostrTrackServiceInterface)(nil).RefreshUploadStatus), ctx, trackID)
+}
+
+// RenewUpload mocks base method.
+func (m *MockNostrTrackServiceInterface) RenewUpload(ctx context.Context, trackID string) (*models.NostrTrack, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenewUpload", ctx, trackID)
+	ret0, _ := ret[0].(*models.NostrTrack)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenewUpload indicates an expected call of RenewUpload.
+func (mr *MockNostrTrackServiceInterfaceMockRecorder) RenewUpload(ctx, trackID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenewUpload", reflect.TypeOf((*MockNostrTrackServiceInterface)(nil).RenewUpload), ctx, trackID)
+}
+
 // SetPendingCompression mocks base method.
 func (m *MockNostrTrackServiceInterface) SetPendingCompression(ctx context.Context, trackID string, pending bool) error {
 	m.ctrl.T.Helper()
@@ -570,6 +802,274 @@ func (mr *MockNostrTrackServiceInterfaceMockRecorder) UpdateTrack(ctx, trackID,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTrack", reflect.TypeOf((*MockNostrTrackServiceInterface)(nil).UpdateTrack), ctx, trackID, updates)
 }
 
+// MockNostrArtistServiceInterface is a mock of NostrArtistServiceInterface interface.
+type MockNostrArtistServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockNostrArtistServiceInterfaceMockRecorder
+}
+
+// MockNostrArtistServiceInterfaceMockRecorder is the mock recorder for MockNostrArtistServiceInterface.
+type MockNostrArtistServiceInterfaceMockRecorder struct {
+	mock *MockNostrArtistServiceInterface
+}
+
+// NewMockNostrArtistServiceInterface creates a new mock instance.
+func NewMockNostrArtistServiceInterface(ctrl *gomock.Controller) *MockNostrArtistServiceInterface {
+	mock := &MockNostrArtistServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockNostrArtistServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNostrArtistServiceInterface) EXPECT() *MockNostrArtistServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreateArtist mocks base method.
+func (m *MockNostrArtistServiceInterface) CreateArtist(ctx context.Context, artist *models.NostrArtist) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateArtist", ctx, artist)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateArtist indicates an expected call of CreateArtist.
+func (mr *MockNostrArtistServiceInterfaceMockRecorder) CreateArtist(ctx, artist interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateArtist", reflect.TypeOf((*MockNostrArtistServiceInterface)(nil).CreateArtist), ctx, artist)
+}
+
+// DeleteArtist mocks base method.
+func (m *MockNostrArtistServiceInterface) DeleteArtist(ctx context.Context, pubkey string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteArtist", ctx, pubkey)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteArtist indicates an expected call of DeleteArtist.
+func (mr *MockNostrArtistServiceInterfaceMockRecorder) DeleteArtist(ctx, pubkey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteArtist", reflect.TypeOf((*MockNostrArtistServiceInterface)(nil).DeleteArtist), ctx, pubkey)
+}
+
+// GetArtist mocks base method.
+func (m *MockNostrArtistServiceInterface) GetArtist(ctx context.Context, pubkey string) (*models.NostrArtist, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetArtist", ctx, pubkey)
+	ret0, _ := ret[0].(*models.NostrArtist)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetArtist indicates an expected call of GetArtist.
+func (mr *MockNostrArtistServiceInterfaceMockRecorder) GetArtist(ctx, pubkey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetArtist", reflect.TypeOf((*MockNostrArtistServiceInterface)(nil).GetArtist), ctx, pubkey)
+}
+
+// UpdateArtist mocks base method.
+func (m *MockNostrArtistServiceInterface) UpdateArtist(ctx context.Context, pubkey string, updates map[string]interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateArtist", ctx, pubkey, updates)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateArtist indicates an expected call of UpdateArtist.
+func (mr *MockNostrArtistServiceInterfaceMockRecorder) UpdateArtist(ctx, pubkey, updates interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateArtist", reflect.TypeOf((*MockNostrArtistServiceInterface)(nil).UpdateArtist), ctx, pubkey, updates)
+}
+
+// MockNostrAlbumServiceInterface is a mock of NostrAlbumServiceInterface interface.
+type MockNostrAlbumServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockNostrAlbumServiceInterfaceMockRecorder
+}
+
+// MockNostrAlbumServiceInterfaceMockRecorder is the mock recorder for MockNostrAlbumServiceInterface.
+type MockNostrAlbumServiceInterfaceMockRecorder struct {
+	mock *MockNostrAlbumServiceInterface
+}
+
+// NewMockNostrAlbumServiceInterface creates a new mock instance.
+func NewMockNostrAlbumServiceInterface(ctrl *gomock.Controller) *MockNostrAlbumServiceInterface {
+	mock := &MockNostrAlbumServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockNostrAlbumServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNostrAlbumServiceInterface) EXPECT() *MockNostrAlbumServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreateAlbum mocks base method.
+func (m *MockNostrAlbumServiceInterface) CreateAlbum(ctx context.Context, album *models.NostrAlbum) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAlbum", ctx, album)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAlbum indicates an expected call of CreateAlbum.
+func (mr *MockNostrAlbumServiceInterfaceMockRecorder) CreateAlbum(ctx, album interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAlbum", reflect.TypeOf((*MockNostrAlbumServiceInterface)(nil).CreateAlbum), ctx, album)
+}
+
+// DeleteAlbum mocks base method.
+func (m *MockNostrAlbumServiceInterface) DeleteAlbum(ctx context.Context, albumID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAlbum", ctx, albumID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAlbum indicates an expected call of DeleteAlbum.
+func (mr *MockNostrAlbumServiceInterfaceMockRecorder) DeleteAlbum(ctx, albumID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAlbum", reflect.TypeOf((*MockNostrAlbumServiceInterface)(nil).DeleteAlbum), ctx, albumID)
+}
+
+// GetAlbum mocks base method.
+func (m *MockNostrAlbumServiceInterface) GetAlbum(ctx context.Context, albumID string) (*models.NostrAlbum, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAlbum", ctx, albumID)
+	ret0, _ := ret[0].(*models.NostrAlbum)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAlbum indicates an expected call of GetAlbum.
+func (mr *MockNostrAlbumServiceInterfaceMockRecorder) GetAlbum(ctx, albumID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAlbum", reflect.TypeOf((*MockNostrAlbumServiceInterface)(nil).GetAlbum), ctx, albumID)
+}
+
+// GetAlbumsByPubkey mocks base method.
+func (m *MockNostrAlbumServiceInterface) GetAlbumsByPubkey(ctx context.Context, pubkey string) ([]*models.NostrAlbum, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAlbumsByPubkey", ctx, pubkey)
+	ret0, _ := ret[0].([]*models.NostrAlbum)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAlbumsByPubkey indicates an expected call of GetAlbumsByPubkey.
+func (mr *MockNostrAlbumServiceInterfaceMockRecorder) GetAlbumsByPubkey(ctx, pubkey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAlbumsByPubkey", reflect.TypeOf((*MockNostrAlbumServiceInterface)(nil).GetAlbumsByPubkey), ctx, pubkey)
+}
+
+// SetAlbumTracks mocks base method.
+func (m *MockNostrAlbumServiceInterface) SetAlbumTracks(ctx context.Context, albumID string, trackIDs []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAlbumTracks", ctx, albumID, trackIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAlbumTracks indicates an expected call of SetAlbumTracks.
+func (mr *MockNostrAlbumServiceInterfaceMockRecorder) SetAlbumTracks(ctx, albumID, trackIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAlbumTracks", reflect.TypeOf((*MockNostrAlbumServiceInterface)(nil).SetAlbumTracks), ctx, albumID, trackIDs)
+}
+
+// UpdateAlbum mocks base method.
+func (m *MockNostrAlbumServiceInterface) UpdateAlbum(ctx context.Context, albumID string, updates map[string]interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAlbum", ctx, albumID, updates)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAlbum indicates an expected call of UpdateAlbum.
+func (mr *MockNostrAlbumServiceInterfaceMockRecorder) UpdateAlbum(ctx, albumID, updates interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAlbum", reflect.TypeOf((*MockNostrAlbumServiceInterface)(nil).UpdateAlbum), ctx, albumID, updates)
+}
+
+// MockArtworkServiceInterface is a mock of ArtworkServiceInterface interface.
+type MockArtworkServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockArtworkServiceInterfaceMockRecorder
+}
+
+// MockArtworkServiceInterfaceMockRecorder is the mock recorder for MockArtworkServiceInterface.
+type MockArtworkServiceInterfaceMockRecorder struct {
+	mock *MockArtworkServiceInterface
+}
+
+// NewMockArtworkServiceInterface creates a new mock instance.
+func NewMockArtworkServiceInterface(ctrl *gomock.Controller) *MockArtworkServiceInterface {
+	mock := &MockArtworkServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockArtworkServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockArtworkServiceInterface) EXPECT() *MockArtworkServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreateArtworkUpload mocks base method.
+func (m *MockArtworkServiceInterface) CreateArtworkUpload(ctx context.Context, pubkey, firebaseUID, targetType, targetID, extension string) (*models.NostrArtwork, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateArtworkUpload", ctx, pubkey, firebaseUID, targetType, targetID, extension)
+	ret0, _ := ret[0].(*models.NostrArtwork)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateArtworkUpload indicates an expected call of CreateArtworkUpload.
+func (mr *MockArtworkServiceInterfaceMockRecorder) CreateArtworkUpload(ctx, pubkey, firebaseUID, targetType, targetID, extension interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateArtworkUpload", reflect.TypeOf((*MockArtworkServiceInterface)(nil).CreateArtworkUpload), ctx, pubkey, firebaseUID, targetType, targetID, extension)
+}
+
+// GetArtwork mocks base method.
+func (m *MockArtworkServiceInterface) GetArtwork(ctx context.Context, artworkID string) (*models.NostrArtwork, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetArtwork", ctx, artworkID)
+	ret0, _ := ret[0].(*models.NostrArtwork)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetArtwork indicates an expected call of GetArtwork.
+func (mr *MockArtworkServiceInterfaceMockRecorder) GetArtwork(ctx, artworkID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetArtwork", reflect.TypeOf((*MockArtworkServiceInterface)(nil).GetArtwork), ctx, artworkID)
+}
+
+// ProcessArtwork mocks base method.
+func (m *MockArtworkServiceInterface) ProcessArtwork(ctx context.Context, artworkID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessArtwork", ctx, artworkID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProcessArtwork indicates an expected call of ProcessArtwork.
+func (mr *MockArtworkServiceInterfaceMockRecorder) ProcessArtwork(ctx, artworkID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessArtwork", reflect.TypeOf((*MockArtworkServiceInterface)(nil).ProcessArtwork), ctx, artworkID)
+}
+
+// ProcessArtworkAsync mocks base method.
+func (m *MockArtworkServiceInterface) ProcessArtworkAsync(ctx context.Context, artworkID string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ProcessArtworkAsync", ctx, artworkID)
+}
+
+// ProcessArtworkAsync indicates an expected call of ProcessArtworkAsync.
+func (mr *MockArtworkServiceInterfaceMockRecorder) ProcessArtworkAsync(ctx, artworkID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessArtworkAsync", reflect.TypeOf((*MockArtworkServiceInterface)(nil).ProcessArtworkAsync), ctx, artworkID)
+}
+
 // MockProcessingServiceInterface is a mock of ProcessingServiceInterface interface.
 type MockProcessingServiceInterface struct {
 	ctrl     *gomock.Controller
@@ -619,6 +1119,32 @@ func (mr *MockProcessingServiceInterfaceMockRecorder) ProcessCompressionAsync(ct
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessCompressionAsync", reflect.TypeOf((*MockProcessingServiceInterface)(nil).ProcessCompressionAsync), ctx, trackID, option)
 }
 
+// ProcessCompressions mocks base method.
+func (m *MockProcessingServiceInterface) ProcessCompressions(ctx context.Context, trackID string, options []models.CompressionOption) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessCompressions", ctx, trackID, options)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProcessCompressions indicates an expected call of ProcessCompressions.
+func (mr *MockProcessingServiceInterfaceMockRecorder) ProcessCompressions(ctx, trackID, options interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessCompressions", reflect.TypeOf((*MockProcessingServiceInterface)(nil).ProcessCompressions), ctx, trackID, options)
+}
+
+// ProcessCompressionsAsync mocks base method.
+func (m *MockProcessingServiceInterface) ProcessCompressionsAsync(ctx context.Context, trackID string, options []models.CompressionOption) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ProcessCompressionsAsync", ctx, trackID, options)
+}
+
+// ProcessCompressionsAsync indicates an expected call of ProcessCompressionsAsync.
+func (mr *MockProcessingServiceInterfaceMockRecorder) ProcessCompressionsAsync(ctx, trackID, options interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessCompressionsAsync", reflect.TypeOf((*MockProcessingServiceInterface)(nil).ProcessCompressionsAsync), ctx, trackID, options)
+}
+
 // ProcessTrack mocks base method.
 func (m *MockProcessingServiceInterface) ProcessTrack(ctx context.Context, trackID string) error {
 	m.ctrl.T.Helper()
@@ -659,6 +1185,72 @@ func (mr *MockProcessingServiceInterfaceMockRecorder) RequestCompressionVersions
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestCompressionVersions", reflect.TypeOf((*MockProcessingServiceInterface)(nil).RequestCompressionVersions), ctx, trackID, compressionOptions)
 }
 
+// MockFingerprintServiceInterface is a mock of FingerprintServiceInterface interface.
+type MockFingerprintServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockFingerprintServiceInterfaceMockRecorder
+}
+
+// MockFingerprintServiceInterfaceMockRecorder is the mock recorder for MockFingerprintServiceInterface.
+type MockFingerprintServiceInterfaceMockRecorder struct {
+	mock *MockFingerprintServiceInterface
+}
+
+// NewMockFingerprintServiceInterface creates a new mock instance.
+func NewMockFingerprintServiceInterface(ctrl *gomock.Controller) *MockFingerprintServiceInterface {
+	mock := &MockFingerprintServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockFingerprintServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFingerprintServiceInterface) EXPECT() *MockFingerprintServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreateModerationEntry mocks base method.
+func (m *MockFingerprintServiceInterface) CreateModerationEntry(ctx context.Context, entry *models.ModerationEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateModerationEntry", ctx, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateModerationEntry indicates an expected call of CreateModerationEntry.
+func (mr *MockFingerprintServiceInterfaceMockRecorder) CreateModerationEntry(ctx, entry interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateModerationEntry", reflect.TypeOf((*MockFingerprintServiceInterface)(nil).CreateModerationEntry), ctx, entry)
+}
+
+// FindMatches mocks base method.
+func (m *MockFingerprintServiceInterface) FindMatches(ctx context.Context, fingerprint *models.TrackFingerprint, threshold float64) ([]models.FingerprintMatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindMatches", ctx, fingerprint, threshold)
+	ret0, _ := ret[0].([]models.FingerprintMatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindMatches indicates an expected call of FindMatches.
+func (mr *MockFingerprintServiceInterfaceMockRecorder) FindMatches(ctx, fingerprint, threshold interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindMatches", reflect.TypeOf((*MockFingerprintServiceInterface)(nil).FindMatches), ctx, fingerprint, threshold)
+}
+
+// SaveFingerprint mocks base method.
+func (m *MockFingerprintServiceInterface) SaveFingerprint(ctx context.Context, fingerprint *models.TrackFingerprint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveFingerprint", ctx, fingerprint)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveFingerprint indicates an expected call of SaveFingerprint.
+func (mr *MockFingerprintServiceInterfaceMockRecorder) SaveFingerprint(ctx, fingerprint interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveFingerprint", reflect.TypeOf((*MockFingerprintServiceInterface)(nil).SaveFingerprint), ctx, fingerprint)
+}
+
 // MockAudioProcessorInterface is a mock of AudioProcessorInterface interface.
 type MockAudioProcessorInterface struct {
 	ctrl     *gomock.Controller
@@ -776,6 +1368,20 @@ func (mr *MockStoragePathConfigInterfaceMockRecorder) GetCompressedPath(trackID
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCompressedPath", reflect.TypeOf((*MockStoragePathConfigInterface)(nil).GetCompressedPath), trackID)
 }
 
+// GetDerivedFilesPrefix mocks base method.
+func (m *MockStoragePathConfigInterface) GetDerivedFilesPrefix(trackID string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDerivedFilesPrefix", trackID)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetDerivedFilesPrefix indicates an expected call of GetDerivedFilesPrefix.
+func (mr *MockStoragePathConfigInterfaceMockRecorder) GetDerivedFilesPrefix(trackID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDerivedFilesPrefix", reflect.TypeOf((*MockStoragePathConfigInterface)(nil).GetDerivedFilesPrefix), trackID)
+}
+
 // GetOriginalPath mocks base method.
 func (m *MockStoragePathConfigInterface) GetOriginalPath(trackID, extension string) string {
 	m.ctrl.T.Helper()
@@ -951,6 +1557,21 @@ func (mr *MockFileServerServiceInterfaceMockRecorder) DownloadFile(ctx, path int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadFile", reflect.TypeOf((*MockFileServerServiceInterface)(nil).DownloadFile), ctx, path)
 }
 
+// GenerateSignedURL mocks base method.
+func (m *MockFileServerServiceInterface) GenerateSignedURL(ctx context.Context, path string, opts models.SignedURLOptions) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateSignedURL", ctx, path, opts)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateSignedURL indicates an expected call of GenerateSignedURL.
+func (mr *MockFileServerServiceInterfaceMockRecorder) GenerateSignedURL(ctx, path, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateSignedURL", reflect.TypeOf((*MockFileServerServiceInterface)(nil).GenerateSignedURL), ctx, path, opts)
+}
+
 // GenerateUploadToken mocks base method.
 func (m *MockFileServerServiceInterface) GenerateUploadToken(ctx context.Context, path, userID string, expiration time.Duration) (*models.FileUploadToken, error) {
 	m.ctrl.T.Helper()
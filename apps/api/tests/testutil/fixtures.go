@@ -259,6 +259,59 @@ func ValidLegacyAlbumsList() []models.LegacyAlbum {
 	return []models.LegacyAlbum{album1, album2}
 }
 
+// Catalog fixtures
+
+// ValidNostrArtist returns a valid NostrArtist for testing
+func ValidNostrArtist() *models.NostrArtist {
+	return &models.NostrArtist{
+		Pubkey:      TestPubkey,
+		FirebaseUID: TestFirebaseUID,
+		Name:        "Test Artist",
+		Bio:         "Test artist bio",
+		ArtworkURL:  "https://example.com/artist.jpg",
+		CreatedAt:   time.Now().Add(-30 * 24 * time.Hour),
+		UpdatedAt:   time.Now(),
+	}
+}
+
+// ValidNostrAlbum returns a valid published NostrAlbum for testing
+func ValidNostrAlbum() *models.NostrAlbum {
+	return &models.NostrAlbum{
+		ID:          TestAlbumID,
+		Pubkey:      TestPubkey,
+		FirebaseUID: TestFirebaseUID,
+		Title:       "Test Album",
+		Description: "Test album description",
+		ArtworkURL:  "https://example.com/album.jpg",
+		IsDraft:     false,
+		TrackIDs:    []string{TestTrackID, "test-track-456"},
+		PublishedAt: time.Now().Add(-7 * 24 * time.Hour),
+		CreatedAt:   time.Now().Add(-30 * 24 * time.Hour),
+		UpdatedAt:   time.Now(),
+	}
+}
+
+// ValidNostrArtwork returns a processed NostrArtwork linked to the test track
+func ValidNostrArtwork() *models.NostrArtwork {
+	return &models.NostrArtwork{
+		ID:          TestArtworkID,
+		Pubkey:      TestPubkey,
+		FirebaseUID: TestFirebaseUID,
+		TargetType:  "track",
+		TargetID:    TestTrackID,
+		Extension:   "png",
+		OriginalURL: "https://storage.googleapis.com/test-bucket/artwork/original/" + TestArtworkID + ".png",
+		Variants: []models.ArtworkVariant{
+			{Size: 100, Format: "jpg", URL: "https://storage.googleapis.com/test-bucket/artwork/" + TestArtworkID + "/100.jpg"},
+			{Size: 100, Format: "webp", URL: "https://storage.googleapis.com/test-bucket/artwork/" + TestArtworkID + "/100.webp"},
+		},
+		Width:     1000,
+		Height:    1000,
+		CreatedAt: time.Now().Add(-1 * time.Hour),
+		UpdatedAt: time.Now(),
+	}
+}
+
 // Constants for testing
 const (
 	TestFirebaseUID = "test-firebase-uid"
@@ -269,6 +322,7 @@ const (
 	TestExtension   = "mp3"
 	TestArtistID    = "artist-123"
 	TestAlbumID     = "album-123"
+	TestArtworkID   = "artwork-123"
 )
 
 // Additional fixtures for comprehensive testing
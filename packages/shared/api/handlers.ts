@@ -1,5 +1,111 @@
 // Code generated by tygo. DO NOT EDIT.
 
+//////////
+// source: albums.go
+
+export interface AlbumsHandler {
+}
+export interface CreateAlbumRequest {
+  title: string;
+  description: string;
+  artwork_url: string;
+  genre: string;
+  is_single: boolean;
+  track_ids: string[];
+}
+/**
+ * UpdateAlbumRequest only changes the fields that are present in the request.
+ * Setting is_draft to false publishes the album.
+ */
+export interface UpdateAlbumRequest {
+  title?: string;
+  description?: string;
+  artwork_url?: string;
+  genre?: string;
+  is_single?: boolean;
+  is_draft?: boolean;
+}
+export interface SetAlbumTracksRequest {
+  track_ids: string[];
+}
+export interface AlbumResponse {
+  success: boolean;
+  data?: any /* models.NostrAlbum */;
+  error?: string;
+  message?: string;
+}
+export interface AlbumsResponse {
+  success: boolean;
+  data?: (any /* models.NostrAlbum */ | undefined)[];
+  error?: string;
+}
+export interface PublicAlbumResponse {
+  success: boolean;
+  data?: PublicAlbum;
+  error?: string;
+}
+export interface PublicAlbumsResponse {
+  success: boolean;
+  data?: (PublicAlbum | undefined)[];
+  error?: string;
+}
+
+//////////
+// source: artists.go
+
+export interface ArtistsHandler {
+}
+export interface CreateArtistRequest {
+  name: string;
+  bio: string;
+  artwork_url: string;
+  website: string;
+  twitter: string;
+  instagram: string;
+  youtube: string;
+}
+/**
+ * UpdateArtistRequest only changes the fields that are present in the request
+ */
+export interface UpdateArtistRequest {
+  name?: string;
+  bio?: string;
+  artwork_url?: string;
+  website?: string;
+  twitter?: string;
+  instagram?: string;
+  youtube?: string;
+  default_ladder?: string[]; // Compression presets generated on upload, empty for the platform default
+}
+export interface ArtistResponse {
+  success: boolean;
+  data?: any /* models.NostrArtist */;
+  error?: string;
+  message?: string;
+}
+export interface PublicArtistResponse {
+  success: boolean;
+  data?: PublicArtist;
+  error?: string;
+}
+
+//////////
+// source: artwork.go
+
+export interface ArtworkHandler {
+}
+export interface CreateArtworkUploadRequest {
+  target_type: string; // "track" or "album"
+  target_id: string;
+  extension: string;
+}
+export interface ArtworkResponse {
+  success: boolean;
+  data?: any /* models.NostrArtwork */;
+  error?: string;
+  message?: string;
+}
+
 //////////
 // source: auth.go
 
@@ -121,6 +227,45 @@ export interface RefreshTokenRequest {
   expiration: number /* int */; // New expiration in minutes
 }
 
+//////////
+// source: catalog_views.go
+
+/**
+ * PublicArtist is the view of an artist profile served to anyone. It omits the creator's
+ * Firebase UID and owner settings such as the default compression ladder; the owner's
+ * endpoints return the full models.NostrArtist.
+ */
+export interface PublicArtist {
+  pubkey: string;
+  name: string;
+  bio?: string;
+  artwork_url?: string;
+  website?: string;
+  twitter?: string;
+  instagram?: string;
+  youtube?: string;
+  created_at: string;
+  updated_at: string;
+}
+/**
+ * PublicAlbum is the view of a published album served to anyone. It omits the creator's
+ * Firebase UID and the draft and deleted flags, which are always false for public albums.
+ */
+export interface PublicAlbum {
+  id: string;
+  pubkey: string;
+  title: string;
+  description?: string;
+  artwork_url?: string;
+  artwork_variants?: any /* models.ArtworkVariant */[];
+  genre?: string;
+  is_single: boolean;
+  track_ids: string[];
+  published_at?: string;
+  created_at: string;
+  updated_at: string;
+}
+
 //////////
 // source: development_handler.go
 
@@ -242,6 +387,44 @@ export interface UserMetadataResponse {
   albums: any /* models.LegacyAlbum */[];
   tracks: any /* models.LegacyTrack */[];
 }
+/**
+ * LegacyTracksResponse is one page of GET /v1/legacy/tracks, in the envelope of the other list endpoints
+ */
+export interface LegacyTracksResponse {
+  success: boolean;
+  data?: any /* models.LegacyTrack */[];
+  next_cursor?: string;
+  error?: string;
+}
+/**
+ * LegacyArtistsResponse is one page of GET /v1/legacy/artists
+ */
+export interface LegacyArtistsResponse {
+  success: boolean;
+  data?: any /* models.LegacyArtist */[];
+  next_cursor?: string;
+  error?: string;
+}
+/**
+ * LegacyAlbumsResponse is one page of GET /v1/legacy/albums
+ */
+export interface LegacyAlbumsResponse {
+  success: boolean;
+  data?: any /* models.LegacyAlbum */[];
+  next_cursor?: string;
+  error?: string;
+}
+
+//////////
+// source: legacy_migration_handler.go
+
+export interface LegacyMigrationHandler {
+}
+export interface LegacyMigrationResponse {
+  success: boolean;
+  data?: any /* models.LegacyMigrationReport */;
+  error?: string;
+}
 
 //////////
 // source: mock_storage_handler.go
@@ -355,6 +538,115 @@ export interface CreateTrackRequest {
   priceMsat: number /* int64 */;
 }
 
+//////////
+// source: stream.go
+
+/**
+ * Stream delivery modes
+ */
+/**
+ * StreamModeProxy streams the object through the API with Range support
+ */
+export const StreamModeProxy = "proxy";
+/**
+ * StreamModeRedirect redirects the listener to a short-lived signed storage URL
+ */
+export const StreamModeRedirect = "redirect";
+export interface StreamHandler {
+}
+/**
+ * DownloadURLResponse carries a signed URL for an owner download or preview
+ */
+export interface DownloadURLResponse {
+  success: boolean;
+  data?: DownloadURLData;
+  error?: string;
+}
+export interface DownloadURLData {
+  url: string;
+  expires_at: string;
+}
+
+//////////
+// source: track_views.go
+
+/**
+ * PublicCompressionVersion is a published compressed version of a track
+ */
+export interface PublicCompressionVersion {
+  id: string;
+  url: string; // Stream path on this API, see streamURL
+  bitrate: number /* int */;
+  format: string;
+  quality: string;
+  sample_rate: number /* int */;
+  size: number /* int64 */;
+  renditions?: number /* int */[]; // HLS rendition bitrates; URL is the master playlist
+  preset?: string; // Compression preset the version was generated from
+}
+/**
+ * PublicTrack is the view of a track served to anyone. It omits the uploader's
+ * Firebase UID, the original upload URL and versions the owner has not published.
+ */
+export interface PublicTrack {
+  id: string;
+  pubkey: string;
+  title?: string;
+  lyrics?: string;
+  is_explicit?: boolean;
+  duration?: number /* int */;
+  loudness?: any /* models.LoudnessInfo */; // Lets players normalize playback
+  compression_versions: PublicCompressionVersion[];
+  nostr_kind?: number /* int */;
+  nostr_d_tag?: string;
+  artwork_url?: string;
+  artwork_variants?: any /* models.ArtworkVariant */[];
+  waveform_url?: string;
+  waveforms?: any /* models.WaveformVariant */[];
+  created_at: string;
+  updated_at: string;
+}
+/**
+ * OwnerTrack is the view of a track served to the pubkey that owns it. It includes
+ * processing state, the original upload and every compression version.
+ */
+export interface OwnerTrack {
+  id: string;
+  pubkey: string;
+  title?: string;
+  lyrics?: string;
+  is_explicit?: boolean;
+  original_url: string;
+  presigned_url?: string;
+  upload_headers?: { [key: string]: string};
+  upload_state?: string;
+  upload_session?: any /* models.UploadSession */;
+  upload_expires_at?: string;
+  extension: string;
+  size?: number /* int64 */;
+  duration?: number /* int */;
+  is_processing: boolean;
+  compression_versions?: any /* models.CompressionVersion */[];
+  has_pending_compression: boolean;
+  deleted: boolean;
+  nostr_kind?: number /* int */;
+  nostr_d_tag?: string;
+  artwork_url?: string;
+  artwork_variants?: any /* models.ArtworkVariant */[];
+  legacy_track_id?: string;
+  loudness?: any /* models.LoudnessInfo */;
+  file_hash?: string;
+  duplicate_of?: string;
+  error?: string;
+  rejection_reasons?: string[];
+  waveform_url?: string;
+  waveforms?: any /* models.WaveformVariant */[];
+  created_at: string;
+  updated_at: string;
+  compressed_url?: string;
+  is_compressed: boolean;
+}
+
 //////////
 // source: tracks.go
 
@@ -362,18 +654,35 @@ export interface TracksHandler {
 }
 export interface CreateNostrTrackRequest {
   extension: string;
+  resumable?: boolean; // Open a resumable upload session instead of a one-shot URL
+  size?: number /* int64 */; // Declared upload size in bytes, checked against the upload limit
 }
 export interface CreateTrackResponse {
   success: boolean;
-  data?: any /* models.NostrTrack */;
+  data?: OwnerTrack;
   error?: string;
   message?: string;
 }
 export interface GetTracksResponse {
   success: boolean;
-  data?: (any /* models.NostrTrack */ | undefined)[];
+  data?: (OwnerTrack | undefined)[];
+  next_cursor?: string;
   error?: string;
 }
+export interface PublicTracksResponse {
+  success: boolean;
+  data?: (PublicTrack | undefined)[];
+  next_cursor?: string;
+  error?: string;
+}
+/**
+ * UploadStatus is the progress of a track's original upload
+ */
+export interface UploadStatus {
+  track_id: string;
+  upload_state?: string;
+  upload_session?: any /* models.UploadSession */;
+}
 
 //////////
 // source: webhook_handler.go
@@ -63,9 +63,37 @@ export interface LinkedPubkeyInfo {
  */
 export interface CompressionOption {
   bitrate: number /* int */; // e.g., 128, 256, 320
-  format: string; // e.g., "mp3", "aac", "ogg"
+  format: string; // "mp3", "aac" (raw ADTS), "m4a" (AAC in MP4), "ogg", "opus", "webm" (Opus in WebM), "flac" or "hls"
   quality: string; // e.g., "low", "medium", "high"
   sample_rate?: number /* int */; // e.g., 44100, 48000
+  renditions?: number /* int */[]; // HLS only: AAC rendition bitrates in kbps
+  loudnorm?: LoudnessTarget; // Two-pass EBU R128 normalization target, nil to keep source loudness
+  preset?: string; // Named preset from config; when requested, the preset's options replace the others
+}
+/**
+ * LoudnessTarget is an EBU R128 normalization target for ffmpeg's loudnorm filter. Unset
+ * values take the platform default; pointers keep an explicit 0 dBTP ceiling apart from unset.
+ */
+export interface LoudnessTarget {
+  integrated_lufs?: number /* float64 */; // Target integrated loudness, e.g. -14
+  true_peak?: number /* float64 */; // Maximum true peak in dBTP, e.g. -1
+  lra?: number /* float64 */; // Target loudness range in LU, e.g. 11
+}
+/**
+ * WaveformVariant is audiowaveform-compatible JSON peak data at one zoom level
+ */
+export interface WaveformVariant {
+  samples_per_pixel: number /* int */;
+  url: string;
+}
+/**
+ * LoudnessInfo is the result of an EBU R128 loudness analysis
+ */
+export interface LoudnessInfo {
+  integrated_lufs: number /* float64 */; // Integrated loudness in LUFS
+  true_peak: number /* float64 */; // True peak in dBTP
+  lra: number /* float64 */; // Loudness range in LU
+  threshold: number /* float64 */; // Gating threshold in LUFS, needed for a second loudnorm pass
 }
 /**
  * CompressionVersion represents a generated compressed version
@@ -81,22 +109,65 @@ export interface CompressionVersion {
   is_public: boolean; // Whether to include in Nostr event
   created_at: string;
   options: CompressionOption; // Original compression request
+  renditions?: number /* int */[]; // HLS only: packaged rendition bitrates
+  file_hash?: string; // Hex SHA-256 of the output file
+  object_key?: string; // Storage object of the file, or the master playlist for HLS
+  object_prefix?: string; // HLS only: prefix of every object in the package
+}
+/**
+ * Upload states of a track original
+ */
+export const UploadStatePending = "pending"; // Upload URL issued, no bytes confirmed yet
+export const UploadStateUploading = "uploading"; // Resumable session has committed some bytes
+export const UploadStateComplete = "complete"; // Original is stored
+export const UploadStateExpired = "upload_expired"; // Upload URL or session expired before the original arrived
+/**
+ * UploadSession is a resumable upload session for a track original. The client sends
+ * chunks to URL with Content-Range headers and resumes from Offset after an interruption.
+ */
+export interface UploadSession {
+  url: string; // Session URL, a bearer capability for the upload
+  content_type: string; // Content-Type the session was started with
+  size?: number /* int64 */; // Declared total size in bytes, 0 when unknown
+  offset: number /* int64 */; // Bytes committed as of the last status check
+  expires_at: string; // When storage discards the session
 }
 export interface NostrTrack {
   id: string; // UUID
   firebase_uid: string; // User who uploaded
   pubkey: string; // Nostr pubkey
+  title?: string; // Track title
+  lyrics?: string; // Lyrics text
+  is_explicit?: boolean; // Explicit content flag
   original_url: string; // GCS URL for original file
+  original_object_key?: string; // Storage object of the original file
   presigned_url?: string; // Temporary upload URL (not stored)
+  upload_headers?: { [key: string]: string}; // Headers the upload request must send (not stored)
+  upload_state?: string; // UploadState* value, empty for tracks created before upload tracking
+  upload_session?: UploadSession; // Resumable upload of the original, nil for one-shot uploads
+  upload_expires_at?: string; // When the one-shot upload URL expires
   extension: string; // File extension
   size?: number /* int64 */; // Original file size in bytes
   duration?: number /* int */; // Duration in seconds
   is_processing: boolean; // Processing status
   compression_versions?: CompressionVersion[]; // All compressed versions
+  has_public_versions: boolean; // Whether any compression version is public, kept in sync for queries
+  formats?: string[]; // Lowercase extension of the original and every version format, kept in sync for queries
   has_pending_compression: boolean; // Whether compression is queued
   deleted: boolean; // Soft delete flag
+  deleted_at?: string; // When the track was soft deleted
   nostr_kind?: number /* int */; // Nostr event kind
   nostr_d_tag?: string; // Nostr d tag
+  artwork_url?: string; // Primary artwork variant
+  artwork_variants?: ArtworkVariant[]; // All resized artwork variants
+  legacy_track_id?: string; // Source track when migrated from the legacy catalog
+  loudness?: LoudnessInfo; // EBU R128 analysis of the original
+  file_hash?: string; // Hex SHA-256 of the original upload
+  duplicate_of?: string; // Track by another pubkey with the same original
+  error?: string; // Why processing failed
+  rejection_reasons?: string[]; // Upload policy violations of the original
+  waveform_url?: string; // Default-resolution peak data for the web player
+  waveforms?: WaveformVariant[]; // Peak data at every generated resolution
   created_at: string;
   updated_at: string;
   /**
@@ -105,6 +176,221 @@ export interface NostrTrack {
   compressed_url?: string; // Legacy compressed file
   is_compressed: boolean; // Legacy compression status
 }
+/**
+ * LegacyTrackMapping records which new track a legacy catalog track was migrated to.
+ * Documents are keyed by the legacy track ID so a track is only migrated once.
+ */
+export interface LegacyTrackMapping {
+  legacy_track_id: string;
+  track_id: string;
+  pubkey: string;
+  firebase_uid: string;
+  migrated_at: string;
+}
+/**
+ * LegacyMigrationResult is the outcome of migrating a single legacy track
+ */
+export interface LegacyMigrationResult {
+  legacy_track_id: string;
+  title: string;
+  status: string; // "migrated", "would_migrate", "skipped", "failed"
+  track_id?: string;
+  message?: string;
+}
+/**
+ * LegacyMigrationReport summarizes a legacy catalog migration run
+ */
+export interface LegacyMigrationReport {
+  firebase_uid: string;
+  pubkey: string;
+  dry_run: boolean;
+  migrated: number /* int */;
+  skipped: number /* int */;
+  failed: number /* int */;
+  results: LegacyMigrationResult[];
+}
+/**
+ * ObjectKeyBackfillResult is the outcome of backfilling one track's storage object keys
+ */
+export interface ObjectKeyBackfillResult {
+  track_id: string;
+  status: string; // "updated", "would_update", "skipped", "failed"
+  original_object_key?: string;
+  version_keys?: number /* int */; // Versions given a key
+  version_fields?: boolean; // has_public_versions and formats written
+  message?: string;
+}
+/**
+ * ObjectKeyBackfillReport summarizes an object key backfill run
+ */
+export interface ObjectKeyBackfillReport {
+  dry_run: boolean;
+  updated: number /* int */;
+  skipped: number /* int */;
+  failed: number /* int */;
+  results: ObjectKeyBackfillResult[];
+}
+/**
+ * TrackPurgeResult is the outcome of hard deleting one soft-deleted track
+ */
+export interface TrackPurgeResult {
+  track_id: string;
+  deleted_at: string;
+  status: string; // "purged", "would_purge", "failed"
+  failed_files?: string[]; // Objects or prefixes that could not be deleted
+  message?: string;
+}
+/**
+ * TrackPurgeReport summarizes a purge of tracks soft deleted before Cutoff
+ */
+export interface TrackPurgeReport {
+  cutoff: string;
+  dry_run: boolean;
+  purged: number /* int */;
+  failed: number /* int */;
+  results: TrackPurgeResult[];
+}
+/**
+ * StorageOrphan is a stored object under a track prefix whose track no longer exists
+ */
+export interface StorageOrphan {
+  object_name: string;
+  track_id?: string; // Track ID parsed from the object name
+  size: number /* int64 */;
+  created_at: string;
+  status: string; // "deleted", "would_delete", "in_grace_period", "failed"
+  message?: string;
+}
+/**
+ * AbandonedTrack is a track record whose original was never uploaded
+ */
+export interface AbandonedTrack {
+  track_id: string;
+  pubkey: string;
+  upload_state?: string;
+  created_at: string;
+  status: string; // "deleted", "would_delete", "failed"
+  message?: string;
+}
+/**
+ * StorageGCReport summarizes a storage garbage collection run. Only orphans and abandoned
+ * tracks older than Cutoff are deleted.
+ */
+export interface StorageGCReport {
+  cutoff: string;
+  dry_run: boolean;
+  tracks_scanned: number /* int */;
+  objects_scanned: number /* int */;
+  deleted: number /* int */;
+  failed: number /* int */;
+  orphans: StorageOrphan[];
+  abandoned_tracks: AbandonedTrack[];
+}
+/**
+ * NostrArtist is an artist profile in the new catalog, keyed by the owning Nostr pubkey
+ */
+export interface NostrArtist {
+  pubkey: string; // Primary key, owner of the profile
+  firebase_uid: string; // User who created the profile
+  name: string;
+  bio?: string;
+  artwork_url?: string;
+  website?: string;
+  twitter?: string;
+  instagram?: string;
+  youtube?: string;
+  default_ladder?: string[]; // Compression presets generated on upload, empty for the platform default
+  deleted: boolean; // Soft delete flag
+  created_at: string;
+  updated_at: string;
+}
+/**
+ * NostrAlbum groups an artist's tracks in a fixed order
+ */
+export interface NostrAlbum {
+  id: string; // UUID
+  pubkey: string; // Owning artist pubkey
+  firebase_uid: string; // User who created the album
+  title: string;
+  description?: string;
+  artwork_url?: string;
+  artwork_variants?: ArtworkVariant[];
+  genre?: string;
+  is_single: boolean;
+  is_draft: boolean; // Drafts are only visible to the owner
+  track_ids: string[]; // Track order within the album
+  deleted: boolean; // Soft delete flag
+  published_at?: string;
+  created_at: string;
+  updated_at: string;
+}
+/**
+ * ArtworkVariant is a square, resized copy of an uploaded artwork image
+ */
+export interface ArtworkVariant {
+  size: number /* int */; // Edge length in pixels
+  format: string; // "jpg" or "webp"
+  url: string;
+}
+/**
+ * NostrArtwork tracks an artwork upload and the variants generated from it
+ */
+export interface NostrArtwork {
+  id: string; // UUID
+  pubkey: string; // Uploader pubkey
+  firebase_uid: string; // User who uploaded
+  target_type: string; // "track" or "album"
+  target_id: string; // Track or album the artwork is linked to
+  extension: string; // Uploaded file extension
+  original_url: string; // URL of the unprocessed upload
+  presigned_url?: string; // Temporary upload URL (not stored)
+  upload_headers?: { [key: string]: string}; // Headers the upload request must send (not stored)
+  is_processing: boolean; // Processing status
+  variants?: ArtworkVariant[]; // Generated variants
+  width?: number /* int */; // Source image width
+  height?: number /* int */; // Source image height
+  error?: string; // Processing failure reason
+  created_at: string;
+  updated_at: string;
+}
+/**
+ * TrackFingerprint is the acoustic fingerprint of a track's original, keyed by track ID
+ */
+export interface TrackFingerprint {
+  track_id: string;
+  pubkey: string; // Track owner
+  duration: number /* float64 */; // Seconds of audio fingerprinted
+  fingerprint: number /* int64 */[]; // Chromaprint raw sub-fingerprints
+  index_keys: number /* int64 */[]; // Coarse keys for candidate lookup
+  created_at: string;
+}
+/**
+ * FingerprintMatch is an existing track whose fingerprint is close to a new upload's
+ */
+export interface FingerprintMatch {
+  track_id: string;
+  pubkey: string;
+  similarity: number /* float64 */; // 0-1, one minus the bit error rate
+}
+/**
+ * Moderation queue entry types and statuses
+ */
+export const ModerationTypeFingerprintMatch = "fingerprint_match";
+export const ModerationStatusPending = "pending";
+/**
+ * ModerationEntry is an item awaiting review in the moderation queue
+ */
+export interface ModerationEntry {
+  id: string; // UUID
+  type: string; // e.g. "fingerprint_match"
+  status: string; // "pending" until reviewed
+  track_id: string; // The new upload
+  pubkey: string; // Owner of the new upload
+  matched_track_id: string; // Existing track it resembles
+  matched_pubkey: string; // Owner of the existing track
+  similarity: number /* float64 */;
+  created_at: string;
+}
 /**
  * VersionUpdate represents a request to update compression version visibility
  */
@@ -181,6 +467,63 @@ export interface LegacyAlbum {
   created_at: string;
   updated_at: string;
 }
+/**
+ * ListOptions controls cursor pagination and ordering for list endpoints
+ */
+export interface ListOptions {
+  Limit: number /* int */; // Maximum number of items to return
+  Cursor: string; // Opaque next_cursor from the previous page
+  SortBy: string; // Field to sort by, e.g. "created_at"
+  SortOrder: string; // "asc" or "desc"
+}
+/**
+ * TrackFilter narrows track listings. Nil fields are not filtered on.
+ */
+export interface TrackFilter {
+  IsProcessing?: boolean;
+  Deleted?: boolean; // Defaults to excluding deleted tracks
+  HasPublicVersions?: boolean;
+  Format: string; // Original file extension or compressed version format
+}
+/**
+ * NostrTrackPage is one page of tracks and the cursor for the next page
+ */
+export interface NostrTrackPage {
+  Tracks: (NostrTrack | undefined)[];
+  NextCursor: string;
+}
+/**
+ * LegacyTrackPage is one page of legacy tracks and the cursor for the next page
+ */
+export interface LegacyTrackPage {
+  Tracks: LegacyTrack[];
+  NextCursor: string;
+}
+/**
+ * LegacyArtistPage is one page of legacy artists and the cursor for the next page
+ */
+export interface LegacyArtistPage {
+  Artists: LegacyArtist[];
+  NextCursor: string;
+}
+/**
+ * LegacyAlbumPage is one page of legacy albums and the cursor for the next page
+ */
+export interface LegacyAlbumPage {
+  Albums: LegacyAlbum[];
+  NextCursor: string;
+}
+/**
+ * SignedURLOptions controls a signed storage URL
+ */
+export interface SignedURLOptions {
+  Method: string; // HTTP method the URL is valid for, defaults to GET
+  Expiration: any /* time.Duration */; // How long the URL stays valid
+  ContentDisposition: string; // Overrides the response Content-Disposition, e.g. `attachment; filename="song.mp3"`
+  MaxContentLength: number /* int64 */; // Uploads only: largest accepted upload in bytes, 0 for no limit
+  Resumable: boolean; // The URL starts a resumable upload session; implies POST
+  ContentType: string; // Resumable only: Content-Type of the uploaded object
+}
 /**
  * FileUploadToken represents a token for file upload authentication
  */
@@ -200,6 +543,7 @@ export interface FileMetadata {
   content_type: string;
   bucket: string;
   url?: string;
+  etag?: string;
   metadata?: { [key: string]: string};
   created_at: string;
   updated_at: string;
@@ -257,6 +601,8 @@ export interface ProcessingStatus {
   error?: string;
   started_at?: string;
   completed_at?: string;
+  duplicate_of?: string; // Track by another pubkey with the same original file
+  rejection_reasons?: string[]; // Upload policy violations of the original
 }
 /**
  * AudioMetadata represents metadata extracted from audio files